@@ -5,11 +5,13 @@ import (
 	"fmt"
 	"log/slog"
 	"os"
+	"strings"
 	"time"
 
 	cliPkg "github.com/glide-cli/glide/v3/internal/cli"
 	"github.com/glide-cli/glide/v3/internal/config"
 	"github.com/glide-cli/glide/v3/internal/context"
+	"github.com/glide-cli/glide/v3/internal/shell"
 	"github.com/glide-cli/glide/v3/pkg/branding"
 	glideErrors "github.com/glide-cli/glide/v3/pkg/errors"
 	"github.com/glide-cli/glide/v3/pkg/logging"
@@ -20,10 +22,18 @@ import (
 	"github.com/spf13/cobra"
 )
 
+// pluginShutdownTimeout bounds how long plugins may take to shut down
+const pluginShutdownTimeout = 5 * time.Second
+
 var (
 	// CLI flags
-	cfgFile   string
-	debugMode bool
+	cfgFile      string
+	debugMode    bool
+	strictDetect bool
+	profileMode  bool
+	noCache      bool
+	gitDirty     bool
+	detectMode   string
 
 	// Global output flags
 	outputFormat string
@@ -37,6 +47,11 @@ var (
 
 func main() {
 	if err := Execute(); err != nil {
+		// Machine consumers get a parseable error object in JSON mode
+		if format, _ := output.ParseFormat(outputFormat); format == output.FormatJSON {
+			os.Exit(glideErrors.PrintJSON(err))
+		}
+
 		// Use the new error handler for consistent error display
 		os.Exit(glideErrors.Print(err))
 	}
@@ -60,16 +75,42 @@ func Execute() error {
 	// Start background update check if enabled
 	startUpdateCheck(cfg)
 
-	// Get list of registered plugins for context detection
+	// Go plugins (.so) in the global plugin directory join the built-in plugins
+	sharedResult := plugin.LoadSharedPlugins(branding.GetGlobalPluginDir())
+
+	// Plugins disabled in the config neither load nor run detection
+	if cfg != nil {
+		plugin.SetDisabled(cfg.Plugins.Disabled)
+	}
+
+	// External plugin executables declared in the config run as subprocesses
+	var externalResult *plugin.PluginLoadResult
+	if cfg != nil && len(cfg.Plugins.External) > 0 {
+		externalResult = plugin.LoadExternalPlugins(stdcontext.Background(), cfg.Plugins.External)
+	}
+
+	// Get list of enabled plugins for context detection
 	// We pass them as interface{} to avoid import cycles
-	pluginList := plugin.List()
+	pluginList := plugin.Enabled()
 	extensionProviders := make([]interface{}, len(pluginList))
 	for i, p := range pluginList {
 		extensionProviders[i] = p
 	}
 
 	// Detect project context with plugin extensions
-	ctx := context.DetectWithExtensions(extensionProviders)
+	var detectOpts []context.DetectOption
+	if noCacheRequested(os.Args[1:]) {
+		detectOpts = append(detectOpts, context.WithoutCache())
+	}
+	if mode, err := context.ParseDetectionMode(detectionModeRequested(os.Args[1:], cfg)); err != nil {
+		logging.Warn("Ignoring detection mode", "error", err)
+	} else {
+		detectOpts = append(detectOpts, context.WithDetectionMode(mode))
+	}
+	if flagRequested(os.Args[1:], "--git-dirty") || (cfg != nil && cfg.Defaults.Detection.GitDirty) {
+		detectOpts = append(detectOpts, context.WithGitDirtyCheck())
+	}
+	ctx := context.DetectWithExtensions(extensionProviders, detectOpts...)
 
 	// Create output manager directly
 	outputManager := output.NewManager(
@@ -82,6 +123,10 @@ func Execute() error {
 	// Set global manager for backward compatibility during migration
 	output.SetGlobalManager(outputManager)
 
+	// Aggregates command timings when --profile is set
+	profiler := shell.NewTimingRecorder()
+	profiling := false
+
 	// Create root command
 	rootCmd := &cobra.Command{
 		Use:                   branding.CommandName,
@@ -115,7 +160,18 @@ func Execute() error {
 			outputManager.SetQuiet(quietMode)
 			outputManager.SetNoColor(noColor)
 
-			return nil
+			// Time the commands YAML commands run; only once, as this also
+			// runs for glide commands YAML commands dispatch in-process
+			if profileMode && !profiling {
+				profiling = true
+				cliPkg.SetYAMLCommandExecutor(shell.NewTimingExecutor(cliPkg.YAMLCommandExecutor(), profiler))
+			}
+
+			// Fail before running the command if detection errored in strict mode
+			if !cmd.Flags().Changed("strict-detect") && os.Getenv("GLIDE_STRICT_DETECT") != "" {
+				strictDetect = true
+			}
+			return cliPkg.ValidateDetection(ctx, strictDetect)
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if len(args) == 0 {
@@ -130,9 +186,16 @@ func Execute() error {
 	// Global flags
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", fmt.Sprintf("config file (default is $HOME/%s)", branding.ConfigFileName))
 	rootCmd.PersistentFlags().BoolVar(&debugMode, "debug", false, "Enable debug logging (equivalent to GLIDE_LOG_LEVEL=debug)")
-	rootCmd.PersistentFlags().StringVar(&outputFormat, "format", "table", "Output format (table, json, yaml, plain)")
+	rootCmd.PersistentFlags().StringVar(&outputFormat, "format", "table", "Output format (table, json, yaml, plain); also accepted as --output")
+	rootCmd.PersistentFlags().StringVar(&outputFormat, "output", "table", "Alias of --format")
+	_ = rootCmd.PersistentFlags().MarkHidden("output")
 	rootCmd.PersistentFlags().BoolVarP(&quietMode, "quiet", "q", false, "Suppress non-error output")
 	rootCmd.PersistentFlags().BoolVar(&noColor, "no-color", false, "Disable colored output")
+	rootCmd.PersistentFlags().BoolVar(&profileMode, "profile", false, "Print a timing report of executed commands when finished")
+	rootCmd.PersistentFlags().BoolVar(&strictDetect, "strict-detect", false, "Treat project context detection errors as fatal (or set GLIDE_STRICT_DETECT)")
+	rootCmd.PersistentFlags().StringVar(&detectMode, "detect-mode", "", "Project context detection mode: fast, standard or full (or set GLIDE_DETECT_MODE)")
+	rootCmd.PersistentFlags().BoolVar(&gitDirty, "git-dirty", false, "Detect uncommitted changes in the project context (or set defaults.detection.git_dirty)")
+	rootCmd.PersistentFlags().BoolVar(&noCache, "no-cache", false, "Detect project context from scratch instead of reusing cached results (or set GLIDE_NO_CACHE)")
 
 	// Initialize CLI with dependencies
 	cli := cliPkg.New(outputManager, ctx, cfg)
@@ -166,14 +229,26 @@ func Execute() error {
 	// Set standard context for cancellation/deadline support
 	rootCmd.SetContext(stdcontext.Background())
 
-	// Load all registered build-time plugins
+	// Load all enabled build-time plugins, honoring plugins.order.
+	// Each plugin logs through a child of the root logger tagged with its name.
+	if cfg != nil {
+		plugin.SetLoadOrder(cfg.Plugins.Order)
+	}
+	plugin.SetLogger(logging.Default())
 	result, err := plugin.LoadAll(rootCmd)
+	defer shutdownPlugins()
 	if err != nil {
 		// Fatal error during plugin loading
 		return fmt.Errorf("failed to load build-time plugins: %w", err)
 	}
 
 	// Report non-fatal plugin errors to user
+	if sharedResult.HasErrors() && !quietMode {
+		fmt.Fprintf(os.Stderr, "%s\n", sharedResult.ErrorMessage())
+	}
+	if externalResult != nil && externalResult.HasErrors() && !quietMode {
+		fmt.Fprintf(os.Stderr, "%s\n", externalResult.ErrorMessage())
+	}
 	if result != nil && result.HasErrors() && !quietMode {
 		fmt.Fprintf(os.Stderr, "%s\n", result.ErrorMessage())
 	}
@@ -199,6 +274,11 @@ func Execute() error {
 	// Execute root command
 	cmdErr := rootCmd.Execute()
 
+	// Show the command timing report on stderr so it doesn't mix with command output
+	if profileMode {
+		fmt.Fprint(os.Stderr, profiler.Report())
+	}
+
 	// Show update notification after command completes (if not in quiet mode)
 	if !quietMode {
 		showUpdateNotification(cfg)
@@ -207,6 +287,60 @@ func Execute() error {
 	return cmdErr
 }
 
+// noCacheRequested reports whether --no-cache was passed or GLIDE_NO_CACHE is
+// set. Context detection runs before cobra parses flags, so the arguments
+// are scanned directly.
+func noCacheRequested(args []string) bool {
+	return os.Getenv("GLIDE_NO_CACHE") != "" || flagRequested(args, "--no-cache")
+}
+
+// detectionModeRequested returns the detection mode from --detect-mode,
+// GLIDE_DETECT_MODE or the config, in that order
+func detectionModeRequested(args []string, cfg *config.Config) string {
+	for i, arg := range args {
+		if arg == "--" {
+			break
+		}
+		if value, ok := strings.CutPrefix(arg, "--detect-mode="); ok {
+			return value
+		}
+		if arg == "--detect-mode" && i+1 < len(args) {
+			return args[i+1]
+		}
+	}
+	if mode := os.Getenv("GLIDE_DETECT_MODE"); mode != "" {
+		return mode
+	}
+	if cfg != nil {
+		return cfg.Defaults.Detection.Mode
+	}
+	return ""
+}
+
+// flagRequested reports whether the boolean flag was passed in args, before
+// any "--"
+func flagRequested(args []string, flag string) bool {
+	for _, arg := range args {
+		if arg == "--" {
+			break
+		}
+		if arg == flag || arg == flag+"=true" {
+			return true
+		}
+	}
+	return false
+}
+
+// shutdownPlugins lets build-time plugins release their resources before exit
+func shutdownPlugins() {
+	ctx, cancel := stdcontext.WithTimeout(stdcontext.Background(), pluginShutdownTimeout)
+	defer cancel()
+
+	if err := plugin.ShutdownAll(ctx); err != nil {
+		logging.Warn("Plugin shutdown failed", "error", err)
+	}
+}
+
 // startUpdateCheck initializes the update notification manager and starts background check
 func startUpdateCheck(cfg *config.Config) {
 	// Check if updates are disabled via config
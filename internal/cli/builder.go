@@ -105,6 +105,30 @@ func (b *Builder) registerCommands() {
 		Description: "Context-aware help and guidance",
 	})
 
+	b.registry.Register("wait", func() *cobra.Command {
+		return NewWaitCommand()
+	}, Metadata{
+		Name:        "wait",
+		Category:    CategoryCore,
+		Description: "Wait for a TCP port, HTTP endpoint or file to become ready",
+	})
+
+	b.registry.Register("shellenv", func() *cobra.Command {
+		return NewShellEnvCommand()
+	}, Metadata{
+		Name:        "shellenv",
+		Category:    CategorySetup,
+		Description: "Print shell aliases and environment provided by plugins",
+	})
+
+	b.registry.Register("validate", func() *cobra.Command {
+		return NewValidateCommand()
+	}, Metadata{
+		Name:        "validate",
+		Category:    CategorySetup,
+		Description: "Check configuration and plugins without running anything",
+	})
+
 	// Project-specific commands have been moved to glide-plugin-chirocat
 	// Docker commands: up, down, status, logs, shell
 	// Developer commands: test, artisan, composer, lint
@@ -149,14 +173,29 @@ func (b *Builder) Build() *cobra.Command {
 // addDebugCommands adds debug commands to the root command
 func (b *Builder) addDebugCommands(rootCmd *cobra.Command) {
 	// Context debug command
-	rootCmd.AddCommand(&cobra.Command{
+	contextCmd := &cobra.Command{
 		Use:          "context",
 		Short:        "Show detected project context (debug)",
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if asJSON, _ := cmd.Flags().GetBool("json"); asJSON {
+				return printContext(cmd, output.FormatJSON, b.projectContext)
+			}
+			if export, _ := cmd.Flags().GetBool("export"); export {
+				return exportContext(cmd, b.projectContext)
+			}
+			if validate, _ := cmd.Flags().GetBool("validate"); validate {
+				return validateContextExtensions(cmd, b.projectContext)
+			}
+			if format, ok := contextOutputFormat(cmd); ok {
+				return printContext(cmd, format, b.projectContext)
+			}
 			return showContext(cmd, b.outputManager, b.projectContext)
 		},
-	})
+	}
+	addContextFlags(contextCmd)
+	contextCmd.AddCommand(newContextDiffCommand())
+	rootCmd.AddCommand(contextCmd)
 
 	// Shell test command
 	rootCmd.AddCommand(&cobra.Command{
@@ -204,6 +243,11 @@ func (b *Builder) GetRegistry() *Registry {
 func (b *Builder) loadYAMLCommands() {
 	// 1. Core commands are already registered (highest priority)
 
+	// Steps with a relative workdir run below the project root
+	if b.projectContext != nil {
+		SetYAMLProjectRoot(b.projectContext.ProjectRoot)
+	}
+
 	// 2. Discover and load all .glide.yml files up the tree
 	cwd, _ := os.Getwd()
 	configPaths, err := config.DiscoverConfigs(cwd)
@@ -262,8 +306,8 @@ func (b *Builder) loadYAMLCommands() {
 func isProtectedCommand(name string) bool {
 	protected := []string{
 		"help", "setup", "plugins", "plugin", "self-update",
-		"update", "upgrade", "version", "completion", "global",
-		"config", "context", "shell-test", "docker-test", "container-test",
+		"update", "upgrade", "version", "completion", "global", "wait",
+		"shellenv", "validate", "config", "context", "shell-test", "docker-test", "container-test",
 	}
 	for _, p := range protected {
 		if name == p {
@@ -97,15 +97,30 @@ func (c *CLI) AddLocalCommands(cmd *cobra.Command) {
 // addDebugCommands adds debug-only commands
 func (c *CLI) addDebugCommands(cmd *cobra.Command) {
 	// Add context debug command
-	cmd.AddCommand(&cobra.Command{
+	contextCmd := &cobra.Command{
 		Use:          "context",
 		Short:        "Show detected project context (debug)",
 		SilenceUsage: true,
 		Hidden:       true, // Hide debug commands
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if asJSON, _ := cmd.Flags().GetBool("json"); asJSON {
+				return printContext(cmd, output.FormatJSON, c.projectContext)
+			}
+			if export, _ := cmd.Flags().GetBool("export"); export {
+				return exportContext(cmd, c.projectContext)
+			}
+			if validate, _ := cmd.Flags().GetBool("validate"); validate {
+				return validateContextExtensions(cmd, c.projectContext)
+			}
+			if format, ok := contextOutputFormat(cmd); ok {
+				return printContext(cmd, format, c.projectContext)
+			}
 			return c.showContext(cmd)
 		},
-	})
+	}
+	addContextFlags(contextCmd)
+	contextCmd.AddCommand(newContextDiffCommand())
+	cmd.AddCommand(contextCmd)
 
 	// Add shell test command (debug)
 	cmd.AddCommand(&cobra.Command{
@@ -154,6 +169,16 @@ func (c *CLI) showContext(cmd *cobra.Command) error {
 	cmd.Printf("Project Root: %s\n", ctx.ProjectRoot)
 	cmd.Printf("Development Mode: %s\n", ctx.DevelopmentMode)
 	cmd.Printf("Location: %s\n", ctx.Location)
+	if ctx.GitBranch != "" {
+		if ctx.GitDetached {
+			cmd.Printf("Git HEAD: %s (detached)\n", ctx.GitBranch)
+		} else {
+			cmd.Printf("Git Branch: %s\n", ctx.GitBranch)
+		}
+	}
+	if ctx.GitCommit != "" {
+		cmd.Printf("Git Commit: %s\n", ctx.GitCommit)
+	}
 
 	if ctx.DevelopmentMode == context.ModeMultiWorktree {
 		cmd.Printf("Is Root: %v\n", ctx.IsRoot)
@@ -165,6 +190,9 @@ func (c *CLI) showContext(cmd *cobra.Command) error {
 	}
 
 	cmd.Printf("\nDocker Running: %v\n", ctx.DockerRunning)
+	if ctx.DockerContext != "" {
+		cmd.Printf("Docker Context: %s\n", ctx.DockerContext)
+	}
 
 	if len(ctx.ComposeFiles) > 0 {
 		cmd.Println("\nCompose Files:")
@@ -2,7 +2,10 @@ package cli
 
 import (
 	"bytes"
+	"encoding/json"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/glide-cli/glide/v3/internal/config"
@@ -11,6 +14,7 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
 )
 
 func TestNewCLI(t *testing.T) {
@@ -359,3 +363,148 @@ func TestCLIIntegration(t *testing.T) {
 		assert.Contains(t, outputStr, "/test/project")
 	})
 }
+
+func TestContextCommand_Export(t *testing.T) {
+	buf := &bytes.Buffer{}
+	ctx := &context.ProjectContext{
+		WorkingDir:      "/test/project",
+		ProjectRoot:     "/test/project",
+		DevelopmentMode: context.ModeSingleRepo,
+		Location:        context.LocationProject,
+		Extensions: map[string]interface{}{
+			"docker": map[string]interface{}{
+				"compose_files": []string{"docker-compose.yml"},
+			},
+			"registry": map[string]interface{}{
+				"api_token": "s3cr3t",
+			},
+		},
+	}
+
+	outputMgr := output.NewManager(output.FormatTable, false, false, buf)
+	cli := New(outputMgr, ctx, &config.Config{})
+
+	rootCmd := &cobra.Command{Use: "glide"}
+	cli.AddLocalCommands(rootCmd)
+	rootCmd.SetOut(buf)
+	rootCmd.SetArgs([]string{"context", "--export"})
+
+	require.NoError(t, rootCmd.Execute())
+
+	out := buf.String()
+	assert.Contains(t, out, "export GLIDE_PROJECT_ROOT='/test/project'\n")
+	assert.Contains(t, out, "export GLIDE_EXT_DOCKER_COMPOSE_FILES='docker-compose.yml'\n")
+	assert.Contains(t, out, "export GLIDE_EXT_REGISTRY_API_TOKEN='"+context.RedactedValue+"'\n")
+	assert.NotContains(t, out, "s3cr3t")
+	assert.NotContains(t, out, "Project Context")
+}
+
+func TestContextCommand_JSON(t *testing.T) {
+	ctx := &context.ProjectContext{
+		WorkingDir:      "/test/project",
+		ProjectRoot:     "/test/project",
+		DevelopmentMode: context.ModeSingleRepo,
+		Location:        context.LocationProject,
+		GitBranch:       "main",
+		Extensions: map[string]interface{}{
+			"docker": map[string]interface{}{
+				"env": map[string]interface{}{"DB_PASSWORD": "hunter2"},
+			},
+		},
+	}
+
+	run := func(args ...string) string {
+		buf := &bytes.Buffer{}
+		outputMgr := output.NewManager(output.FormatTable, false, false, buf)
+		cli := New(outputMgr, ctx, &config.Config{})
+
+		rootCmd := &cobra.Command{Use: "glide"}
+		rootCmd.PersistentFlags().String("format", "table", "Output format")
+		cli.AddLocalCommands(rootCmd)
+		rootCmd.SetOut(buf)
+		rootCmd.SetArgs(args)
+		require.NoError(t, rootCmd.Execute())
+		return buf.String()
+	}
+
+	for _, args := range [][]string{{"context", "--json"}, {"--format", "json", "context"}} {
+		t.Run(strings.Join(args, " "), func(t *testing.T) {
+			out := run(args...)
+
+			var decoded map[string]interface{}
+			require.NoError(t, json.Unmarshal([]byte(out), &decoded))
+			assert.Equal(t, "/test/project", decoded["project_root"])
+			assert.Equal(t, map[string]interface{}{"branch": "main"}, decoded["git"])
+			assert.NotContains(t, out, "hunter2")
+		})
+	}
+
+	t.Run("--format yaml", func(t *testing.T) {
+		out := run("--format", "yaml", "context")
+
+		var decoded map[string]interface{}
+		require.NoError(t, yaml.Unmarshal([]byte(out), &decoded))
+		assert.Equal(t, "/test/project", decoded["project_root"])
+		assert.NotContains(t, out, "hunter2")
+	})
+}
+
+func TestContextCommand_Diff(t *testing.T) {
+	dir := t.TempDir()
+	fileA := filepath.Join(dir, "a.json")
+	fileB := filepath.Join(dir, "b.json")
+	require.NoError(t, os.WriteFile(fileA, []byte(`{"ProjectRoot":"/ci","Extensions":{"docker":{"compose_files":"docker-compose.yml"}}}`), 0644))
+	require.NoError(t, os.WriteFile(fileB, []byte(`{"ProjectRoot":"/local","Extensions":{"docker":{"compose_files":["docker-compose.yml"]}}}`), 0644))
+
+	run := func(args ...string) string {
+		buf := &bytes.Buffer{}
+		outputMgr := output.NewManager(output.FormatTable, false, false, buf)
+		cli := New(outputMgr, &context.ProjectContext{}, &config.Config{})
+
+		rootCmd := &cobra.Command{Use: "glide"}
+		rootCmd.PersistentFlags().String("format", "table", "Output format")
+		cli.AddLocalCommands(rootCmd)
+		rootCmd.SetOut(buf)
+		rootCmd.SetArgs(append([]string{"context", "diff", fileA, fileB}, args...))
+		require.NoError(t, rootCmd.Execute())
+		return buf.String()
+	}
+
+	t.Run("human readable", func(t *testing.T) {
+		out := run()
+		assert.Contains(t, out, `~ ProjectRoot: "/ci" -> "/local"`)
+		assert.Contains(t, out, "! Extensions.docker.compose_files: type changed from string to list")
+	})
+
+	t.Run("json", func(t *testing.T) {
+		var entries []context.DiffEntry
+		require.NoError(t, json.Unmarshal([]byte(run("--json")), &entries))
+		require.Len(t, entries, 2)
+		assert.Equal(t, context.DiffTypeChanged, entries[0].Kind)
+		assert.Equal(t, "Extensions.docker.compose_files", entries[0].Path)
+		assert.Equal(t, context.DiffChanged, entries[1].Kind)
+	})
+
+	t.Run("global format", func(t *testing.T) {
+		var entries []map[string]interface{}
+		require.NoError(t, yaml.Unmarshal([]byte(run("--format", "yaml")), &entries))
+		require.Len(t, entries, 2)
+		assert.Equal(t, "type_changed", entries[0]["kind"])
+		assert.Equal(t, "Extensions.docker.compose_files", entries[0]["path"])
+	})
+}
+
+func TestContextCommand_Validate(t *testing.T) {
+	buf := &bytes.Buffer{}
+	outputMgr := output.NewManager(output.FormatTable, false, false, buf)
+	cli := New(outputMgr, &context.ProjectContext{ProjectRoot: t.TempDir()}, &config.Config{})
+
+	rootCmd := &cobra.Command{Use: "glide"}
+	cli.AddLocalCommands(rootCmd)
+	rootCmd.SetOut(buf)
+	rootCmd.SetArgs([]string{"context", "--validate"})
+	require.NoError(t, rootCmd.Execute())
+
+	// No registered plugin declares an extension data schema
+	assert.Contains(t, buf.String(), "No context extensions declare a data schema")
+}
@@ -2,7 +2,9 @@ package cli
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"strings"
 	"time"
 
@@ -10,6 +12,8 @@ import (
 	"github.com/glide-cli/glide/v3/internal/docker"
 	"github.com/glide-cli/glide/v3/internal/shell"
 	"github.com/glide-cli/glide/v3/pkg/output"
+	"github.com/glide-cli/glide/v3/pkg/plugin"
+	"github.com/glide-cli/glide/v3/pkg/plugin/sdk"
 	"github.com/glide-cli/glide/v3/pkg/progress"
 	"github.com/spf13/cobra"
 )
@@ -28,6 +32,16 @@ func showContext(_ *cobra.Command, outputManager *output.Manager, projectContext
 	_ = outputManager.Info("Project Root: %s", ctx.ProjectRoot)
 	_ = outputManager.Info("Development Mode: %s", ctx.DevelopmentMode)
 	_ = outputManager.Info("Location: %s", ctx.Location)
+	if ctx.GitBranch != "" {
+		if ctx.GitDetached {
+			_ = outputManager.Info("Git HEAD: %s (detached)", ctx.GitBranch)
+		} else {
+			_ = outputManager.Info("Git Branch: %s", ctx.GitBranch)
+		}
+	}
+	if ctx.GitCommit != "" {
+		_ = outputManager.Info("Git Commit: %s", ctx.GitCommit)
+	}
 
 	if ctx.DevelopmentMode == glideContext.ModeMultiWorktree {
 		_ = outputManager.Info("")
@@ -40,6 +54,9 @@ func showContext(_ *cobra.Command, outputManager *output.Manager, projectContext
 
 	_ = outputManager.Info("")
 	_ = outputManager.Info("Docker Running: %v", ctx.DockerRunning)
+	if ctx.DockerContext != "" {
+		_ = outputManager.Info("Docker Context: %s", ctx.DockerContext)
+	}
 	if len(ctx.ComposeFiles) > 0 {
 		_ = outputManager.Info("Compose Files: %s", strings.Join(ctx.ComposeFiles, ", "))
 	}
@@ -47,6 +64,194 @@ func showContext(_ *cobra.Command, outputManager *output.Manager, projectContext
 	return nil
 }
 
+// addContextFlags adds the flags shared by the context debug command variants
+func addContextFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool("export", false, "Print the context as shell export statements (eval \"$(glide context --export)\")")
+	cmd.Flags().Bool("validate", false, "Check each extension's detected data against the schema it declares")
+	cmd.Flags().Bool("json", false, "Print the context as JSON, with sensitive extension values redacted (same as --format json)")
+}
+
+// validateContextExtensions re-runs detection for every plugin extension that
+// declares a data schema and reports where the output drifted from it
+func validateContextExtensions(cmd *cobra.Command, projectContext *glideContext.ProjectContext) error {
+	if projectContext == nil {
+		return fmt.Errorf("no project context available")
+	}
+
+	plugins := plugin.List()
+	providers := make([]interface{}, len(plugins))
+	for i, p := range plugins {
+		providers[i] = p
+	}
+
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	results := glideContext.ValidateExtensions(ctx, providers, projectContext.ProjectRoot)
+
+	out := cmd.OutOrStdout()
+	if len(results) == 0 {
+		fmt.Fprintln(out, "No context extensions declare a data schema")
+		return nil
+	}
+
+	failed := 0
+	for _, result := range results {
+		switch {
+		case result.DetectErr != nil:
+			failed++
+			fmt.Fprintf(out, "✗ %s (%s): detection failed: %v\n", result.Extension, result.Plugin, result.DetectErr)
+		case !result.Detected:
+			fmt.Fprintf(out, "- %s (%s): not detected in this project\n", result.Extension, result.Plugin)
+		case len(result.Errors) > 0:
+			failed++
+			fmt.Fprintf(out, "✗ %s (%s): %d schema mismatch(es)\n", result.Extension, result.Plugin, len(result.Errors))
+			for _, verr := range result.Errors {
+				fmt.Fprintf(out, "    %s\n", verr.Error())
+			}
+		default:
+			fmt.Fprintf(out, "✓ %s (%s): matches schema\n", result.Extension, result.Plugin)
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d context extension(s) do not match their declared schema", failed)
+	}
+	return nil
+}
+
+// exportContext prints the project context as shell export statements with
+// sensitive extension values redacted
+func exportContext(cmd *cobra.Command, projectContext *glideContext.ProjectContext) error {
+	if projectContext == nil {
+		return fmt.Errorf("no project context available")
+	}
+
+	for _, line := range projectContext.Redacted().ShellExports() {
+		fmt.Fprintln(cmd.OutOrStdout(), line)
+	}
+	return nil
+}
+
+// contextOutputFormat returns the structured format the context debug
+// commands should print in: JSON for their --json flag, otherwise the global
+// --format if it selects JSON or YAML. It reports false for the
+// human-readable output.
+func contextOutputFormat(cmd *cobra.Command) (output.Format, bool) {
+	if asJSON, _ := cmd.Flags().GetBool("json"); asJSON {
+		return output.FormatJSON, true
+	}
+	switch format := sdk.OutputFormat(cmd); format {
+	case output.FormatJSON, output.FormatYAML:
+		return format, true
+	}
+	return "", false
+}
+
+// printContext writes the context in format with sensitive extension values
+// redacted
+func printContext(cmd *cobra.Command, format output.Format, projectContext *glideContext.ProjectContext) error {
+	if projectContext == nil {
+		return fmt.Errorf("no project context available")
+	}
+	return writeStructured(cmd.OutOrStdout(), format, projectContext.Redacted())
+}
+
+// writeStructured writes data with the shared JSON or YAML formatter. Data is
+// normalized to its JSON form first, so YAML output uses the same keys.
+func writeStructured(w io.Writer, format output.Format, data interface{}) error {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	var generic interface{}
+	if err := json.Unmarshal(encoded, &generic); err != nil {
+		return err
+	}
+
+	formatter, err := output.CreateFormatter(format, w, true, false)
+	if err != nil {
+		return err
+	}
+	return formatter.Display(generic)
+}
+
+// newContextDiffCommand creates the `context diff` subcommand
+func newContextDiffCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "diff <file-a.json> <file-b.json>",
+		Short: "Compare two serialized project contexts",
+		Long: `Compare two project contexts saved as JSON and list added, removed and
+changed keys, including nested extension data. The global --format json or
+yaml prints the diff as structured data; --json is short for --format json.
+
+Examples:
+  glide context --json > local-context.json
+  glide context diff ci-context.json local-context.json
+  glide context diff a.json b.json --json`,
+		Args:         cobra.ExactArgs(2),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			a, err := glideContext.LoadContextFile(args[0])
+			if err != nil {
+				return err
+			}
+			b, err := glideContext.LoadContextFile(args[1])
+			if err != nil {
+				return err
+			}
+
+			format, _ := contextOutputFormat(cmd)
+			return printContextDiff(cmd.OutOrStdout(), glideContext.DiffMaps(a, b), format)
+		},
+	}
+
+	cmd.Flags().Bool("json", false, "Output the diff as JSON (same as --format json)")
+
+	return cmd
+}
+
+// printContextDiff writes diff entries in a structured format, or as
+// human-readable lines if format is empty
+func printContextDiff(w io.Writer, entries []glideContext.DiffEntry, format output.Format) error {
+	if format != "" {
+		if entries == nil {
+			entries = []glideContext.DiffEntry{}
+		}
+		return writeStructured(w, format, entries)
+	}
+
+	if len(entries) == 0 {
+		fmt.Fprintln(w, "No differences")
+		return nil
+	}
+
+	for _, entry := range entries {
+		switch entry.Kind {
+		case glideContext.DiffAdded:
+			fmt.Fprintf(w, "+ %s: %s\n", entry.Path, formatDiffValue(entry.New))
+		case glideContext.DiffRemoved:
+			fmt.Fprintf(w, "- %s: %s\n", entry.Path, formatDiffValue(entry.Old))
+		case glideContext.DiffChanged:
+			fmt.Fprintf(w, "~ %s: %s -> %s\n", entry.Path, formatDiffValue(entry.Old), formatDiffValue(entry.New))
+		case glideContext.DiffTypeChanged:
+			fmt.Fprintf(w, "! %s: type changed from %s to %s: %s -> %s\n", entry.Path,
+				entry.OldType, entry.NewType, formatDiffValue(entry.Old), formatDiffValue(entry.New))
+		}
+	}
+	return nil
+}
+
+// formatDiffValue renders a value compactly as JSON
+func formatDiffValue(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(data)
+}
+
 // showConfig displays the current configuration
 // func showConfig(cmd *cobra.Command, app *app.Application) {
 // 	output := app.OutputManager
@@ -1,6 +1,7 @@
 package cli
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/glide-cli/glide/v3/internal/config"
@@ -71,6 +72,36 @@ func ValidateSingleRepoMode(ctx *context.ProjectContext, commandName string) err
 	return nil
 }
 
+// ValidateDetection enforces strict context detection.
+// In lenient mode (the default) detection errors are ignored and commands run
+// against whatever partial context was detected. In strict mode any detection
+// error is returned so the command is not run.
+func ValidateDetection(ctx *context.ProjectContext, strict bool) error {
+	if !strict {
+		return nil
+	}
+
+	if ctx == nil {
+		return glideErrors.NewConfigError("project context detection failed: no context available",
+			glideErrors.WithSuggestions("Run without --strict-detect to continue without a project context"),
+		)
+	}
+
+	if ctx.Error != nil {
+		return glideErrors.NewConfigError(
+			fmt.Sprintf("project context detection failed: %v", ctx.Error),
+			glideErrors.WithError(ctx.Error),
+			glideErrors.WithContext("working_dir", ctx.WorkingDir),
+			glideErrors.WithSuggestions(
+				"Run the command from inside a Glide project",
+				"Run without --strict-detect to continue with a partial context",
+			),
+		)
+	}
+
+	return nil
+}
+
 // ShowAvailableCommands shows commands available for current mode
 func ShowAvailableCommands(mode context.DevelopmentMode) {
 	output.Println()
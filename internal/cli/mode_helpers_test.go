@@ -5,6 +5,7 @@ import (
 
 	"github.com/glide-cli/glide/v3/internal/config"
 	"github.com/glide-cli/glide/v3/internal/context"
+	glideErrors "github.com/glide-cli/glide/v3/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -301,3 +302,32 @@ func TestShowContextAwareHelp(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateDetection(t *testing.T) {
+	detectionErr := context.ErrProjectRootNotFound
+
+	t.Run("lenient ignores detection errors", func(t *testing.T) {
+		ctx := &context.ProjectContext{WorkingDir: "/tmp", Error: detectionErr}
+		assert.NoError(t, ValidateDetection(ctx, false))
+		assert.NoError(t, ValidateDetection(nil, false))
+	})
+
+	t.Run("strict passes a valid context", func(t *testing.T) {
+		ctx := &context.ProjectContext{WorkingDir: "/project", ProjectRoot: "/project"}
+		assert.NoError(t, ValidateDetection(ctx, true))
+	})
+
+	t.Run("strict fails on detection error", func(t *testing.T) {
+		ctx := &context.ProjectContext{WorkingDir: "/tmp", Error: detectionErr}
+		err := ValidateDetection(ctx, true)
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, detectionErr)
+		assert.True(t, glideErrors.Is(err, glideErrors.TypeConfig))
+		assert.Contains(t, err.Error(), "could not find project root")
+	})
+
+	t.Run("strict fails without context", func(t *testing.T) {
+		assert.Error(t, ValidateDetection(nil, true))
+	})
+}
@@ -49,6 +49,9 @@ func TestCommandTreeBuilding(t *testing.T) {
 			"version",
 			"help",
 			"self-update",
+			"wait",
+			"shellenv",
+			"validate",
 		}
 
 		for _, cmdName := range expectedCommands {
@@ -160,8 +160,14 @@ func (r *Registry) AddYAMLCommand(name string, cmd *config.Command) error {
 			Short: cmd.Description,
 			Long:  cmd.Help,
 			RunE: func(c *cobra.Command, args []string) error {
+				// Ask before running commands marked with confirm
+				args, err := confirmYAMLCommand(cmd.Confirm, args)
+				if err != nil {
+					return err
+				}
+
 				// Execute the YAML-defined command
-				return ExecuteYAMLCommand(cmd.Cmd, args)
+				return runYAMLCommand(c.Root(), cmd, args)
 			},
 		}
 
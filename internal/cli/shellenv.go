@@ -0,0 +1,77 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/glide-cli/glide/v3/pkg/branding"
+	"github.com/glide-cli/glide/v3/pkg/logging"
+	"github.com/glide-cli/glide/v3/pkg/plugin"
+	"github.com/glide-cli/glide/v3/pkg/plugin/sdk"
+	"github.com/spf13/cobra"
+)
+
+// ShellEnvCommand handles the shellenv command
+type ShellEnvCommand struct {
+	registry *plugin.Registry
+}
+
+// NewShellEnvCommand creates the shellenv command
+func NewShellEnvCommand() *cobra.Command {
+	sc := &ShellEnvCommand{
+		registry: plugin.GetGlobalRegistry(),
+	}
+	return sc.command()
+}
+
+// command builds the cobra command for this ShellEnvCommand
+func (sc *ShellEnvCommand) command() *cobra.Command {
+	return &cobra.Command{
+		Use:   "shellenv",
+		Short: "Print shell aliases and environment provided by plugins",
+		Long: fmt.Sprintf(`Print shell aliases and environment variables contributed by plugins.
+
+The output is POSIX shell and is meant to be evaluated by your shell.
+Each plugin's contribution is preceded by a comment naming the plugin.
+Plugins opt in by implementing sdk.ShellEnvProvider.
+
+Examples:
+  eval "$(%[1]s shellenv)"            # Apply to the current shell
+  echo 'eval "$(%[1]s shellenv)"' >> ~/.bashrc`, branding.CommandName),
+		Args:          cobra.NoArgs,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return sc.execute(cmd.OutOrStdout())
+		},
+	}
+}
+
+// execute writes the shell snippets of every enabled providing plugin to w
+func (sc *ShellEnvCommand) execute(w io.Writer) error {
+	fmt.Fprintf(w, "# Generated by '%s shellenv'\n", branding.CommandName)
+
+	for _, p := range sc.registry.Enabled() {
+		name := p.Name()
+		provider, ok := p.(sdk.ShellEnvProvider)
+		if !ok {
+			continue
+		}
+
+		env := provider.ProvideShellEnv()
+		if env.IsEmpty() {
+			continue
+		}
+
+		// Skip the whole plugin rather than emit a partial or unsafe snippet
+		snippet, err := env.Render()
+		if err != nil {
+			logging.Warn("Skipping plugin shell environment", "plugin", name, "error", err)
+			continue
+		}
+
+		fmt.Fprintf(w, "\n# Provided by plugin: %s\n%s", name, snippet)
+	}
+
+	return nil
+}
@@ -0,0 +1,54 @@
+package cli
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/glide-cli/glide/v3/pkg/plugin"
+	"github.com/glide-cli/glide/v3/pkg/plugin/plugintest"
+	"github.com/glide-cli/glide/v3/pkg/plugin/sdk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// shellEnvPlugin is a mock plugin that contributes shell setup
+type shellEnvPlugin struct {
+	*plugintest.MockPlugin
+	env *sdk.ShellEnv
+}
+
+func (p *shellEnvPlugin) ProvideShellEnv() *sdk.ShellEnv {
+	return p.env
+}
+
+func TestShellEnvCommand(t *testing.T) {
+	reg := plugin.NewRegistry()
+	require.NoError(t, reg.RegisterPlugin(&shellEnvPlugin{
+		MockPlugin: plugintest.NewMockPlugin("docker"),
+		env:        &sdk.ShellEnv{Aliases: map[string]string{"dc": "glide docker"}},
+	}))
+	require.NoError(t, reg.RegisterPlugin(&shellEnvPlugin{
+		MockPlugin: plugintest.NewMockPlugin("broken"),
+		env:        &sdk.ShellEnv{Aliases: map[string]string{"a;b": "x"}},
+	}))
+	require.NoError(t, reg.RegisterPlugin(plugintest.NewMockPlugin("plain")))
+	require.NoError(t, reg.RegisterPlugin(&shellEnvPlugin{
+		MockPlugin: plugintest.NewMockPlugin("off"),
+		env:        &sdk.ShellEnv{Aliases: map[string]string{"offalias": "glide off"}},
+	}))
+	reg.SetDisabled([]string{"off"})
+
+	sc := &ShellEnvCommand{registry: reg}
+	cmd := sc.command()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{})
+
+	require.NoError(t, cmd.Execute())
+
+	output := out.String()
+	assert.Contains(t, output, "# Provided by plugin: docker\nalias dc='glide docker'\n")
+	assert.NotContains(t, output, "broken")
+	assert.NotContains(t, output, "plain")
+	assert.NotContains(t, output, "offalias", "disabled plugins contribute nothing")
+}
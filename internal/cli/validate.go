@@ -0,0 +1,269 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/glide-cli/glide/v3/internal/config"
+	"github.com/glide-cli/glide/v3/pkg/branding"
+	pkgconfig "github.com/glide-cli/glide/v3/pkg/config"
+	glideErrors "github.com/glide-cli/glide/v3/pkg/errors"
+	"github.com/glide-cli/glide/v3/pkg/plugin"
+	"github.com/glide-cli/glide/v3/pkg/plugin/sdk"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// ValidateCommand handles the validate command
+type ValidateCommand struct {
+	registry        *plugin.Registry
+	loadConfig      func() (*config.Config, error)
+	discoverConfigs func() ([]string, error)
+}
+
+// NewValidateCommand creates the validate command
+func NewValidateCommand() *cobra.Command {
+	vc := &ValidateCommand{
+		registry:        plugin.GetGlobalRegistry(),
+		loadConfig:      config.Load,
+		discoverConfigs: discoverProjectConfigs,
+	}
+	return vc.command()
+}
+
+// command builds the cobra command for this ValidateCommand
+func (vc *ValidateCommand) command() *cobra.Command {
+	return &cobra.Command{
+		Use:   "validate",
+		Short: "Check configuration and plugins without running anything",
+		Long: fmt.Sprintf(`Check the configuration and plugin setup and report every problem at once.
+
+The following checks are run:
+  - The layered configuration loads and its commands are valid
+  - Each project %[2]s up the directory tree parses and its commands are valid
+  - Each plugin's configuration matches its schema
+  - Plugin dependencies are present and satisfy their version constraints
+  - The plugin dependency graph has no cycles
+  - Each plugin's self-check passes
+
+No commands are executed. The exit code is non-zero if any check fails.
+
+Examples:
+  %[1]s validate
+  %[1]s validate && %[1]s deploy`, branding.CommandName, branding.ConfigFileName),
+		Args:          cobra.NoArgs,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return vc.execute(cmd.OutOrStdout())
+		},
+	}
+}
+
+// validationReport collects check outcomes and prints them as it goes
+type validationReport struct {
+	w      io.Writer
+	passed int
+	failed int
+}
+
+func (r *validationReport) section(title string) {
+	if r.passed+r.failed > 0 {
+		fmt.Fprintln(r.w)
+	}
+	fmt.Fprintln(r.w, title)
+}
+
+func (r *validationReport) check(name string, err error) {
+	if err != nil {
+		r.failed++
+		fmt.Fprintf(r.w, "  ✗ %s: %v\n", name, err)
+		return
+	}
+	r.passed++
+	fmt.Fprintf(r.w, "  ✓ %s\n", name)
+}
+
+func (r *validationReport) skip(name, reason string) {
+	fmt.Fprintf(r.w, "  - %s: %s\n", name, reason)
+}
+
+// execute runs every check and writes the report to w
+func (vc *ValidateCommand) execute(w io.Writer) error {
+	report := &validationReport{w: w}
+
+	report.section("Configuration")
+	cfg, err := vc.loadConfig()
+	report.check("configuration loads", err)
+	if err == nil && len(cfg.Commands) > 0 {
+		report.check("global commands", validateCommands(cfg.Commands))
+	}
+
+	report.section("Project configuration")
+	vc.checkProjectConfigs(report)
+
+	report.section("Plugin configuration")
+	vc.checkPluginConfigs(report)
+
+	report.section("Plugin dependencies")
+	vc.checkDependencies(report)
+
+	report.section("Plugin self-checks")
+	vc.checkSelfChecks(report)
+
+	fmt.Fprintf(w, "\n%d passed, %d failed\n", report.passed, report.failed)
+
+	if report.failed > 0 {
+		return glideErrors.NewConfigError(
+			fmt.Sprintf("validation found %d problem(s)", report.failed),
+			glideErrors.WithSuggestions("Fix the problems marked with ✗ above and run validate again"),
+		)
+	}
+	return nil
+}
+
+// discoverProjectConfigs finds the project config files the builder loads commands from
+func discoverProjectConfigs() ([]string, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+	return config.DiscoverConfigs(cwd)
+}
+
+// checkProjectConfigs parses every discovered project config file and validates
+// its commands. Each file is checked on its own because the builder silently
+// skips files that fail to load.
+func (vc *ValidateCommand) checkProjectConfigs(report *validationReport) {
+	paths, err := vc.discoverConfigs()
+	if err != nil {
+		report.check("project configs", err)
+		return
+	}
+
+	if len(paths) == 0 {
+		report.skip("project configs", "no "+branding.ConfigFileName+" found")
+		return
+	}
+
+	for _, path := range paths {
+		report.check(path, validateProjectConfig(path))
+	}
+}
+
+// validateProjectConfig loads a single project config file the way
+// config.LoadAndMergeConfigs does and validates its commands
+func validateProjectConfig(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var cfg config.Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return err
+	}
+
+	return validateCommands(cfg.Commands)
+}
+
+// validateCommands parses the given commands and reports every invalid one
+func validateCommands(raw config.CommandMap) error {
+	commands, err := config.ParseCommands(raw)
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var errs []error
+	for _, name := range names {
+		if err := config.ValidateCommand(commands[name]); err != nil {
+			errs = append(errs, fmt.Errorf("command %s: %w", name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// checkPluginConfigs validates every registered typed config against its schema
+func (vc *ValidateCommand) checkPluginConfigs(report *validationReport) {
+	names := pkgconfig.List()
+	sort.Strings(names)
+
+	if len(names) == 0 {
+		report.skip("plugins", "no plugin declares a configuration")
+		return
+	}
+
+	for _, name := range names {
+		report.check(name, pkgconfig.Validate(name))
+	}
+}
+
+// checkDependencies checks each enabled plugin's declared dependencies and, once they
+// are all satisfied, that the dependency graph can be ordered
+func (vc *ValidateCommand) checkDependencies(report *validationReport) {
+	enabled := vc.registry.Enabled()
+	names := make([]string, 0, len(enabled))
+	available := make(map[string]sdk.PluginMetadata, len(enabled))
+	for _, p := range enabled {
+		name := p.Name()
+		names = append(names, name)
+		meta := p.Metadata()
+		available[name] = sdk.PluginMetadata{
+			Name:         name,
+			Version:      p.Version(),
+			Dependencies: meta.Dependencies,
+		}
+	}
+
+	if len(available) == 0 {
+		report.skip("plugins", "no plugins registered")
+		return
+	}
+
+	resolver := sdk.NewDependencyResolver()
+	satisfied := true
+	for _, name := range names {
+		err := resolver.ValidatePluginDependencies(available[name], available)
+		if err != nil {
+			satisfied = false
+		}
+		report.check(name, err)
+	}
+
+	// Cycles can only be detected reliably once every dependency resolves
+	if !satisfied {
+		report.skip("dependency graph", "skipped until the dependencies above are fixed")
+		return
+	}
+
+	_, err := resolver.Resolve(available)
+	report.check("dependency graph", err)
+}
+
+// checkSelfChecks runs the Validate check of every enabled plugin that has one
+func (vc *ValidateCommand) checkSelfChecks(report *validationReport) {
+	enabled := vc.registry.Enabled()
+	if len(enabled) == 0 {
+		report.skip("plugins", "no plugins registered")
+		return
+	}
+
+	for _, p := range enabled {
+		name := p.Name()
+		validatable, ok := p.(plugin.Validatable)
+		if !ok {
+			report.skip(name, "no self-check")
+			continue
+		}
+		report.check(name, validatable.Validate())
+	}
+}
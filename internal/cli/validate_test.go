@@ -0,0 +1,157 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/glide-cli/glide/v3/internal/config"
+	"github.com/glide-cli/glide/v3/pkg/plugin"
+	"github.com/glide-cli/glide/v3/pkg/plugin/plugintest"
+	"github.com/glide-cli/glide/v3/pkg/plugin/sdk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// selfCheckPlugin is a mock plugin with a self-check
+type selfCheckPlugin struct {
+	*plugintest.MockPlugin
+	err error
+}
+
+func (p *selfCheckPlugin) Validate() error {
+	return p.err
+}
+
+func loadValidConfig() (*config.Config, error) {
+	return &config.Config{}, nil
+}
+
+func noProjectConfigs() ([]string, error) {
+	return nil, nil
+}
+
+func TestValidateCommand(t *testing.T) {
+	t.Run("all checks pass", func(t *testing.T) {
+		reg := plugin.NewRegistry()
+		require.NoError(t, reg.RegisterPlugin(&selfCheckPlugin{MockPlugin: plugintest.NewMockPlugin("docker")}))
+		require.NoError(t, reg.RegisterPlugin(plugintest.NewMockPlugin("node")))
+
+		vc := &ValidateCommand{registry: reg, loadConfig: loadValidConfig, discoverConfigs: noProjectConfigs}
+		var out bytes.Buffer
+		require.NoError(t, vc.execute(&out))
+
+		output := out.String()
+		assert.Contains(t, output, "✓ configuration loads")
+		assert.Contains(t, output, "✓ dependency graph")
+		assert.Contains(t, output, "  ✓ docker\n")
+		assert.Contains(t, output, "- node: no self-check")
+		assert.Contains(t, output, "0 failed")
+	})
+
+	t.Run("reports every problem", func(t *testing.T) {
+		reg := plugin.NewRegistry()
+
+		broken := &selfCheckPlugin{
+			MockPlugin: plugintest.NewMockPlugin("docker"),
+			err:        errors.New("docker not found in PATH"),
+		}
+		require.NoError(t, reg.RegisterPlugin(broken))
+
+		dependent := plugintest.NewMockPlugin("compose")
+		dependent.MetadataValue.Dependencies = []sdk.PluginDependency{
+			{Name: "docker", Version: "^2.0.0"},
+		}
+		require.NoError(t, reg.RegisterPlugin(dependent))
+
+		vc := &ValidateCommand{
+			registry: reg,
+			loadConfig: func() (*config.Config, error) {
+				return nil, errors.New("yaml: line 3: mapping values are not allowed")
+			},
+			discoverConfigs: noProjectConfigs,
+		}
+		var out bytes.Buffer
+		err := vc.execute(&out)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "3 problem(s)")
+
+		output := out.String()
+		assert.Contains(t, output, "✗ configuration loads: yaml: line 3")
+		assert.Contains(t, output, "✗ compose: plugin \"compose\" requires docker@^2.0.0 but found version 1.0.0")
+		assert.Contains(t, output, "- dependency graph: skipped")
+		assert.Contains(t, output, "✗ docker: docker not found in PATH")
+		assert.Contains(t, output, "3 failed")
+	})
+
+	t.Run("skips disabled plugins", func(t *testing.T) {
+		reg := plugin.NewRegistry()
+		require.NoError(t, reg.RegisterPlugin(&selfCheckPlugin{
+			MockPlugin: plugintest.NewMockPlugin("docker"),
+			err:        errors.New("docker not found in PATH"),
+		}))
+		require.NoError(t, reg.RegisterPlugin(plugintest.NewMockPlugin("node")))
+		reg.SetDisabled([]string{"docker"})
+
+		vc := &ValidateCommand{registry: reg, loadConfig: loadValidConfig, discoverConfigs: noProjectConfigs}
+		var out bytes.Buffer
+		require.NoError(t, vc.execute(&out))
+		assert.NotContains(t, out.String(), "docker")
+	})
+
+	t.Run("validates project configs", func(t *testing.T) {
+		dir := t.TempDir()
+		valid := filepath.Join(dir, "valid.yml")
+		require.NoError(t, os.WriteFile(valid, []byte("commands:\n  build: make\n"), 0644))
+		badYAML := filepath.Join(dir, "bad-yaml.yml")
+		require.NoError(t, os.WriteFile(badYAML, []byte("commands:\n  build: [\n"), 0644))
+		badCommand := filepath.Join(dir, "bad-command.yml")
+		require.NoError(t, os.WriteFile(badCommand, []byte("commands:\n  empty:\n    cmd: \"\"\n"), 0644))
+
+		vc := &ValidateCommand{
+			registry:   plugin.NewRegistry(),
+			loadConfig: loadValidConfig,
+			discoverConfigs: func() ([]string, error) {
+				return []string{valid, badYAML, badCommand}, nil
+			},
+		}
+		var out bytes.Buffer
+		err := vc.execute(&out)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "2 problem(s)")
+
+		output := out.String()
+		assert.Contains(t, output, "✓ "+valid)
+		assert.Contains(t, output, "✗ "+badYAML+": yaml:")
+		assert.Contains(t, output, "✗ "+badCommand+": command empty: command cannot be empty")
+	})
+
+	t.Run("validates global commands", func(t *testing.T) {
+		vc := &ValidateCommand{
+			registry: plugin.NewRegistry(),
+			loadConfig: func() (*config.Config, error) {
+				return &config.Config{Commands: config.CommandMap{"deploy": ""}}, nil
+			},
+			discoverConfigs: noProjectConfigs,
+		}
+		var out bytes.Buffer
+		require.Error(t, vc.execute(&out))
+		assert.Contains(t, out.String(), "✗ global commands: command deploy: command cannot be empty")
+	})
+
+	t.Run("detects dependency cycles", func(t *testing.T) {
+		reg := plugin.NewRegistry()
+		for _, pair := range [][2]string{{"a", "b"}, {"b", "a"}} {
+			p := plugintest.NewMockPlugin(pair[0])
+			p.MetadataValue.Dependencies = []sdk.PluginDependency{{Name: pair[1], Version: "^1.0.0"}}
+			require.NoError(t, reg.RegisterPlugin(p))
+		}
+
+		vc := &ValidateCommand{registry: reg, loadConfig: loadValidConfig, discoverConfigs: noProjectConfigs}
+		var out bytes.Buffer
+		require.Error(t, vc.execute(&out))
+		assert.Contains(t, out.String(), "✗ dependency graph: cyclic dependency detected")
+	})
+}
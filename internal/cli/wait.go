@@ -0,0 +1,240 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	glideErrors "github.com/glide-cli/glide/v3/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// Dialer opens network connections for TCP probes.
+// *net.Dialer satisfies this interface.
+type Dialer interface {
+	DialContext(ctx context.Context, network, address string) (net.Conn, error)
+}
+
+// WaitCommand handles the wait command
+type WaitCommand struct {
+	dialer     Dialer
+	httpClient *http.Client
+	stat       func(name string) (os.FileInfo, error)
+}
+
+// waitOptions holds the flag values for the wait command
+type waitOptions struct {
+	tcp            []string
+	http           []string
+	files          []string
+	timeout        time.Duration
+	interval       time.Duration
+	attemptTimeout time.Duration
+}
+
+// waitProbe is a single readiness condition
+type waitProbe struct {
+	description string
+	check       func(ctx context.Context) error
+}
+
+// NewWaitCommand creates the wait command
+func NewWaitCommand() *cobra.Command {
+	wc := &WaitCommand{
+		dialer:     &net.Dialer{},
+		httpClient: &http.Client{},
+		stat:       os.Stat,
+	}
+	return wc.command()
+}
+
+// command builds the cobra command for this WaitCommand
+func (wc *WaitCommand) command() *cobra.Command {
+	opts := &waitOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "wait [flags]",
+		Short: "Wait for a TCP port, HTTP endpoint or file to become ready",
+		Long: `Wait until every given condition is met, polling at a fixed interval.
+
+Conditions:
+  --tcp host:port   A TCP connection can be established
+  --http url        A GET request returns a 2xx status
+  --file path       The file exists
+
+Each flag may be repeated. The command exits 0 once all conditions are met
+and with exit code 124 if the timeout elapses first. A single check that
+takes longer than --attempt-timeout counts as failed and is retried.
+
+Examples:
+  glide wait --tcp localhost:5432
+  glide wait --http http://localhost:8080/health --timeout 2m
+  glide wait --tcp db:3306 --file storage/ready --interval 500ms`,
+		Args:          cobra.NoArgs,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return wc.execute(cmd, opts)
+		},
+	}
+
+	cmd.Flags().StringArrayVar(&opts.tcp, "tcp", nil, "Wait for a TCP port (host:port)")
+	cmd.Flags().StringArrayVar(&opts.http, "http", nil, "Wait for an HTTP URL to return 2xx")
+	cmd.Flags().StringArrayVar(&opts.files, "file", nil, "Wait for a file to exist")
+	cmd.Flags().DurationVar(&opts.timeout, "timeout", 60*time.Second, "Maximum time to wait")
+	cmd.Flags().DurationVar(&opts.interval, "interval", time.Second, "Time between checks")
+	cmd.Flags().DurationVar(&opts.attemptTimeout, "attempt-timeout", 5*time.Second, "Maximum time for a single check")
+
+	return cmd
+}
+
+// execute runs the wait command
+func (wc *WaitCommand) execute(cmd *cobra.Command, opts *waitOptions) error {
+	probes := wc.probes(opts)
+	if len(probes) == 0 {
+		return glideErrors.NewUserError(
+			"no wait condition given",
+			"Specify at least one of --tcp, --http or --file",
+		)
+	}
+	if opts.interval <= 0 {
+		return glideErrors.NewUserError(
+			"--interval must be positive",
+			"Use a duration such as 500ms or 2s",
+		)
+	}
+	if opts.attemptTimeout <= 0 {
+		return glideErrors.NewUserError(
+			"--attempt-timeout must be positive",
+			"Use a duration such as 500ms or 2s",
+		)
+	}
+
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if opts.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.timeout)
+		defer cancel()
+	}
+
+	if err := wc.waitFor(ctx, probes, opts.interval, opts.attemptTimeout); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(cmd.OutOrStdout(), "All conditions met")
+	return nil
+}
+
+// probes builds the probes requested by opts
+func (wc *WaitCommand) probes(opts *waitOptions) []waitProbe {
+	var probes []waitProbe
+
+	for _, addr := range opts.tcp {
+		probes = append(probes, waitProbe{
+			description: "tcp " + addr,
+			check:       func(ctx context.Context) error { return wc.checkTCP(ctx, addr) },
+		})
+	}
+	for _, url := range opts.http {
+		probes = append(probes, waitProbe{
+			description: "http " + url,
+			check:       func(ctx context.Context) error { return wc.checkHTTP(ctx, url) },
+		})
+	}
+	for _, path := range opts.files {
+		probes = append(probes, waitProbe{
+			description: "file " + path,
+			check:       func(ctx context.Context) error { return wc.checkFile(path) },
+		})
+	}
+
+	return probes
+}
+
+// waitFor polls the probes until all succeed or ctx is done.
+// Probes that succeed are not checked again. Each check is bounded by
+// attemptTimeout so that one hung probe cannot stall the others.
+func (wc *WaitCommand) waitFor(ctx context.Context, probes []waitProbe, interval, attemptTimeout time.Duration) error {
+	pending := probes
+	lastErrs := make(map[string]error)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		var remaining []waitProbe
+		for _, probe := range pending {
+			if err := checkWithTimeout(ctx, probe, attemptTimeout); err != nil {
+				lastErrs[probe.description] = err
+				remaining = append(remaining, probe)
+			}
+		}
+		pending = remaining
+
+		if len(pending) == 0 {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			waiting := make([]string, 0, len(pending))
+			for _, probe := range pending {
+				waiting = append(waiting, fmt.Sprintf("%s (%v)", probe.description, lastErrs[probe.description]))
+			}
+			return glideErrors.NewTimeoutError(
+				"wait",
+				glideErrors.WithError(ctx.Err()),
+				glideErrors.WithContext("pending", strings.Join(waiting, ", ")),
+			)
+		case <-ticker.C:
+		}
+	}
+}
+
+// checkWithTimeout runs a single probe check bounded by timeout
+func checkWithTimeout(ctx context.Context, probe waitProbe, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	return probe.check(ctx)
+}
+
+// checkTCP succeeds if a TCP connection to addr can be established
+func (wc *WaitCommand) checkTCP(ctx context.Context, addr string) error {
+	conn, err := wc.dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+// checkHTTP succeeds if a GET request to url returns a 2xx status
+func (wc *WaitCommand) checkHTTP(ctx context.Context, url string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := wc.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return nil
+}
+
+// checkFile succeeds if path exists
+func (wc *WaitCommand) checkFile(path string) error {
+	_, err := wc.stat(path)
+	return err
+}
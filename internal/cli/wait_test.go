@@ -0,0 +1,139 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	glideErrors "github.com/glide-cli/glide/v3/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeDialer fails until the configured number of attempts has been made
+type fakeDialer struct {
+	failures int32
+	calls    atomic.Int32
+}
+
+func (d *fakeDialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	if d.calls.Add(1) <= d.failures {
+		return nil, errors.New("connection refused")
+	}
+	client, server := net.Pipe()
+	_ = server.Close()
+	return client, nil
+}
+
+func runWait(t *testing.T, wc *WaitCommand, args ...string) (string, error) {
+	t.Helper()
+	cmd := wc.command()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetArgs(args)
+	err := cmd.Execute()
+	return out.String(), err
+}
+
+func newTestWaitCommand(dialer Dialer) *WaitCommand {
+	return &WaitCommand{
+		dialer:     dialer,
+		httpClient: &http.Client{},
+		stat:       os.Stat,
+	}
+}
+
+func TestWaitCommand_TCP(t *testing.T) {
+	dialer := &fakeDialer{failures: 2}
+	out, err := runWait(t, newTestWaitCommand(dialer),
+		"--tcp", "db:5432", "--interval", "1ms", "--timeout", "1s")
+
+	require.NoError(t, err)
+	assert.Contains(t, out, "All conditions met")
+	assert.Equal(t, int32(3), dialer.calls.Load())
+}
+
+func TestWaitCommand_HTTP(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	_, err := runWait(t, newTestWaitCommand(&fakeDialer{}),
+		"--http", server.URL, "--interval", "1ms", "--timeout", "5s")
+
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), requests.Load())
+}
+
+func TestWaitCommand_HTTPHungRequest(t *testing.T) {
+	release := make(chan struct{})
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			select {
+			case <-r.Context().Done():
+			case <-release:
+			}
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	defer close(release)
+
+	start := time.Now()
+	_, err := runWait(t, newTestWaitCommand(&fakeDialer{}),
+		"--http", server.URL, "--interval", "1ms", "--attempt-timeout", "50ms", "--timeout", "5s")
+
+	require.NoError(t, err)
+	assert.Less(t, time.Since(start), 2*time.Second)
+	assert.GreaterOrEqual(t, requests.Load(), int32(2))
+}
+
+func TestWaitCommand_File(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ready")
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		_ = os.WriteFile(path, nil, 0644)
+	}()
+
+	_, err := runWait(t, newTestWaitCommand(&fakeDialer{}),
+		"--file", path, "--interval", "5ms", "--timeout", "5s")
+
+	require.NoError(t, err)
+}
+
+func TestWaitCommand_Timeout(t *testing.T) {
+	dialer := &fakeDialer{failures: 1 << 30}
+	_, err := runWait(t, newTestWaitCommand(dialer),
+		"--tcp", "db:5432", "--interval", "5ms", "--timeout", "30ms")
+
+	require.Error(t, err)
+	assert.True(t, glideErrors.Is(err, glideErrors.TypeTimeout))
+
+	var glideErr *glideErrors.GlideError
+	require.True(t, errors.As(err, &glideErr))
+	assert.Equal(t, 124, glideErr.Code)
+	assert.Contains(t, glideErr.Context["pending"], "tcp db:5432")
+}
+
+func TestWaitCommand_RequiresCondition(t *testing.T) {
+	_, err := runWait(t, newTestWaitCommand(&fakeDialer{}))
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no wait condition")
+}
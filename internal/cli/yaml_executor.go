@@ -1,21 +1,56 @@
 package cli
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 
 	"github.com/glide-cli/glide/v3/internal/config"
 	"github.com/glide-cli/glide/v3/internal/shell"
+	glideErrors "github.com/glide-cli/glide/v3/pkg/errors"
+	"github.com/glide-cli/glide/v3/pkg/prompt"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"golang.org/x/term"
 )
 
 var (
 	// yamlCommandSanitizer is the global sanitizer for YAML commands
 	// Can be configured via environment variables or config file
 	yamlCommandSanitizer shell.CommandSanitizer
+
+	// yamlCommandExecutor runs the shell that executes YAML commands
+	yamlCommandExecutor shell.CommandExecutor = shell.NewExecutor(shell.Options{})
+
+	// yamlProjectRoot is the directory relative step working directories
+	// are resolved from
+	yamlProjectRoot string
+
+	// yamlCommandPrompter asks for confirmation of YAML commands with confirm set
+	yamlCommandPrompter prompt.Prompter = prompt.New()
+
+	// stdinIsTerminal reports whether confirmation prompts can be answered
+	stdinIsTerminal = func() bool { return term.IsTerminal(int(os.Stdin.Fd())) }
 )
 
+// shellMetacharacters mark scripts that need a shell to run. Scripts
+// containing any of them are never dispatched in-process.
+const shellMetacharacters = "|&;<>()$`\\\"'*?[]{}~#\n"
+
+// maxGlideDispatchDepth stops YAML commands that invoke each other from
+// recursing forever in-process; deeper invocations run through the shell
+const maxGlideDispatchDepth = 8
+
+// glideDispatchDepth counts nested in-process glide invocations
+var glideDispatchDepth int
+
+// yesFlag skips the confirmation of YAML commands that declare one
+const yesFlag = "--yes"
+
 func init() {
 	// Initialize sanitizer based on environment
 	mode := os.Getenv("GLIDE_YAML_SANITIZE_MODE")
@@ -43,49 +78,290 @@ func init() {
 	}
 }
 
-// ExecuteYAMLCommand runs a YAML-defined command
+// ExecuteYAMLCommand runs a YAML-defined command with the default interpreter
 func ExecuteYAMLCommand(cmdStr string, args []string) error {
+	return ExecuteYAMLCommandWithShell("", cmdStr, args)
+}
+
+// ExecuteYAMLCommandWithShell runs a YAML-defined command, passing the whole
+// script to a single invocation of shellName (see scriptInterpreter)
+func ExecuteYAMLCommandWithShell(shellName, cmdStr string, args []string) error {
+	return executeYAMLCommand(nil, shellName, cmdStr, args, config.EnvExpansionNone, "", nil)
+}
+
+// runYAMLCommand runs the script or the steps of a YAML command
+func runYAMLCommand(root *cobra.Command, cmd *config.Command, args []string) error {
+	if len(cmd.Steps) > 0 {
+		return executeYAMLSteps(root, cmd, args)
+	}
+	return executeYAMLCommand(root, cmd.Shell, cmd.Cmd, args, cmd.ExpandEnv, "", cmd.Secrets)
+}
+
+// executeYAMLCommand runs a YAML-defined command in dir, or the current
+// directory if dir is empty, expanding environment variables in it according
+// to envMode. Errors mask the environment variables named in secrets. If
+// root is set, a command that only invokes glide itself runs
+// in-process on root (see dispatchGlideCommand).
+func executeYAMLCommand(root *cobra.Command, shellName, cmdStr string, args []string, envMode config.EnvExpansion, dir string, secrets []string) error {
+	expanded, err := prepareYAMLScript(cmdStr, args, envMode, secrets)
+	if err != nil {
+		return err
+	}
+
+	// Run glide subcommands without starting another glide process
+	if shellName == "" && dir == "" {
+		if handled, err := dispatchGlideCommand(root, expanded); handled {
+			return err
+		}
+	}
+
+	// Execute as a shell script
+	// This properly handles:
+	// - Single commands
+	// - Multi-line scripts
+	// - Pipes and redirects (if allowed by sanitizer)
+	// - Control structures (if allowed by sanitizer)
+	// - Shell built-ins and functions
+	return executeScript(scriptInterpreter(shellName, expanded), expanded, dir)
+}
+
+// prepareYAMLScript validates a YAML command and its arguments and returns
+// the command with the arguments substituted. The environment variables
+// named in secrets are masked in errors.
+func prepareYAMLScript(cmdStr string, args []string, envMode config.EnvExpansion, secrets []string) (string, error) {
 	// Validate command before expansion (check command string itself)
 	if err := yamlCommandSanitizer.Validate(cmdStr, []string{}); err != nil {
-		return fmt.Errorf("YAML command validation failed: %w\n\nTo disable sanitization (UNSAFE): export GLIDE_YAML_SANITIZE_MODE=disabled", err)
+		return "", fmt.Errorf("YAML command validation failed: %w\n\nTo disable sanitization (UNSAFE): export GLIDE_YAML_SANITIZE_MODE=disabled", err)
 	}
 
 	// Validate arguments before expansion
 	if err := yamlCommandSanitizer.Validate("", args); err != nil {
-		return fmt.Errorf("YAML command arguments validation failed: %w\n\nTo disable sanitization (UNSAFE): export GLIDE_YAML_SANITIZE_MODE=disabled", err)
+		return "", fmt.Errorf("YAML command arguments validation failed: %w\n\nTo disable sanitization (UNSAFE): export GLIDE_YAML_SANITIZE_MODE=disabled", err)
 	}
 
 	// Expand parameters
-	expanded := config.ExpandCommand(cmdStr, args)
+	expanded, err := config.ExpandCommandEnv(cmdStr, args, envMode, nil)
+	if err != nil {
+		return "", glideErrors.NewUserError(
+			fmt.Sprintf("cannot run command: %v", err),
+			"Set the variable, or use expand_env: true to expand undefined variables to empty strings",
+		)
+	}
 
 	// Validate expanded command as final check
 	// This catches injection attempts that might occur during expansion
 	if err := yamlCommandSanitizer.Validate(expanded, []string{}); err != nil {
-		return fmt.Errorf("expanded YAML command validation failed: %w\n\nCommand after expansion: %s\n\nTo disable sanitization (UNSAFE): export GLIDE_YAML_SANITIZE_MODE=disabled", err, expanded)
+		return "", fmt.Errorf("expanded YAML command validation failed: %w\n\nCommand after expansion: %s\n\nTo disable sanitization (UNSAFE): export GLIDE_YAML_SANITIZE_MODE=disabled", err, maskSecrets(expanded, secrets))
 	}
 
-	// Execute as a shell script
-	// This properly handles:
-	// - Single commands
-	// - Multi-line scripts
-	// - Pipes and redirects (if allowed by sanitizer)
-	// - Control structures (if allowed by sanitizer)
-	// - Shell built-ins and functions
-	return executeShellCommand(expanded)
+	return expanded, nil
 }
 
-// executeShellCommand runs a command through the shell
+// dispatchGlideCommand runs script on root in-process if it is a single
+// invocation of the running glide binary with plain arguments, as in
+// "glide test --watch". This keeps the project context and plugins already
+// loaded instead of starting glide again. Scripts using shell features
+// (quoting, variables, pipes, several commands) and commands naming a
+// different binary are not handled and should run through the shell.
+func dispatchGlideCommand(root *cobra.Command, script string) (handled bool, err error) {
+	if root == nil || glideDispatchDepth >= maxGlideDispatchDepth {
+		return false, nil
+	}
+
+	script = strings.TrimSpace(script)
+	if script == "" || strings.ContainsAny(script, shellMetacharacters) {
+		return false, nil
+	}
+	fields := strings.Fields(script)
+	if !isRunningExecutable(fields[0]) {
+		return false, nil
+	}
+
+	// Flags parsed by the outer invocation or an earlier step would
+	// otherwise carry over into this one
+	if !resetDispatchFlags(root, fields[1:]) {
+		return false, nil
+	}
+
+	glideDispatchDepth++
+	defer func() { glideDispatchDepth-- }()
+
+	root.SetArgs(fields[1:])
+	defer root.SetArgs(nil)
+	return true, root.Execute()
+}
+
+// resetDispatchFlags returns the flags of the command args resolve to, and of
+// its parents, to their defaults before it is executed again in-process. The
+// root's persistent flags keep the values of the outer invocation, so global
+// options such as --quiet apply to the nested command as well. It reports
+// false if a set flag cannot be reset: slice and map flags append to their
+// previous values once set, so such commands must run through the shell.
+func resetDispatchFlags(root *cobra.Command, args []string) bool {
+	target, _, err := root.Find(args)
+	if err != nil {
+		// Unknown commands are reported when root runs
+		return true
+	}
+
+	globals := root.PersistentFlags()
+	resettable := true
+	for c := target; c != nil; c = c.Parent() {
+		c.Flags().VisitAll(func(flag *pflag.Flag) {
+			if !flag.Changed || globals.Lookup(flag.Name) == flag {
+				return
+			}
+			if _, isSlice := flag.Value.(pflag.SliceValue); isSlice || strings.HasPrefix(flag.Value.Type(), "stringTo") {
+				resettable = false
+				return
+			}
+			if err := flag.Value.Set(flag.DefValue); err != nil {
+				resettable = false
+				return
+			}
+			flag.Changed = false
+		})
+	}
+	return resettable
+}
+
+// isRunningExecutable reports whether program resolves to the binary of the
+// current process
+func isRunningExecutable(program string) bool {
+	path, err := exec.LookPath(program)
+	if err != nil {
+		return false
+	}
+	self, err := os.Executable()
+	if err != nil {
+		return false
+	}
+	path, err = filepath.EvalSymlinks(path)
+	if err != nil {
+		return false
+	}
+	self, err = filepath.EvalSymlinks(self)
+	if err != nil {
+		return false
+	}
+	return path == self
+}
+
+// executeShellCommand runs a command through the platform's default shell
 func executeShellCommand(cmdStr string) error {
-	// Use sh -c to handle pipes, redirects, and other shell features
-	cmd := exec.Command("sh", "-c", cmdStr)
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	cmd.Stdin = os.Stdin
+	return executeScript(shell.DefaultShell(), cmdStr, "")
+}
+
+// scriptInterpreter returns the interpreter argv for a script: shellName if
+// set, otherwise the script's #! line, otherwise the platform's default shell
+// (sh -c, or cmd /C on Windows). The script is never split, so loops,
+// heredocs and variables work across lines.
+func scriptInterpreter(shellName, script string) []string {
+	if strings.TrimSpace(shellName) != "" {
+		return shell.ShellArgs(shellName)
+	}
+
+	if strings.HasPrefix(script, "#!") {
+		line, _, _ := strings.Cut(script[2:], "\n")
+		if strings.TrimSpace(line) != "" {
+			return shell.ShellArgs(line)
+		}
+	}
+
+	return shell.DefaultShell()
+}
+
+// executeScript runs the script in dir with interpreter, which ends with the
+// flag that takes the script (e.g. "sh -c")
+func executeScript(interpreter []string, cmdStr, dir string) error {
+	return runScript(context.Background(), interpreter, cmdStr, dir, os.Stdin, os.Stdout, os.Stderr)
+}
 
-	// Set environment to include current environment
-	cmd.Env = os.Environ()
+// runScript runs the script in dir with interpreter and the given standard
+// streams through yamlCommandExecutor, killing the interpreter if ctx is
+// cancelled
+func runScript(ctx context.Context, interpreter []string, cmdStr, dir string, stdin io.Reader, stdout, stderr io.Writer) error {
+	// Let the interpreter handle pipes, redirects, and other shell features
+	cmdArgs := append(append([]string{}, interpreter[1:]...), cmdStr)
+	cmd := shell.NewPassthroughCommand(interpreter[0], cmdArgs...).WithWorkingDir(dir)
+	cmd.Stdin = stdin
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	cmd.StreamOutput = true
+
+	result, err := yamlCommandExecutor.ExecuteWithContext(ctx, cmd)
+	if err != nil {
+		return err
+	}
+	return shell.ResultError(cmd, result)
+}
+
+// confirmYAMLCommand asks the user to confirm a command marked with confirm
+// and returns the args with --yes removed. --yes is only recognized before a
+// "--" separator; later args are passed to the command as is. Without a
+// terminal to ask on, --yes is required. Declining returns an error, so the
+// command exits non-zero without running.
+func confirmYAMLCommand(message string, args []string) ([]string, error) {
+	if message == "" {
+		return args, nil
+	}
+
+	remaining := make([]string, 0, len(args))
+	yes := false
+	for i, arg := range args {
+		if arg == "--" {
+			remaining = append(remaining, args[i:]...)
+			break
+		}
+		if arg == yesFlag {
+			yes = true
+			continue
+		}
+		remaining = append(remaining, arg)
+	}
+
+	if yes {
+		return remaining, nil
+	}
+
+	if !stdinIsTerminal() {
+		return nil, glideErrors.NewUserError(
+			"this command requires confirmation but no terminal is available",
+			fmt.Sprintf("Re-run with %s to confirm non-interactively", yesFlag),
+		)
+	}
+
+	confirmed, err := yamlCommandPrompter.Confirm(message, false)
+	if err != nil {
+		return nil, err
+	}
+	if !confirmed {
+		return nil, glideErrors.New(glideErrors.TypeCommand, "cancelled", glideErrors.WithExitCode(1))
+	}
+
+	return remaining, nil
+}
+
+// SetYAMLCommandPrompter allows overriding the confirmation prompter (for testing)
+func SetYAMLCommandPrompter(prompter prompt.Prompter) {
+	yamlCommandPrompter = prompter
+}
+
+// YAMLCommandExecutor returns the executor that runs YAML commands
+func YAMLCommandExecutor() shell.CommandExecutor {
+	return yamlCommandExecutor
+}
+
+// SetYAMLCommandExecutor allows overriding the executor that runs YAML
+// commands, e.g. to wrap it in a decorator such as shell.TimingExecutor
+func SetYAMLCommandExecutor(executor shell.CommandExecutor) {
+	yamlCommandExecutor = executor
+}
 
-	return cmd.Run()
+// SetYAMLProjectRoot sets the directory relative step working directories
+// are resolved from
+func SetYAMLProjectRoot(dir string) {
+	yamlProjectRoot = dir
 }
 
 // SetYAMLCommandSanitizer allows overriding the global sanitizer (for testing)
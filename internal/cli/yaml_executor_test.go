@@ -1,10 +1,20 @@
 package cli
 
 import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
 	"strings"
 	"testing"
 
+	"github.com/glide-cli/glide/v3/internal/config"
 	"github.com/glide-cli/glide/v3/internal/shell"
+	"github.com/glide-cli/glide/v3/internal/shell/shelltest"
+	glideErrors "github.com/glide-cli/glide/v3/pkg/errors"
+	"github.com/glide-cli/glide/v3/pkg/prompt"
+	"github.com/spf13/cobra"
 )
 
 func TestExecuteYAMLCommand_Sanitization(t *testing.T) {
@@ -521,3 +531,427 @@ func TestExecuteShellCommand(t *testing.T) {
 		})
 	}
 }
+
+func TestExecuteYAMLCommand_Profiling(t *testing.T) {
+	originalSanitizer := yamlCommandSanitizer
+	defer SetYAMLCommandSanitizer(originalSanitizer)
+	SetYAMLCommandSanitizer(shell.NewSanitizer(&shell.SanitizerConfig{Mode: shell.ModeDisabled}))
+
+	originalExecutor := yamlCommandExecutor
+	defer SetYAMLCommandExecutor(originalExecutor)
+	recorder := shell.NewTimingRecorder()
+	SetYAMLCommandExecutor(shell.NewTimingExecutor(originalExecutor, recorder))
+
+	if err := ExecuteYAMLCommand("true && true", nil); err != nil {
+		t.Fatalf("ExecuteYAMLCommand() error = %v", err)
+	}
+
+	report := recorder.Report()
+	if report.Count != 1 {
+		t.Fatalf("expected 1 recorded command, got %d", report.Count)
+	}
+	if report.Programs[0].Program != "true" {
+		t.Errorf("expected program %q, got %q", "true", report.Programs[0].Program)
+	}
+}
+
+// TestExecuteYAMLCommand_Executor verifies that YAML commands run through
+// the configured executor and that its exit codes are reported
+func TestExecuteYAMLCommand_Executor(t *testing.T) {
+	originalSanitizer := yamlCommandSanitizer
+	defer SetYAMLCommandSanitizer(originalSanitizer)
+	SetYAMLCommandSanitizer(shell.NewSanitizer(shell.ScriptConfig()))
+
+	originalExecutor := yamlCommandExecutor
+	defer SetYAMLCommandExecutor(originalExecutor)
+
+	executor := shelltest.NewMockExecutor()
+	executor.OnAnyArgs("bash").Return(&shell.Result{ExitCode: 3}, nil)
+	SetYAMLCommandExecutor(executor)
+
+	if err := ExecuteYAMLCommandWithShell("sh", "docker compose ps | grep web", nil); err != nil {
+		t.Fatalf("ExecuteYAMLCommandWithShell() error = %v", err)
+	}
+	executor.AssertCalled(t, "sh", "-c", "docker compose ps | grep web")
+
+	err := ExecuteYAMLCommandWithShell("bash", "exit 3", nil)
+	var exitErr *shell.ExitError
+	if !errors.As(err, &exitErr) || exitErr.Code() != 3 {
+		t.Errorf("ExecuteYAMLCommandWithShell() error = %v, want exit code 3", err)
+	}
+}
+
+// TestExecuteYAMLCommand_WholeScript verifies that multi-line scripts run in a
+// single interpreter invocation, so loops, heredocs and variables span lines
+func TestExecuteYAMLCommand_WholeScript(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("POSIX shell scripts")
+	}
+	if _, err := exec.LookPath("bash"); err != nil {
+		t.Skip("bash not available")
+	}
+
+	originalSanitizer := yamlCommandSanitizer
+	defer SetYAMLCommandSanitizer(originalSanitizer)
+	SetYAMLCommandSanitizer(shell.NewSanitizer(shell.ScriptConfig()))
+
+	tests := []struct {
+		name      string
+		shellName string
+		script    string
+		want      string
+	}{
+		{
+			name:      "bash for loop",
+			shellName: "bash",
+			script: `total=0
+for i in {1..3}; do
+  total=$((total + i))
+done
+echo "$total" > "$OUT"`,
+			want: "6\n",
+		},
+		{
+			name: "heredoc",
+			script: `name=glide
+cat > "$OUT" <<EOF
+hello $name
+second line
+EOF`,
+			want: "hello glide\nsecond line\n",
+		},
+		{
+			name: "shebang selects interpreter",
+			script: `#!/usr/bin/env bash
+words=(a b c)
+echo "${#words[@]}" > "$OUT"`,
+			want: "3\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := filepath.Join(t.TempDir(), "out")
+			t.Setenv("OUT", out)
+
+			if err := ExecuteYAMLCommandWithShell(tt.shellName, tt.script, nil); err != nil {
+				t.Fatalf("ExecuteYAMLCommandWithShell() error = %v", err)
+			}
+
+			got, err := os.ReadFile(out)
+			if err != nil {
+				t.Fatalf("failed to read output: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("output = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestExecuteYAMLCommand_ExpandEnv verifies that expand_env substitutes
+// environment variables before the script runs
+func TestExecuteYAMLCommand_ExpandEnv(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("POSIX shell scripts")
+	}
+
+	originalSanitizer := yamlCommandSanitizer
+	defer SetYAMLCommandSanitizer(originalSanitizer)
+	SetYAMLCommandSanitizer(shell.NewSanitizer(shell.ScriptConfig()))
+
+	out := filepath.Join(t.TempDir(), "out")
+	t.Setenv("OUT", out)
+	t.Setenv("GLIDE_TEST_CONTEXT", "staging")
+
+	// Single quotes keep the shell from expanding the variable itself
+	script := `echo '${GLIDE_TEST_CONTEXT} [$GLIDE_TEST_UNSET]' > "$OUT"`
+	if err := executeYAMLCommand(nil, "", script, nil, config.EnvExpansionLenient, "", nil); err != nil {
+		t.Fatalf("executeYAMLCommand() error = %v", err)
+	}
+	got, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("failed to read output: %v", err)
+	}
+	if string(got) != "staging []\n" {
+		t.Errorf("output = %q, want %q", got, "staging []\n")
+	}
+
+	err = executeYAMLCommand(nil, "", script, nil, config.EnvExpansionStrict, "", nil)
+	if err == nil || !strings.Contains(err.Error(), "GLIDE_TEST_UNSET is not set") {
+		t.Errorf("executeYAMLCommand() error = %v, want undefined variable error", err)
+	}
+}
+
+// TestDispatchGlideCommand verifies that YAML commands invoking glide itself
+// run in-process on the root command
+func TestDispatchGlideCommand(t *testing.T) {
+	self, err := os.Executable()
+	if err != nil {
+		t.Fatalf("os.Executable() error = %v", err)
+	}
+	bin := t.TempDir()
+	name := "glide"
+	if runtime.GOOS == "windows" {
+		name += ".exe"
+	}
+	if err := os.Symlink(self, filepath.Join(bin, name)); err != nil {
+		t.Skipf("cannot create symlink: %v", err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	var ran [][]string
+	root := &cobra.Command{Use: "glide", SilenceErrors: true, SilenceUsage: true}
+	root.AddCommand(&cobra.Command{
+		Use: "sub",
+		RunE: func(c *cobra.Command, args []string) error {
+			ran = append(ran, args)
+			return nil
+		},
+	})
+
+	tests := []struct {
+		name    string
+		root    *cobra.Command
+		script  string
+		handled bool
+	}{
+		{name: "glide invocation", root: root, script: "glide sub one two\n", handled: true},
+		{name: "no root", root: nil, script: "glide sub"},
+		{name: "other binary", root: root, script: "go version"},
+		{name: "unknown binary", root: root, script: "glide-not-installed sub"},
+		{name: "pipeline", root: root, script: "glide sub | cat"},
+		{name: "quoted arguments", root: root, script: `glide sub "one two"`},
+		{name: "variables", root: root, script: "glide sub $HOME"},
+		{name: "several commands", root: root, script: "glide sub\nglide sub"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ran = nil
+			handled, err := dispatchGlideCommand(tt.root, tt.script)
+			if handled != tt.handled {
+				t.Fatalf("dispatchGlideCommand() handled = %v, want %v", handled, tt.handled)
+			}
+			if err != nil {
+				t.Errorf("dispatchGlideCommand() error = %v", err)
+			}
+			if tt.handled && (len(ran) != 1 || strings.Join(ran[0], ",") != "one,two") {
+				t.Errorf("sub ran with %v, want [[one two]]", ran)
+			}
+			if !tt.handled && len(ran) != 0 {
+				t.Errorf("sub ran with %v, want no runs", ran)
+			}
+		})
+	}
+
+	t.Run("flags do not carry over between steps", func(t *testing.T) {
+		var forced []bool
+		root := &cobra.Command{Use: "glide", SilenceErrors: true, SilenceUsage: true}
+		var quiet bool
+		root.PersistentFlags().BoolVar(&quiet, "quiet", false, "")
+		build := &cobra.Command{
+			Use: "b",
+			RunE: func(c *cobra.Command, args []string) error {
+				force, _ := c.Flags().GetBool("force")
+				forced = append(forced, force)
+				return nil
+			},
+		}
+		build.Flags().Bool("force", false, "")
+		build.Flags().StringSlice("tag", nil, "")
+		root.AddCommand(build)
+
+		cmd := &config.Command{Steps: []config.Step{{Cmd: "glide b --force"}, {Cmd: "glide b"}}}
+		if err := executeYAMLSteps(root, cmd, nil); err != nil {
+			t.Fatalf("executeYAMLSteps() error = %v", err)
+		}
+		if len(forced) != 2 || !forced[0] || forced[1] {
+			t.Errorf("force per step = %v, want [true false]", forced)
+		}
+
+		// Global options of the outer invocation still apply
+		root.SetArgs([]string{"--quiet", "b", "--force"})
+		if err := root.Execute(); err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+		if handled, err := dispatchGlideCommand(root, "glide b"); !handled || err != nil {
+			t.Fatalf("dispatchGlideCommand() = %v, %v", handled, err)
+		}
+		if forced[len(forced)-1] || !quiet {
+			t.Errorf("force = %v, quiet = %v, want force reset and quiet kept", forced[len(forced)-1], quiet)
+		}
+
+		// Slice flags cannot be reset once set
+		if err := build.Flags().Set("tag", "v1"); err != nil {
+			t.Fatal(err)
+		}
+		if resetDispatchFlags(root, []string{"b"}) {
+			t.Error("resetDispatchFlags() = true with a slice flag set, want false")
+		}
+	})
+
+	t.Run("recursion is bounded", func(t *testing.T) {
+		depth := 0
+		root := &cobra.Command{Use: "glide", SilenceErrors: true, SilenceUsage: true}
+		root.AddCommand(&cobra.Command{
+			Use: "loop",
+			RunE: func(c *cobra.Command, args []string) error {
+				depth++
+				if handled, err := dispatchGlideCommand(c.Root(), "glide loop"); !handled {
+					return err
+				}
+				return nil
+			},
+		})
+
+		if _, err := dispatchGlideCommand(root, "glide loop"); err != nil {
+			t.Fatalf("dispatchGlideCommand() error = %v", err)
+		}
+		if depth != maxGlideDispatchDepth {
+			t.Errorf("depth = %d, want %d", depth, maxGlideDispatchDepth)
+		}
+	})
+}
+
+func TestScriptInterpreter(t *testing.T) {
+	tests := []struct {
+		shellName string
+		script    string
+		want      []string
+	}{
+		{"", "echo hi", shell.DefaultShell()},
+		{"bash", "echo hi", []string{"bash", "-c"}},
+		{"", "#!/usr/bin/env bash\necho hi", []string{"/usr/bin/env", "bash", "-c"}},
+		{"zsh", "#!/bin/bash\necho hi", []string{"zsh", "-c"}},
+		{"pwsh", "Write-Output hi", []string{"pwsh", "-Command"}},
+		{"", "#!\necho hi", shell.DefaultShell()},
+	}
+
+	for _, tt := range tests {
+		got := scriptInterpreter(tt.shellName, tt.script)
+		if strings.Join(got, " ") != strings.Join(tt.want, " ") {
+			t.Errorf("scriptInterpreter(%q, %q) = %v, want %v", tt.shellName, tt.script, got, tt.want)
+		}
+	}
+}
+
+// fakeConfirmPrompter answers Confirm with a fixed value and records the question
+type fakeConfirmPrompter struct {
+	answer bool
+	asked  []string
+}
+
+func (f *fakeConfirmPrompter) Confirm(message string, defaultValue bool) (bool, error) {
+	f.asked = append(f.asked, message)
+	return f.answer, nil
+}
+
+func (f *fakeConfirmPrompter) Select(message string, options []string, defaultIndex int) (int, string, error) {
+	return defaultIndex, options[defaultIndex], nil
+}
+
+func (f *fakeConfirmPrompter) Input(message string, defaultValue string, validator prompt.InputValidator) (string, error) {
+	return defaultValue, nil
+}
+
+func (f *fakeConfirmPrompter) Password(message string) (string, error) {
+	return "", nil
+}
+
+func TestConfirmYAMLCommand(t *testing.T) {
+	originalPrompter := yamlCommandPrompter
+	originalIsTerminal := stdinIsTerminal
+	defer func() {
+		SetYAMLCommandPrompter(originalPrompter)
+		stdinIsTerminal = originalIsTerminal
+	}()
+
+	tests := []struct {
+		name      string
+		message   string
+		args      []string
+		terminal  bool
+		answer    bool
+		wantArgs  []string
+		wantErr   bool
+		wantCode  int
+		wantAsked int
+	}{
+		{
+			name:     "no confirm runs without asking",
+			args:     []string{"--yes", "x"},
+			terminal: true,
+			wantArgs: []string{"--yes", "x"},
+		},
+		{
+			name:      "confirmed",
+			message:   "Reset the database?",
+			args:      []string{"x"},
+			terminal:  true,
+			answer:    true,
+			wantArgs:  []string{"x"},
+			wantAsked: 1,
+		},
+		{
+			name:      "declined",
+			message:   "Reset the database?",
+			args:      []string{"x"},
+			terminal:  true,
+			answer:    false,
+			wantErr:   true,
+			wantCode:  1,
+			wantAsked: 1,
+		},
+		{
+			name:     "yes skips the prompt and is stripped",
+			message:  "Reset the database?",
+			args:     []string{"x", "--yes"},
+			terminal: false,
+			wantArgs: []string{"x"},
+		},
+		{
+			name:      "yes after -- belongs to the command",
+			message:   "Reset the database?",
+			args:      []string{"x", "--", "--yes"},
+			terminal:  true,
+			answer:    true,
+			wantArgs:  []string{"x", "--", "--yes"},
+			wantAsked: 1,
+		},
+		{
+			name:     "no terminal without yes fails",
+			message:  "Reset the database?",
+			args:     []string{"x"},
+			terminal: false,
+			wantErr:  true,
+			wantCode: 64,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prompter := &fakeConfirmPrompter{answer: tt.answer}
+			SetYAMLCommandPrompter(prompter)
+			stdinIsTerminal = func() bool { return tt.terminal }
+
+			args, err := confirmYAMLCommand(tt.message, tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			var glideErr *glideErrors.GlideError
+			if tt.wantErr && (!errors.As(err, &glideErr) || glideErr.Code != tt.wantCode) {
+				t.Errorf("error = %#v, want exit code %d", err, tt.wantCode)
+			}
+			if strings.Join(args, " ") != strings.Join(tt.wantArgs, " ") {
+				t.Errorf("args = %v, want %v", args, tt.wantArgs)
+			}
+			if len(prompter.asked) != tt.wantAsked {
+				t.Errorf("prompted %d times, want %d", len(prompter.asked), tt.wantAsked)
+			}
+			if tt.wantAsked > 0 && prompter.asked[0] != tt.message {
+				t.Errorf("prompt = %q, want %q", prompter.asked[0], tt.message)
+			}
+		})
+	}
+}
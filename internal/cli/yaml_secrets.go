@@ -0,0 +1,63 @@
+package cli
+
+import (
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// secretMask replaces secrets in commands glide prints
+const secretMask = "****"
+
+var (
+	secretsMu sync.RWMutex
+
+	// secretEnvNames are environment variables masked in every YAML command
+	secretEnvNames []string
+
+	// secretPatterns match further text masked in every YAML command
+	secretPatterns []*regexp.Regexp
+)
+
+// RegisterYAMLSecretEnv marks environment variables as sensitive. References
+// to them (${NAME} or $NAME) and their current values are masked when YAML
+// commands are printed; the commands still run with the real values.
+func RegisterYAMLSecretEnv(names ...string) {
+	secretsMu.Lock()
+	defer secretsMu.Unlock()
+	secretEnvNames = append(secretEnvNames, names...)
+}
+
+// RegisterYAMLSecretPattern masks text matching pattern when YAML commands
+// are printed, e.g. `--password=\S+`
+func RegisterYAMLSecretPattern(pattern *regexp.Regexp) {
+	secretsMu.Lock()
+	defer secretsMu.Unlock()
+	secretPatterns = append(secretPatterns, pattern)
+}
+
+// maskSecrets returns text with the registered secrets and the environment
+// variables in names replaced by secretMask
+func maskSecrets(text string, names []string) string {
+	secretsMu.RLock()
+	defer secretsMu.RUnlock()
+
+	for _, name := range append(append([]string{}, secretEnvNames...), names...) {
+		text = secretReference(name).ReplaceAllString(text, secretMask)
+		if value := os.Getenv(name); value != "" {
+			text = strings.ReplaceAll(text, value, secretMask)
+		}
+	}
+	for _, pattern := range secretPatterns {
+		text = pattern.ReplaceAllString(text, secretMask)
+	}
+	return text
+}
+
+// secretReference matches references to the environment variable name,
+// including ones with a default such as ${NAME:-value}
+func secretReference(name string) *regexp.Regexp {
+	quoted := regexp.QuoteMeta(name)
+	return regexp.MustCompile(`\$\{` + quoted + `(?::?[-=?+][^}]*)?\}|\$` + quoted + `\b`)
+}
@@ -0,0 +1,124 @@
+package cli
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/glide-cli/glide/v3/internal/config"
+	"github.com/glide-cli/glide/v3/internal/shell"
+)
+
+func TestMaskSecrets(t *testing.T) {
+	t.Setenv("DB_PASS", "hunter22")
+	t.Setenv("API_TOKEN", "tok-123")
+
+	originalNames, originalPatterns := secretEnvNames, secretPatterns
+	defer func() { secretEnvNames, secretPatterns = originalNames, originalPatterns }()
+	secretEnvNames, secretPatterns = nil, nil
+
+	RegisterYAMLSecretEnv("API_TOKEN")
+	RegisterYAMLSecretPattern(regexp.MustCompile(`--key=\S+`))
+
+	tests := []struct {
+		name  string
+		text  string
+		names []string
+		want  string
+	}{
+		{
+			name:  "braced reference",
+			text:  "mysql --password ${DB_PASS}",
+			names: []string{"DB_PASS"},
+			want:  "mysql --password ****",
+		},
+		{
+			name:  "plain reference",
+			text:  "mysql --password $DB_PASS -h db",
+			names: []string{"DB_PASS"},
+			want:  "mysql --password **** -h db",
+		},
+		{
+			name:  "reference with default",
+			text:  "login ${DB_PASS:-secret}",
+			names: []string{"DB_PASS"},
+			want:  "login ****",
+		},
+		{
+			name:  "literal value",
+			text:  "mysql --password hunter22",
+			names: []string{"DB_PASS"},
+			want:  "mysql --password ****",
+		},
+		{
+			name:  "longer variable names are kept",
+			text:  "echo $DB_PASS_FILE",
+			names: []string{"DB_PASS"},
+			want:  "echo $DB_PASS_FILE",
+		},
+		{
+			name: "undeclared variables are kept",
+			text: "mysql --password ${DB_PASS}",
+			want: "mysql --password ${DB_PASS}",
+		},
+		{
+			name: "registered variable",
+			text: "curl -H \"Authorization: $API_TOKEN\" tok-123",
+			want: "curl -H \"Authorization: ****\" ****",
+		},
+		{
+			name: "registered pattern",
+			text: "deploy --key=abc123 --env prod",
+			want: "deploy **** --env prod",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := maskSecrets(tt.text, tt.names); got != tt.want {
+				t.Errorf("maskSecrets(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+// rejectingSanitizer rejects commands containing a fixed string
+type rejectingSanitizer struct {
+	shell.CommandSanitizer
+	reject string
+}
+
+func (s rejectingSanitizer) Validate(command string, args []string) error {
+	if command != "" && strings.Contains(command, s.reject) {
+		return errors.New("command not allowed")
+	}
+	return nil
+}
+
+func TestPrepareYAMLScript_MasksSecretsInErrors(t *testing.T) {
+	t.Setenv("DB_PASS", "hunter22")
+
+	original := yamlCommandSanitizer
+	defer func() { yamlCommandSanitizer = original }()
+	yamlCommandSanitizer = rejectingSanitizer{CommandSanitizer: original, reject: "hunter22"}
+
+	cmd := &config.Command{
+		ExpandEnv: config.EnvExpansionLenient,
+		Secrets:   []string{"DB_PASS"},
+		Steps:     []config.Step{{Cmd: "mysql --password=${DB_PASS}"}},
+	}
+
+	_, err := prepareYAMLScript(cmd.Steps[0].Cmd, nil, cmd.ExpandEnv, cmd.Secrets)
+	if err == nil {
+		t.Fatal("prepareYAMLScript() error = nil, want validation error")
+	}
+	if strings.Contains(err.Error(), "hunter22") || !strings.Contains(err.Error(), "mysql --password=****") {
+		t.Errorf("prepareYAMLScript() error = %q, want the secret masked", err)
+	}
+
+	err = executeYAMLSteps(nil, cmd, nil)
+	if err == nil || strings.Contains(err.Error(), "hunter22") {
+		t.Errorf("executeYAMLSteps() error = %v, want a failure without the secret", err)
+	}
+}
@@ -0,0 +1,265 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/glide-cli/glide/v3/internal/config"
+	glideErrors "github.com/glide-cli/glide/v3/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// errStepsInterrupted reports that parallel steps were cancelled by a signal
+var errStepsInterrupted = errors.New("interrupted")
+
+// executeYAMLSteps runs the steps of a YAML command in order, reporting
+// progress on stderr. Consecutive parallel steps run together as a group.
+// It stops after the first failing step or group unless the failed steps
+// ignore errors, and fails if any step failed.
+func executeYAMLSteps(root *cobra.Command, cmd *config.Command, args []string) error {
+	failures := &stepFailures{cmd: cmd}
+	for i := 0; i < len(cmd.Steps) && !failures.stop; {
+		if !cmd.Steps[i].Parallel {
+			announceStep(cmd, i)
+			if err := executeYAMLStep(root, cmd, cmd.Steps[i], args); err != nil {
+				failures.record(i, err)
+			}
+			i++
+			continue
+		}
+
+		end := i + 1
+		for end < len(cmd.Steps) && cmd.Steps[end].Parallel {
+			end++
+		}
+		executeParallelSteps(cmd, args, i, end, failures)
+		i = end
+	}
+
+	if len(failures.errs) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%d of %d steps failed: %w", failures.count, len(cmd.Steps), errors.Join(failures.errs...))
+}
+
+// executeYAMLStep runs one step of a YAML command in its working directory
+func executeYAMLStep(root *cobra.Command, cmd *config.Command, step config.Step, args []string) error {
+	dir, err := stepDir(step)
+	if err != nil {
+		return err
+	}
+	return executeYAMLCommand(root, cmd.Shell, step.Cmd, args, cmd.ExpandEnv, dir, cmd.Secrets)
+}
+
+// executeParallelSteps runs the steps from start up to end concurrently, at
+// most cmd.MaxParallel at a time, prefixing each line of their output with
+// the step's name. An interrupt cancels all running steps and stops the
+// command.
+func executeParallelSteps(cmd *config.Command, args []string, start, end int, failures *stepFailures) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	limit := cmd.MaxParallel
+	if limit < 1 {
+		limit = runtime.NumCPU()
+	}
+	slots := make(chan struct{}, limit)
+
+	var (
+		outputMu sync.Mutex
+		wg       sync.WaitGroup
+	)
+	for i := start; i < end; i++ {
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		announceStep(cmd, i)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			name := stepName(cmd.Steps[i])
+			stdout := newPrefixWriter(os.Stdout, &outputMu, name)
+			stderr := newPrefixWriter(os.Stderr, &outputMu, name)
+			err := executeParallelStep(ctx, cmd, cmd.Steps[i], args, stdout, stderr)
+			stdout.Flush()
+			stderr.Flush()
+
+			if err != nil && ctx.Err() == nil {
+				failures.record(i, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if ctx.Err() != nil {
+		failures.interrupt()
+	}
+}
+
+// executeParallelStep runs one parallel step with its output going to
+// stdout and stderr. Parallel steps never read stdin and always run
+// through the shell.
+func executeParallelStep(ctx context.Context, cmd *config.Command, step config.Step, args []string, stdout, stderr io.Writer) error {
+	dir, err := stepDir(step)
+	if err != nil {
+		return err
+	}
+	expanded, err := prepareYAMLScript(step.Cmd, args, cmd.ExpandEnv, cmd.Secrets)
+	if err != nil {
+		return err
+	}
+	return runScript(ctx, scriptInterpreter(cmd.Shell, expanded), expanded, dir, nil, stdout, stderr)
+}
+
+// stepDir returns the directory a step runs in: its workdir, resolved from
+// the project root if relative, or "" for the current directory. It fails if
+// the directory does not exist.
+func stepDir(step config.Step) (string, error) {
+	if step.WorkDir == "" {
+		return "", nil
+	}
+
+	dir := step.WorkDir
+	if !filepath.IsAbs(dir) && yamlProjectRoot != "" {
+		dir = filepath.Join(yamlProjectRoot, dir)
+	}
+
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return "", glideErrors.NewUserError(
+			fmt.Sprintf("working directory %s does not exist", dir),
+			"Check the step's workdir; relative paths are resolved from the project root",
+		)
+	}
+	return dir, nil
+}
+
+// stepFailures collects the failed steps of a YAML command
+type stepFailures struct {
+	cmd *config.Command
+
+	mu    sync.Mutex
+	errs  []error
+	count int
+	stop  bool
+}
+
+// record reports a failed step and stops the command unless the step
+// ignores errors
+func (f *stepFailures) record(i int, err error) {
+	step := f.cmd.Steps[i]
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.errs = append(f.errs, fmt.Errorf("step %d (%s): %w", i+1, maskSecrets(stepSummary(step.Cmd), f.cmd.Secrets), err))
+	f.count++
+	if step.IgnoreErrors {
+		fmt.Fprintf(os.Stderr, "✗ %s failed, continuing: %v\n", stepLabel(f.cmd, i), err)
+		return
+	}
+	fmt.Fprintf(os.Stderr, "✗ %s failed: %v\n", stepLabel(f.cmd, i), err)
+	f.stop = true
+}
+
+// interrupt stops the command after a signal cancelled running steps
+func (f *stepFailures) interrupt() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.errs = append(f.errs, errStepsInterrupted)
+	f.stop = true
+}
+
+// announceStep prints the step about to run, with secrets masked
+func announceStep(cmd *config.Command, i int) {
+	fmt.Fprintf(os.Stderr, "→ %s %s\n", stepLabel(cmd, i), maskSecrets(stepSummary(cmd.Steps[i].Cmd), cmd.Secrets))
+}
+
+// stepLabel numbers a step for progress output, e.g. [2/3]
+func stepLabel(cmd *config.Command, i int) string {
+	return fmt.Sprintf("[%d/%d]", i+1, len(cmd.Steps))
+}
+
+// stepSummary returns the first line of a step's script for progress output
+func stepSummary(script string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(script), "\n")
+	return strings.TrimSpace(line)
+}
+
+// stepName returns the name prefixing a parallel step's output: its name,
+// or the program it runs
+func stepName(step config.Step) string {
+	if step.Name != "" {
+		return step.Name
+	}
+	if fields := strings.Fields(step.Cmd); len(fields) > 0 {
+		return fields[0]
+	}
+	return "step"
+}
+
+// prefixWriter writes each line written to it to w, prefixed with a name.
+// Writers sharing mu never interleave within a line.
+type prefixWriter struct {
+	w       io.Writer
+	mu      *sync.Mutex
+	prefix  []byte
+	partial []byte
+}
+
+func newPrefixWriter(w io.Writer, mu *sync.Mutex, name string) *prefixWriter {
+	return &prefixWriter{w: w, mu: mu, prefix: []byte("[" + name + "] ")}
+}
+
+// Write passes every complete line in p on and keeps the rest until the
+// next write
+func (p *prefixWriter) Write(data []byte) (int, error) {
+	p.partial = append(p.partial, data...)
+	for {
+		i := bytes.IndexByte(p.partial, '\n')
+		if i < 0 {
+			break
+		}
+		if err := p.writeLine(p.partial[:i+1]); err != nil {
+			return len(data), err
+		}
+		p.partial = p.partial[i+1:]
+	}
+	return len(data), nil
+}
+
+// Flush passes on an unterminated last line
+func (p *prefixWriter) Flush() {
+	if len(p.partial) > 0 {
+		_ = p.writeLine(append(p.partial, '\n'))
+		p.partial = nil
+	}
+}
+
+func (p *prefixWriter) writeLine(line []byte) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if _, err := p.w.Write(p.prefix); err != nil {
+		return err
+	}
+	_, err := p.w.Write(line)
+	return err
+}
@@ -0,0 +1,244 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/glide-cli/glide/v3/internal/config"
+	"github.com/glide-cli/glide/v3/internal/shell"
+)
+
+// TestExecuteYAMLSteps verifies that steps run in order, stopping at the
+// first failure unless the step ignores errors
+func TestExecuteYAMLSteps(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("POSIX shell scripts")
+	}
+
+	originalSanitizer := yamlCommandSanitizer
+	defer SetYAMLCommandSanitizer(originalSanitizer)
+	SetYAMLCommandSanitizer(shell.NewSanitizer(shell.ScriptConfig()))
+
+	tests := []struct {
+		name    string
+		steps   []config.Step
+		wantRan string
+		wantErr string
+	}{
+		{
+			name:    "all steps succeed",
+			steps:   []config.Step{{Cmd: "echo one"}, {Cmd: "echo two"}},
+			wantRan: "one\ntwo\n",
+		},
+		{
+			name:    "failure stops later steps",
+			steps:   []config.Step{{Cmd: "echo one"}, {Cmd: "exit 3"}, {Cmd: "echo three"}},
+			wantRan: "one\n",
+			wantErr: "1 of 3 steps failed",
+		},
+		{
+			name: "ignored failures continue",
+			steps: []config.Step{
+				{Cmd: "exit 1", IgnoreErrors: true},
+				{Cmd: "echo two"},
+				{Cmd: "exit 2", IgnoreErrors: true},
+				{Cmd: "echo four"},
+			},
+			wantRan: "two\nfour\n",
+			wantErr: "2 of 4 steps failed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := filepath.Join(t.TempDir(), "out")
+			steps := make([]config.Step, len(tt.steps))
+			for i, step := range tt.steps {
+				steps[i] = config.Step{Cmd: step.Cmd + ` >> "` + out + `"`, IgnoreErrors: step.IgnoreErrors}
+			}
+
+			err := runYAMLCommand(nil, &config.Command{Steps: steps}, nil)
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("runYAMLCommand() error = %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("runYAMLCommand() error = %v, want %q", err, tt.wantErr)
+			}
+
+			got, _ := os.ReadFile(out)
+			if string(got) != tt.wantRan {
+				t.Errorf("output = %q, want %q", got, tt.wantRan)
+			}
+		})
+	}
+}
+
+// TestExecuteYAMLSteps_Parallel verifies that parallel steps run
+// concurrently and the group fails if any of its steps fails
+func TestExecuteYAMLSteps_Parallel(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("POSIX shell scripts")
+	}
+
+	originalSanitizer := yamlCommandSanitizer
+	defer SetYAMLCommandSanitizer(originalSanitizer)
+	SetYAMLCommandSanitizer(shell.NewSanitizer(shell.ScriptConfig()))
+
+	t.Run("steps run concurrently", func(t *testing.T) {
+		dir := t.TempDir()
+		// Each step waits for the other, so they only finish if they overlap
+		wait := func(own, other string) string {
+			return `touch "` + filepath.Join(dir, own) + `"; i=0; while [ ! -e "` + filepath.Join(dir, other) + `" ]; do i=$((i+1)); [ $i -gt 100 ] && exit 1; sleep 0.05; done`
+		}
+		cmd := &config.Command{MaxParallel: 2, Steps: []config.Step{
+			{Cmd: wait("a", "b"), Parallel: true},
+			{Cmd: wait("b", "a"), Parallel: true},
+			{Cmd: `touch "` + filepath.Join(dir, "after") + `"`},
+		}}
+
+		if err := runYAMLCommand(nil, cmd, nil); err != nil {
+			t.Fatalf("runYAMLCommand() error = %v", err)
+		}
+		if _, err := os.Stat(filepath.Join(dir, "after")); err != nil {
+			t.Errorf("step after the parallel group did not run: %v", err)
+		}
+	})
+
+	t.Run("max_parallel bounds concurrency", func(t *testing.T) {
+		dir := t.TempDir()
+		// Fails if another step is running at the same time
+		step := `mkdir "` + filepath.Join(dir, "lock") + `" || exit 1; sleep 0.1; rmdir "` + filepath.Join(dir, "lock") + `"`
+		cmd := &config.Command{MaxParallel: 1, Steps: []config.Step{
+			{Cmd: step, Parallel: true},
+			{Cmd: step, Parallel: true},
+			{Cmd: step, Parallel: true},
+		}}
+
+		if err := runYAMLCommand(nil, cmd, nil); err != nil {
+			t.Fatalf("runYAMLCommand() error = %v", err)
+		}
+	})
+
+	t.Run("failing step fails the group", func(t *testing.T) {
+		out := filepath.Join(t.TempDir(), "out")
+		cmd := &config.Command{Steps: []config.Step{
+			{Cmd: "exit 4", Parallel: true},
+			{Cmd: `sleep 0.1; echo two >> "` + out + `"`, Parallel: true},
+			{Cmd: `echo three >> "` + out + `"`},
+		}}
+
+		err := runYAMLCommand(nil, cmd, nil)
+		if err == nil || !strings.Contains(err.Error(), "1 of 3 steps failed") {
+			t.Fatalf("runYAMLCommand() error = %v, want 1 of 3 steps failed", err)
+		}
+		got, _ := os.ReadFile(out)
+		if string(got) != "two\n" {
+			t.Errorf("output = %q, want the group to finish and later steps to be skipped", got)
+		}
+	})
+
+	t.Run("interrupt cancels running steps", func(t *testing.T) {
+		cmd := &config.Command{MaxParallel: 2, Steps: []config.Step{
+			{Cmd: "sleep 30", Parallel: true},
+			{Cmd: "sleep 0.2; kill -INT $PPID", Parallel: true},
+		}}
+
+		start := time.Now()
+		err := runYAMLCommand(nil, cmd, nil)
+		if !errors.Is(err, errStepsInterrupted) {
+			t.Errorf("runYAMLCommand() error = %v, want %v", err, errStepsInterrupted)
+		}
+		if elapsed := time.Since(start); elapsed > 10*time.Second {
+			t.Errorf("steps ran for %s after the interrupt", elapsed)
+		}
+	})
+}
+
+// TestExecuteYAMLSteps_WorkDir verifies that steps run in their workdir,
+// resolved from the project root
+func TestExecuteYAMLSteps_WorkDir(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("POSIX shell scripts")
+	}
+
+	originalSanitizer := yamlCommandSanitizer
+	defer SetYAMLCommandSanitizer(originalSanitizer)
+	SetYAMLCommandSanitizer(shell.NewSanitizer(shell.ScriptConfig()))
+
+	root := t.TempDir()
+	if err := os.Mkdir(filepath.Join(root, "web"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	SetYAMLProjectRoot(root)
+	defer SetYAMLProjectRoot("")
+
+	t.Run("relative to project root", func(t *testing.T) {
+		cmd := &config.Command{Steps: []config.Step{
+			{Cmd: "touch sequential", WorkDir: "web"},
+			{Cmd: "touch parallel", WorkDir: "web", Parallel: true},
+		}}
+
+		if err := runYAMLCommand(nil, cmd, nil); err != nil {
+			t.Fatalf("runYAMLCommand() error = %v", err)
+		}
+		for _, name := range []string{"sequential", "parallel"} {
+			if _, err := os.Stat(filepath.Join(root, "web", name)); err != nil {
+				t.Errorf("step did not run in its workdir: %v", err)
+			}
+		}
+	})
+
+	t.Run("missing directory", func(t *testing.T) {
+		out := filepath.Join(t.TempDir(), "out")
+		cmd := &config.Command{Steps: []config.Step{
+			{Cmd: `echo ran >> "` + out + `"`, WorkDir: "api"},
+		}}
+
+		err := runYAMLCommand(nil, cmd, nil)
+		if err == nil || !strings.Contains(err.Error(), "working directory "+filepath.Join(root, "api")+" does not exist") {
+			t.Fatalf("runYAMLCommand() error = %v, want missing working directory", err)
+		}
+		if _, err := os.Stat(out); err == nil {
+			t.Error("step ran despite its missing workdir")
+		}
+	})
+}
+
+func TestPrefixWriter(t *testing.T) {
+	var buf bytes.Buffer
+	var mu sync.Mutex
+	w := newPrefixWriter(&buf, &mu, "lint")
+
+	_, _ = w.Write([]byte("first line\nsec"))
+	_, _ = w.Write([]byte("ond line\nunterminated"))
+	w.Flush()
+
+	want := "[lint] first line\n[lint] second line\n[lint] unterminated\n"
+	if buf.String() != want {
+		t.Errorf("output = %q, want %q", buf.String(), want)
+	}
+}
+
+func TestStepName(t *testing.T) {
+	tests := []struct {
+		step config.Step
+		want string
+	}{
+		{config.Step{Name: "lint", Cmd: "golangci-lint run"}, "lint"},
+		{config.Step{Cmd: "  golangci-lint run\n"}, "golangci-lint"},
+		{config.Step{Cmd: ""}, "step"},
+	}
+
+	for _, tt := range tests {
+		if got := stepName(tt.step); got != tt.want {
+			t.Errorf("stepName(%+v) = %q, want %q", tt.step, got, tt.want)
+		}
+	}
+}
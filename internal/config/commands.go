@@ -2,6 +2,8 @@ package config
 
 import (
 	"fmt"
+	"os"
+	"strconv"
 	"strings"
 )
 
@@ -36,8 +38,25 @@ func parseCommand(name string, value interface{}) (*Command, error) {
 		// Structured format with additional properties
 		cmd := &Command{}
 
-		// Parse required cmd field
-		if cmdStr, ok := v["cmd"].(string); ok {
+		// Parse the cmd field, or the steps that replace it
+		if rawSteps, ok := v["steps"]; ok {
+			if _, ok := v["cmd"]; ok {
+				return nil, fmt.Errorf("command cannot have both 'cmd' and 'steps'")
+			}
+			steps, err := parseSteps(rawSteps)
+			if err != nil {
+				return nil, err
+			}
+			cmd.Steps = steps
+
+			if value, ok := v["max_parallel"]; ok {
+				limit, ok := value.(int)
+				if !ok || limit < 1 {
+					return nil, fmt.Errorf("max_parallel must be a positive number")
+				}
+				cmd.MaxParallel = limit
+			}
+		} else if cmdStr, ok := v["cmd"].(string); ok {
 			cmd.Cmd = cmdStr
 		} else {
 			return nil, fmt.Errorf("command must have 'cmd' field")
@@ -56,6 +75,26 @@ func parseCommand(name string, value interface{}) (*Command, error) {
 		if cat, ok := v["category"].(string); ok {
 			cmd.Category = cat
 		}
+		if shell, ok := v["shell"].(string); ok {
+			cmd.Shell = shell
+		}
+		if confirm, ok := v["confirm"].(string); ok {
+			cmd.Confirm = confirm
+		}
+		if value, ok := v["expand_env"]; ok {
+			mode, err := parseEnvExpansion(value)
+			if err != nil {
+				return nil, err
+			}
+			cmd.ExpandEnv = mode
+		}
+		if value, ok := v["secrets"]; ok {
+			secrets, err := parseSecrets(value)
+			if err != nil {
+				return nil, err
+			}
+			cmd.Secrets = secrets
+		}
 
 		return cmd, nil
 
@@ -75,37 +114,326 @@ func parseCommand(name string, value interface{}) (*Command, error) {
 	}
 }
 
+// parseSteps reads the steps of a multi-step command. Each step is either a
+// command string or a map with cmd and its options.
+func parseSteps(value interface{}) ([]Step, error) {
+	items, ok := value.([]interface{})
+	if !ok || len(items) == 0 {
+		return nil, fmt.Errorf("'steps' must be a non-empty list")
+	}
+
+	steps := make([]Step, 0, len(items))
+	for i, item := range items {
+		if m, ok := item.(map[interface{}]interface{}); ok {
+			strMap := make(map[string]interface{}, len(m))
+			for k, v := range m {
+				if keyStr, ok := k.(string); ok {
+					strMap[keyStr] = v
+				}
+			}
+			item = strMap
+		}
+
+		switch v := item.(type) {
+		case string:
+			steps = append(steps, Step{Cmd: v})
+		case map[string]interface{}:
+			cmdStr, ok := v["cmd"].(string)
+			if !ok {
+				return nil, fmt.Errorf("step %d must have 'cmd' field", i+1)
+			}
+			step := Step{Cmd: cmdStr}
+			if name, ok := v["name"].(string); ok {
+				step.Name = name
+			}
+			if value, ok := v["ignore_errors"]; ok {
+				if step.IgnoreErrors, ok = value.(bool); !ok {
+					return nil, fmt.Errorf("step %d: ignore_errors must be true or false", i+1)
+				}
+			}
+			if value, ok := v["parallel"]; ok {
+				if step.Parallel, ok = value.(bool); !ok {
+					return nil, fmt.Errorf("step %d: parallel must be true or false", i+1)
+				}
+			}
+			if value, ok := v["workdir"]; ok {
+				if step.WorkDir, ok = value.(string); !ok || step.WorkDir == "" {
+					return nil, fmt.Errorf("step %d: workdir must be a path", i+1)
+				}
+			}
+			steps = append(steps, step)
+		default:
+			return nil, fmt.Errorf("invalid format for step %d", i+1)
+		}
+	}
+	return steps, nil
+}
+
+// parseSecrets reads the list of environment variable names in secrets
+func parseSecrets(value interface{}) ([]string, error) {
+	items, ok := value.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("'secrets' must be a list of environment variable names")
+	}
+
+	secrets := make([]string, 0, len(items))
+	for _, item := range items {
+		name, ok := item.(string)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("'secrets' must be a list of environment variable names")
+		}
+		secrets = append(secrets, name)
+	}
+	return secrets, nil
+}
+
+// EnvExpansion selects whether ExpandCommandEnv expands environment variables
+type EnvExpansion string
+
+const (
+	// EnvExpansionNone leaves environment variables for the shell to expand
+	EnvExpansionNone EnvExpansion = ""
+	// EnvExpansionLenient expands environment variables, undefined ones to ""
+	EnvExpansionLenient EnvExpansion = "lenient"
+	// EnvExpansionStrict expands environment variables and fails on undefined ones
+	EnvExpansionStrict EnvExpansion = "strict"
+)
+
 // ExpandCommand prepares a command for execution with parameter substitution
 func ExpandCommand(cmd string, args []string) string {
-	// Replace positional parameters
-	expanded := cmd
-	for i, arg := range args {
-		placeholder := fmt.Sprintf("$%d", i+1)
-		expanded = strings.ReplaceAll(expanded, placeholder, arg)
+	// Without environment expansion there is nothing to fail on
+	expanded, _ := ExpandCommandEnv(cmd, args, EnvExpansionNone, nil)
+	return expanded
+}
+
+// ExpandCommandEnv substitutes the positional parameters $1 to $9 and $@ or
+// $* (all arguments), and depending on mode also ${VAR} and $VAR from the
+// environment. lookup finds variables and defaults to os.LookupEnv.
+//
+// Braced references may give a default for a parameter or variable that is
+// unset or empty, as in the shell: ${1:-./...} or ${ENV:-${1:-staging}}.
+//
+// The command is expanded in a single pass, so a '$' inside an argument or a
+// variable's value is never expanded again. Missing positional parameters,
+// escaped references (\$VAR) and special parameters such as $$ are left for
+// the shell.
+func ExpandCommandEnv(cmd string, args []string, mode EnvExpansion, lookup func(string) (string, bool)) (string, error) {
+	if lookup == nil {
+		lookup = os.LookupEnv
+	}
+	expandEnv := mode == EnvExpansionLenient || mode == EnvExpansionStrict
+
+	var b strings.Builder
+	for i := 0; i < len(cmd); i++ {
+		c := cmd[i]
+		if c == '\\' && i+1 < len(cmd) && cmd[i+1] == '$' {
+			b.WriteString(cmd[i : i+2])
+			i++
+			continue
+		}
+		if c != '$' || i+1 == len(cmd) {
+			b.WriteByte(c)
+			continue
+		}
+
+		next := cmd[i+1]
+		switch {
+		case next >= '1' && next <= '9':
+			if n := int(next - '1'); n < len(args) {
+				b.WriteString(args[n])
+			} else {
+				b.WriteString(cmd[i : i+2])
+			}
+			i++
+
+		case next == '$':
+			// The shell's process ID, not a reference to $1 etc.
+			b.WriteString("$$")
+			i++
+
+		case next == '@' || next == '*':
+			b.WriteString(strings.Join(args, " "))
+			i++
+
+		case next == '{':
+			end := matchingBrace(cmd, i+1)
+			if end < 0 {
+				b.WriteByte(c)
+				continue
+			}
+			value, ok, err := expandBraced(cmd[i+2:end], args, mode, lookup)
+			if err != nil {
+				return "", err
+			}
+			if !ok {
+				// Left for the shell; keep scanning inside the braces
+				b.WriteByte(c)
+				continue
+			}
+			b.WriteString(value)
+			i = end
+
+		case expandEnv && isEnvNameStart(next):
+			end := i + 2
+			for end < len(cmd) && isEnvNameChar(cmd[end]) {
+				end++
+			}
+			value, err := lookupEnv(cmd[i+1:end], mode, lookup)
+			if err != nil {
+				return "", err
+			}
+			b.WriteString(value)
+			i = end - 1
+
+		default:
+			b.WriteByte(c)
+		}
 	}
 
-	// Handle $@ for all arguments
-	if strings.Contains(expanded, "$@") {
-		expanded = strings.ReplaceAll(expanded, "$@", strings.Join(args, " "))
+	return b.String(), nil
+}
+
+// matchingBrace returns the index of the '}' closing the '{' at open, or -1
+func matchingBrace(cmd string, open int) int {
+	depth := 0
+	for i := open; i < len(cmd); i++ {
+		switch cmd[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
 	}
+	return -1
+}
 
-	// Handle $* as an alias for $@
-	if strings.Contains(expanded, "$*") {
-		expanded = strings.ReplaceAll(expanded, "$*", strings.Join(args, " "))
+// expandBraced expands the contents of a ${...} reference: a positional
+// parameter or, if mode allows, an environment variable, optionally followed
+// by :-default. The default is used when the parameter is unset or empty and
+// is itself expanded. ok is false for references left for the shell: other
+// constructs, missing parameters without a default, and variables when
+// environment expansion is off.
+func expandBraced(expr string, args []string, mode EnvExpansion, lookup func(string) (string, bool)) (value string, ok bool, err error) {
+	name := expr
+	if i := strings.Index(expr, ":-"); i >= 0 {
+		name = expr[:i]
 	}
+	fallback, hasDefault := strings.CutPrefix(expr[len(name):], ":-")
 
-	return expanded
+	var set bool
+	switch {
+	case isPositional(name):
+		n, _ := strconv.Atoi(name)
+		if n <= len(args) {
+			value, set = args[n-1], true
+		}
+	case isEnvName(name) && (mode == EnvExpansionLenient || mode == EnvExpansionStrict):
+		value, set = lookup(name)
+	default:
+		return "", false, nil
+	}
+
+	if hasDefault && value == "" {
+		value, err = ExpandCommandEnv(fallback, args, mode, lookup)
+		return value, err == nil, err
+	}
+	if !set {
+		if isPositional(name) {
+			return "", false, nil
+		}
+		value, err = lookupEnv(name, mode, lookup)
+		return value, err == nil, err
+	}
+	return value, true, nil
+}
+
+// isPositional reports whether name is a positional parameter (1 or above)
+func isPositional(name string) bool {
+	if name == "" || name[0] == '0' {
+		return false
+	}
+	for i := 0; i < len(name); i++ {
+		if name[i] < '0' || name[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// lookupEnv returns the value of the named variable, failing in strict mode
+// if it is not set
+func lookupEnv(name string, mode EnvExpansion, lookup func(string) (string, bool)) (string, error) {
+	value, ok := lookup(name)
+	if !ok && mode == EnvExpansionStrict {
+		return "", fmt.Errorf("environment variable %s is not set", name)
+	}
+	return value, nil
+}
+
+func isEnvName(name string) bool {
+	if name == "" || !isEnvNameStart(name[0]) {
+		return false
+	}
+	for i := 1; i < len(name); i++ {
+		if !isEnvNameChar(name[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func isEnvNameStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isEnvNameChar(c byte) bool {
+	return isEnvNameStart(c) || (c >= '0' && c <= '9')
+}
+
+// parseEnvExpansion reads the expand_env setting, which may be a boolean or
+// the name of a mode
+func parseEnvExpansion(value interface{}) (EnvExpansion, error) {
+	switch v := value.(type) {
+	case bool:
+		if v {
+			return EnvExpansionLenient, nil
+		}
+		return EnvExpansionNone, nil
+	case string:
+		switch strings.ToLower(v) {
+		case "", "false":
+			return EnvExpansionNone, nil
+		case "true", string(EnvExpansionLenient):
+			return EnvExpansionLenient, nil
+		case string(EnvExpansionStrict):
+			return EnvExpansionStrict, nil
+		}
+	}
+	return EnvExpansionNone, fmt.Errorf("invalid expand_env %v: must be true, false, lenient or strict", value)
 }
 
 // ValidateCommand checks if a command is valid
 func ValidateCommand(cmd *Command) error {
-	if cmd.Cmd == "" {
-		return fmt.Errorf("command cannot be empty")
+	scripts := []string{cmd.Cmd}
+	if len(cmd.Steps) > 0 {
+		scripts = scripts[:0]
+		for _, step := range cmd.Steps {
+			scripts = append(scripts, step.Cmd)
+		}
 	}
 
-	// Check for circular references (basic check)
-	if strings.Contains(cmd.Cmd, "glide"+cmd.Alias) || strings.Contains(cmd.Cmd, "glide "+cmd.Alias) {
-		return fmt.Errorf("command may contain circular reference")
+	for _, script := range scripts {
+		if script == "" {
+			return fmt.Errorf("command cannot be empty")
+		}
+
+		// Check for circular references (basic check)
+		if strings.Contains(script, "glide"+cmd.Alias) || strings.Contains(script, "glide "+cmd.Alias) {
+			return fmt.Errorf("command may contain circular reference")
+		}
 	}
 
 	return nil
@@ -31,6 +31,9 @@ func TestParseCommands(t *testing.T) {
 					"description": "Deploy app",
 					"help":        "Detailed help",
 					"category":    "deployment",
+					"shell":       "bash",
+					"confirm":     "Deploy now?",
+					"expand_env":  "strict",
 				},
 			},
 			expected: map[string]*Command{
@@ -40,6 +43,9 @@ func TestParseCommands(t *testing.T) {
 					Description: "Deploy app",
 					Help:        "Detailed help",
 					Category:    "deployment",
+					Shell:       "bash",
+					Confirm:     "Deploy now?",
+					ExpandEnv:   EnvExpansionStrict,
 				},
 			},
 			wantErr: false,
@@ -64,6 +70,132 @@ func TestParseCommands(t *testing.T) {
 			expected: nil,
 			wantErr:  true,
 		},
+		{
+			name: "expand_env as a boolean",
+			input: CommandMap{
+				"deploy": map[string]interface{}{"cmd": "deploy.sh", "expand_env": true},
+				"build":  map[string]interface{}{"cmd": "build.sh", "expand_env": false},
+			},
+			expected: map[string]*Command{
+				"deploy": {Cmd: "deploy.sh", ExpandEnv: EnvExpansionLenient},
+				"build":  {Cmd: "build.sh"},
+			},
+		},
+		{
+			name: "invalid expand_env",
+			input: CommandMap{
+				"deploy": map[string]interface{}{"cmd": "deploy.sh", "expand_env": "always"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "steps",
+			input: CommandMap{
+				"ci": map[string]interface{}{
+					"steps": []interface{}{
+						"go vet ./...",
+						map[interface{}]interface{}{"cmd": "golangci-lint run", "ignore_errors": true},
+						map[string]interface{}{"cmd": "go test ./..."},
+					},
+				},
+			},
+			expected: map[string]*Command{
+				"ci": {Steps: []Step{
+					{Cmd: "go vet ./..."},
+					{Cmd: "golangci-lint run", IgnoreErrors: true},
+					{Cmd: "go test ./..."},
+				}},
+			},
+		},
+		{
+			name: "steps and cmd together",
+			input: CommandMap{
+				"ci": map[string]interface{}{"cmd": "make", "steps": []interface{}{"make test"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid steps",
+			input: CommandMap{
+				"ci": map[string]interface{}{"steps": "make test"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "step without cmd",
+			input: CommandMap{
+				"ci": map[string]interface{}{"steps": []interface{}{map[string]interface{}{"ignore_errors": true}}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid ignore_errors",
+			input: CommandMap{
+				"ci": map[string]interface{}{"steps": []interface{}{map[string]interface{}{"cmd": "make", "ignore_errors": "yes"}}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "parallel steps",
+			input: CommandMap{
+				"lint": map[string]interface{}{
+					"max_parallel": 2,
+					"steps": []interface{}{
+						map[string]interface{}{"name": "vet", "cmd": "go vet ./...", "parallel": true},
+						map[string]interface{}{"cmd": "golangci-lint run", "parallel": true},
+					},
+				},
+			},
+			expected: map[string]*Command{
+				"lint": {MaxParallel: 2, Steps: []Step{
+					{Name: "vet", Cmd: "go vet ./...", Parallel: true},
+					{Cmd: "golangci-lint run", Parallel: true},
+				}},
+			},
+		},
+		{
+			name: "step workdir",
+			input: CommandMap{
+				"build": map[string]interface{}{
+					"steps": []interface{}{map[string]interface{}{"cmd": "npm run build", "workdir": "web"}},
+				},
+			},
+			expected: map[string]*Command{
+				"build": {Steps: []Step{{Cmd: "npm run build", WorkDir: "web"}}},
+			},
+		},
+		{
+			name: "invalid workdir",
+			input: CommandMap{
+				"build": map[string]interface{}{
+					"steps": []interface{}{map[string]interface{}{"cmd": "npm run build", "workdir": 1}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "secrets",
+			input: CommandMap{
+				"db": map[string]interface{}{"cmd": "mysql -p$DB_PASS", "secrets": []interface{}{"DB_PASS"}},
+			},
+			expected: map[string]*Command{
+				"db": {Cmd: "mysql -p$DB_PASS", Secrets: []string{"DB_PASS"}},
+			},
+		},
+		{
+			name: "invalid secrets",
+			input: CommandMap{
+				"db": map[string]interface{}{"cmd": "mysql -p$DB_PASS", "secrets": "DB_PASS"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid max_parallel",
+			input: CommandMap{
+				"lint": map[string]interface{}{"max_parallel": 0, "steps": []interface{}{"go vet ./..."}},
+			},
+			wantErr: true,
+		},
 		{
 			name: "mixed simple and structured commands",
 			input: CommandMap{
@@ -144,6 +276,60 @@ func TestExpandCommand(t *testing.T) {
 			args:     []string{"one"},
 			expected: "echo one $2 $3",
 		},
+		{
+			name:     "arguments containing placeholders are not expanded again",
+			cmd:      "echo $1 $2",
+			args:     []string{"$2", "price: $@"},
+			expected: "echo $2 price: $@",
+		},
+		{
+			name:     "default with argument present",
+			cmd:      "go test ${1:-./...}",
+			args:     []string{"./internal/config"},
+			expected: "go test ./internal/config",
+		},
+		{
+			name:     "default with argument absent",
+			cmd:      "go test ${1:-./...}",
+			args:     []string{},
+			expected: "go test ./...",
+		},
+		{
+			name:     "default with empty argument",
+			cmd:      "deploy ${1:-staging}",
+			args:     []string{""},
+			expected: "deploy staging",
+		},
+		{
+			name:     "absent argument without default",
+			cmd:      "echo ${1} ${2}",
+			args:     []string{"one"},
+			expected: "echo one ${2}",
+		},
+		{
+			name:     "nested defaults",
+			cmd:      "deploy ${2:-${1:-staging}}",
+			args:     []string{"production"},
+			expected: "deploy production",
+		},
+		{
+			name:     "braced parameters beyond nine",
+			cmd:      "echo ${10}",
+			args:     []string{"1", "2", "3", "4", "5", "6", "7", "8", "9", "ten"},
+			expected: "echo ten",
+		},
+		{
+			name:     "unknown constructs are left untouched",
+			cmd:      "echo ${#1} ${1:+set} ${KUBE_CTX:-$1} ${unclosed",
+			args:     []string{"one"},
+			expected: "echo ${#1} ${1:+set} ${KUBE_CTX:-one} ${unclosed",
+		},
+		{
+			name:     "environment variables are left for the shell",
+			cmd:      "kubectl --context ${KUBE_CTX} apply -f $1",
+			args:     []string{"app.yaml"},
+			expected: "kubectl --context ${KUBE_CTX} apply -f app.yaml",
+		},
 	}
 
 	for _, tt := range tests {
@@ -156,6 +342,114 @@ func TestExpandCommand(t *testing.T) {
 	}
 }
 
+func TestExpandCommandEnv(t *testing.T) {
+	env := map[string]string{
+		"KUBE_CTX": "staging",
+		"PRICE":    "$1",
+		"EMPTY":    "",
+	}
+	lookup := func(name string) (string, bool) {
+		value, ok := env[name]
+		return value, ok
+	}
+
+	tests := []struct {
+		name     string
+		cmd      string
+		args     []string
+		mode     EnvExpansion
+		expected string
+		wantErr  bool
+	}{
+		{
+			name:     "braced variable",
+			cmd:      "kubectl --context ${KUBE_CTX} apply",
+			mode:     EnvExpansionLenient,
+			expected: "kubectl --context staging apply",
+		},
+		{
+			name:     "bare variable",
+			cmd:      "echo $KUBE_CTX-cluster",
+			mode:     EnvExpansionLenient,
+			expected: "echo staging-cluster",
+		},
+		{
+			name:     "undefined variable is blank when lenient",
+			cmd:      "echo [$MISSING] [${MISSING}]",
+			mode:     EnvExpansionLenient,
+			expected: "echo [] []",
+		},
+		{
+			name:    "undefined variable fails when strict",
+			cmd:     "echo ${MISSING}",
+			mode:    EnvExpansionStrict,
+			wantErr: true,
+		},
+		{
+			name:     "empty variable is defined",
+			cmd:      "echo [$EMPTY]",
+			mode:     EnvExpansionStrict,
+			expected: "echo []",
+		},
+		{
+			name:     "values and arguments are not expanded again",
+			cmd:      "echo $PRICE $1",
+			args:     []string{"$KUBE_CTX"},
+			mode:     EnvExpansionStrict,
+			expected: "echo $1 $KUBE_CTX",
+		},
+		{
+			name:     "positional parameters and variables together",
+			cmd:      "deploy --env ${KUBE_CTX} $@",
+			args:     []string{"web", "worker"},
+			mode:     EnvExpansionLenient,
+			expected: "deploy --env staging web worker",
+		},
+		{
+			name:     "escaped and special references are left for the shell",
+			cmd:      `echo \$KUBE_CTX $$ $? ${1} ${not valid}`,
+			mode:     EnvExpansionStrict,
+			expected: `echo \$KUBE_CTX $$ $? ${1} ${not valid}`,
+		},
+		{
+			name:     "variable defaults",
+			cmd:      "echo ${KUBE_CTX:-local} ${MISSING:-local} ${EMPTY:-local}",
+			mode:     EnvExpansionStrict,
+			expected: "echo staging local local",
+		},
+		{
+			name:     "default expands variables",
+			cmd:      "echo ${1:-$KUBE_CTX}",
+			mode:     EnvExpansionLenient,
+			expected: "echo staging",
+		},
+		{
+			name:    "undefined variable in default fails when strict",
+			cmd:     "echo ${1:-${MISSING}}",
+			mode:    EnvExpansionStrict,
+			wantErr: true,
+		},
+		{
+			name:     "disabled",
+			cmd:      "echo ${KUBE_CTX} $KUBE_CTX",
+			mode:     EnvExpansionNone,
+			expected: "echo ${KUBE_CTX} $KUBE_CTX",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ExpandCommandEnv(tt.cmd, tt.args, tt.mode, lookup)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ExpandCommandEnv() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.expected {
+				t.Errorf("ExpandCommandEnv() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
 func TestValidateCommand(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -182,6 +476,21 @@ func TestValidateCommand(t *testing.T) {
 			cmd:     &Command{Cmd: "glideother", Alias: "test"},
 			wantErr: false,
 		},
+		{
+			name:    "valid steps",
+			cmd:     &Command{Steps: []Step{{Cmd: "make lint"}, {Cmd: "make test"}}},
+			wantErr: false,
+		},
+		{
+			name:    "empty step",
+			cmd:     &Command{Steps: []Step{{Cmd: "make lint"}, {Cmd: ""}}},
+			wantErr: true,
+		},
+		{
+			name:    "circular reference in a step",
+			cmd:     &Command{Steps: []Step{{Cmd: "make"}, {Cmd: "glide test"}}, Alias: "test"},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -112,6 +112,9 @@ func LoadAndMergeConfigs(configPaths []string) (*Config, error) {
 		// The config loader extracts plugin configs from raw YAML and syncs them
 		// to the typed registry automatically.
 
+		// Plugin order, disabled and external plugins are global settings,
+		// read from the global config only.
+
 		// Take the first non-empty default project
 		if merged.DefaultProject == "" && cfg.DefaultProject != "" {
 			merged.DefaultProject = cfg.DefaultProject
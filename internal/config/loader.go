@@ -276,6 +276,13 @@ func (l *Loader) ConfigExists() bool {
 	return err == nil
 }
 
+// Keys of core plugin settings in the plugins section
+const (
+	pluginsOrderKey    = "order"
+	pluginsDisabledKey = "disabled"
+	pluginsExternalKey = "external"
+)
+
 // syncPluginConfigsFromRaw synchronizes plugin configurations from raw YAML data
 // to the typed configuration registry.
 //
@@ -296,6 +303,11 @@ func (l *Loader) syncPluginConfigsFromRaw(rawConfig map[string]interface{}) {
 
 	// For each plugin config in the YAML
 	for pluginName, rawPluginConfig := range plugins {
+		// Core plugin settings, not a plugin's config
+		if pluginName == pluginsOrderKey || pluginName == pluginsDisabledKey || pluginName == pluginsExternalKey {
+			continue
+		}
+
 		// Check if this plugin has registered a typed config
 		if !pkgconfig.Exists(pluginName) {
 			logging.Debug("Plugin config not registered in typed registry",
@@ -57,6 +57,50 @@ defaults:
 	assert.Equal(t, 30, cfg.Defaults.Docker.ComposeTimeout)
 }
 
+func TestLoader_Load_PluginOrder(t *testing.T) {
+	tempDir := t.TempDir()
+	oldHome := os.Getenv("HOME")
+	os.Setenv("HOME", tempDir)
+	defer os.Setenv("HOME", oldHome)
+
+	configPath := filepath.Join(tempDir, ".glide.yml")
+	yamlContent := `
+plugins:
+  order: [docker, k8s]
+  docker:
+    compose_timeout: 10
+`
+	err := os.WriteFile(configPath, []byte(yamlContent), 0644)
+	require.NoError(t, err)
+
+	loader := NewLoader()
+	cfg, err := loader.Load()
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"docker", "k8s"}, cfg.Plugins.Order)
+}
+
+func TestLoader_Load_DisabledPlugins(t *testing.T) {
+	tempDir := t.TempDir()
+	oldHome := os.Getenv("HOME")
+	os.Setenv("HOME", tempDir)
+	defer os.Setenv("HOME", oldHome)
+
+	configPath := filepath.Join(tempDir, ".glide.yml")
+	yamlContent := `
+plugins:
+  disabled: [docker]
+`
+	err := os.WriteFile(configPath, []byte(yamlContent), 0644)
+	require.NoError(t, err)
+
+	loader := NewLoader()
+	cfg, err := loader.Load()
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"docker"}, cfg.Plugins.Disabled)
+}
+
 func TestLoader_Validate_InvalidProjectMode(t *testing.T) {
 	tempDir := t.TempDir()
 	oldHome := os.Getenv("HOME")
@@ -8,11 +8,58 @@ type Command struct {
 	// The actual command(s) to execute
 	Cmd string `yaml:"cmd"`
 
+	// Steps are run one after another instead of Cmd. A failing step stops
+	// the command unless it sets IgnoreErrors.
+	Steps []Step `yaml:"steps,omitempty"`
+
+	// MaxParallel limits how many parallel steps run at once. Defaults to
+	// the number of CPUs.
+	MaxParallel int `yaml:"max_parallel,omitempty"`
+
 	// Optional fields for structured format
 	Alias       string `yaml:"alias,omitempty"`
 	Description string `yaml:"description,omitempty"`
 	Help        string `yaml:"help,omitempty"`
 	Category    string `yaml:"category,omitempty"`
+
+	// Shell is the interpreter the script is passed to with -c (e.g. "bash").
+	// Defaults to the script's #! line, or sh if there is none.
+	Shell string `yaml:"shell,omitempty"`
+
+	// Confirm is a question the user must answer yes to before the command
+	// runs (e.g. "Drop and recreate the database?"). Pass --yes to skip it.
+	Confirm string `yaml:"confirm,omitempty"`
+
+	// ExpandEnv makes glide expand ${VAR} and $VAR before running the command:
+	// true (or lenient) expands undefined variables to "", strict refuses to
+	// run. By default variables are left for the shell.
+	ExpandEnv EnvExpansion `yaml:"expand_env,omitempty"`
+
+	// Secrets names environment variables whose references and values are
+	// shown as **** when glide prints the command
+	Secrets []string `yaml:"secrets,omitempty"`
+}
+
+// Step is one command of a multi-step YAML command
+type Step struct {
+	Cmd string `yaml:"cmd"`
+
+	// Name prefixes the output of parallel steps. Defaults to the program
+	// the step runs.
+	Name string `yaml:"name,omitempty"`
+
+	// Parallel runs the step together with the parallel steps next to it.
+	// The group finishes before the next step starts and fails if any of
+	// its steps fails.
+	Parallel bool `yaml:"parallel,omitempty"`
+
+	// WorkDir is the directory the step runs in. Relative paths are
+	// resolved from the project root.
+	WorkDir string `yaml:"workdir,omitempty"`
+
+	// IgnoreErrors runs the following steps even if this one fails. The
+	// command still fails once all steps have run.
+	IgnoreErrors bool `yaml:"ignore_errors,omitempty"`
 }
 
 // Config represents the global Glide configuration
@@ -21,6 +68,7 @@ type Config struct {
 	DefaultProject string                   `yaml:"default_project"`
 	Defaults       DefaultsConfig           `yaml:"defaults"`
 	Commands       CommandMap               `yaml:"commands,omitempty"`
+	Plugins        PluginsConfig            `yaml:"plugins,omitempty"`
 
 	// NOTE: Plugin configuration has been migrated to the type-safe pkg/config system.
 	// Plugins register their typed configs using config.Register() in their init() functions,
@@ -28,6 +76,22 @@ type Config struct {
 	// See pkg/config/MIGRATION.md for details.
 }
 
+// PluginsConfig holds core settings from the plugins section. Every other key
+// in that section is a plugin's own configuration (see syncPluginConfigsFromRaw).
+type PluginsConfig struct {
+	// Order lists plugins to load first, in order; the rest load alphabetically.
+	// When plugins add the same command, the first one loaded wins.
+	Order []string `yaml:"order,omitempty"`
+
+	// Disabled lists plugins that are not loaded: their commands are not
+	// registered and their context extensions do not run.
+	Disabled []string `yaml:"disabled,omitempty"`
+
+	// External lists plugin executables to run as external plugins, which
+	// talk to glide over JSON-RPC on stdin and stdout.
+	External []string `yaml:"external,omitempty"`
+}
+
 // ProjectConfig represents a single project configuration
 type ProjectConfig struct {
 	Path     string     `yaml:"path"`
@@ -42,6 +106,19 @@ type DefaultsConfig struct {
 	Colors   ColorDefaults    `yaml:"colors"`
 	Worktree WorktreeDefaults `yaml:"worktree"`
 	Update   UpdateDefaults   `yaml:"update"`
+
+	Detection DetectionDefaults `yaml:"detection"`
+}
+
+// DetectionDefaults contains project context detection settings
+type DetectionDefaults struct {
+	// Mode is the detection mode: fast, standard (default) or full, which
+	// also collects container status and uncommitted changes
+	Mode string `yaml:"mode"`
+
+	// GitDirty runs `git status` during detection to find uncommitted
+	// changes; off by default because of its cost in large repositories
+	GitDirty bool `yaml:"git_dirty"`
 }
 
 // UpdateDefaults contains update notification settings
@@ -0,0 +1,220 @@
+package context
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/glide-cli/glide/v3/pkg/branding"
+	"github.com/glide-cli/glide/v3/pkg/logging"
+)
+
+const (
+	// DefaultExtensionCacheTTL is how long cached extension detection results
+	// are reused
+	DefaultExtensionCacheTTL = 30 * time.Second
+
+	// extensionCacheFile is the cache file name inside the project's
+	// .glide directory
+	extensionCacheFile = "context-cache.json"
+)
+
+// composeFilePatterns match the compose files whose changes invalidate the
+// cache, in the project root and in the vcs/ and worktrees/*/ directories
+var composeFilePatterns = []string{
+	"docker-compose*.yml", "docker-compose*.yaml",
+	"compose*.yml", "compose*.yaml",
+}
+
+// extensionCacheEntry is the on-disk form of cached detection results
+type extensionCacheEntry struct {
+	ProjectRoot string                 `json:"project_root"`
+	Fingerprint string                 `json:"fingerprint"`
+	DetectedAt  time.Time              `json:"detected_at"`
+	Extensions  map[string]interface{} `json:"extensions"`
+}
+
+// cachedExtensionRegistry reuses recent detection results of another
+// registry. Results are cached per project in <root>/.glide/context-cache.json
+// and reused until the TTL passes or the project fingerprint changes.
+//
+// Results are always returned in their JSON form (objects decode as
+// map[string]interface{}, arrays as []interface{}), whether they come from
+// the cache or from fresh detection, so consumers see the same types either
+// way. The ProjectContext accessors understand this form.
+type cachedExtensionRegistry struct {
+	registry ExtensionRegistry
+	// key identifies the set of extensions, so enabling or disabling a
+	// plugin does not reuse results detected without it
+	key string
+	ttl time.Duration
+	now func() time.Time
+}
+
+// newCachedExtensionRegistry wraps registry with an on-disk cache for the
+// named extensions
+func newCachedExtensionRegistry(registry ExtensionRegistry, extensionNames []string, ttl time.Duration) *cachedExtensionRegistry {
+	names := append([]string(nil), extensionNames...)
+	sort.Strings(names)
+	return &cachedExtensionRegistry{
+		registry: registry,
+		key:      strings.Join(names, ","),
+		ttl:      ttl,
+		now:      time.Now,
+	}
+}
+
+// DetectAll returns cached results if they are fresh, otherwise runs
+// detection and caches the results
+func (c *cachedExtensionRegistry) DetectAll(projectRoot string) (map[string]interface{}, error) {
+	fingerprint, err := c.fingerprint(projectRoot)
+	if err != nil {
+		logging.Debug("Context cache disabled", "projectRoot", projectRoot, "error", err)
+		return c.registry.DetectAll(projectRoot)
+	}
+
+	path := extensionCachePath(projectRoot)
+	if entry, ok := c.load(path); ok &&
+		entry.ProjectRoot == projectRoot &&
+		entry.Fingerprint == fingerprint &&
+		c.now().Sub(entry.DetectedAt) < c.ttl {
+		logging.Debug("Using cached context extensions", "path", path, "count", len(entry.Extensions))
+		return entry.Extensions, nil
+	}
+
+	results, err := c.registry.DetectAll(projectRoot)
+	if err != nil {
+		return results, err
+	}
+	normalized, err := normalizeExtensions(results)
+	if err != nil {
+		// Never cached, so callers only ever see the detected form
+		logging.Debug("Context extensions are not cacheable", "error", err)
+		return results, nil
+	}
+	results = normalized
+
+	entry := extensionCacheEntry{
+		ProjectRoot: projectRoot,
+		Fingerprint: fingerprint,
+		DetectedAt:  c.now(),
+		Extensions:  results,
+	}
+	if err := c.save(path, entry); err != nil {
+		// Caching is best effort, e.g. in a read-only checkout
+		logging.Debug("Failed to write context cache", "path", path, "error", err)
+	}
+	return results, nil
+}
+
+// normalizeExtensions converts detection results to their JSON form, the
+// form they have when read back from the cache
+func normalizeExtensions(results map[string]interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(results)
+	if err != nil {
+		return nil, err
+	}
+	var normalized map[string]interface{}
+	if err := json.Unmarshal(data, &normalized); err != nil {
+		return nil, err
+	}
+	return normalized, nil
+}
+
+// load reads the cache file, reporting false if it is missing or unreadable
+func (c *cachedExtensionRegistry) load(path string) (*extensionCacheEntry, bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+	var entry extensionCacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		logging.Debug("Ignoring corrupt context cache", "path", path, "error", err)
+		return nil, false
+	}
+	return &entry, true
+}
+
+// save writes entry to the cache file atomically
+func (c *cachedExtensionRegistry) save(path string, entry extensionCacheEntry) error {
+	data, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode cache: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), extensionCacheFile+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// fingerprint hashes what detection depends on: the registered extensions
+// and the names, sizes and modification times of the project root's entries
+// and of every compose file
+func (c *cachedExtensionRegistry) fingerprint(projectRoot string) (string, error) {
+	hash := sha256.New()
+	fmt.Fprintf(hash, "extensions=%s\n", c.key)
+
+	entries, err := os.ReadDir(projectRoot)
+	if err != nil {
+		return "", err
+	}
+	for _, entry := range entries {
+		// The cache itself lives in the .glide directory
+		if entry.Name() == branding.GetPluginDirName() {
+			continue
+		}
+		writeFileStamp(hash, filepath.Join(projectRoot, entry.Name()))
+	}
+
+	dirs := []string{filepath.Join(projectRoot, "vcs")}
+	worktrees, _ := filepath.Glob(filepath.Join(projectRoot, "worktrees", "*"))
+	dirs = append(dirs, worktrees...)
+	dirs = append(dirs, projectRoot)
+	for _, dir := range dirs {
+		for _, pattern := range composeFilePatterns {
+			matches, _ := filepath.Glob(filepath.Join(dir, pattern))
+			for _, match := range matches {
+				writeFileStamp(hash, match)
+			}
+		}
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// writeFileStamp writes path's name, size and modification time to w
+func writeFileStamp(w io.Writer, path string) {
+	info, err := os.Stat(path)
+	if err != nil {
+		fmt.Fprintf(w, "%s missing\n", path)
+		return
+	}
+	fmt.Fprintf(w, "%s %d %d\n", path, info.Size(), info.ModTime().UnixNano())
+}
+
+// extensionCachePath returns the cache file for the project at projectRoot
+func extensionCachePath(projectRoot string) string {
+	return filepath.Join(projectRoot, branding.GetPluginDirName(), extensionCacheFile)
+}
@@ -0,0 +1,125 @@
+package context
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingRegistry counts detections and returns fixed results
+type countingRegistry struct {
+	calls   int
+	results map[string]interface{}
+}
+
+func (r *countingRegistry) DetectAll(projectRoot string) (map[string]interface{}, error) {
+	r.calls++
+	return r.results, nil
+}
+
+func newCacheTestProject(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(root, ".glide.yml"), []byte("{}"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "docker-compose.yml"), []byte("services: {}"), 0644))
+	return root
+}
+
+func TestCachedExtensionRegistry(t *testing.T) {
+	t.Run("reuses results within the TTL", func(t *testing.T) {
+		root := newCacheTestProject(t)
+		inner := &countingRegistry{results: map[string]interface{}{
+			"docker": map[string]interface{}{"compose_files": []string{"docker-compose.yml"}},
+		}}
+		cache := newCachedExtensionRegistry(inner, []string{"docker"}, time.Minute)
+
+		first, err := cache.DetectAll(root)
+		require.NoError(t, err)
+		second, err := cache.DetectAll(root)
+		require.NoError(t, err)
+
+		assert.Equal(t, 1, inner.calls)
+		assert.FileExists(t, filepath.Join(root, ".glide", "context-cache.json"))
+
+		// Fresh and cached results both come back in their JSON form
+		want := map[string]interface{}{"compose_files": []interface{}{"docker-compose.yml"}}
+		assert.Equal(t, want, first["docker"])
+		assert.Equal(t, want, second["docker"])
+
+		ctx := &ProjectContext{Extensions: second}
+		files, ok := ctx.StringSlice("docker", "compose_files")
+		assert.True(t, ok)
+		assert.Equal(t, []string{"docker-compose.yml"}, files)
+	})
+
+	t.Run("expires after the TTL", func(t *testing.T) {
+		root := newCacheTestProject(t)
+		inner := &countingRegistry{results: map[string]interface{}{"node": "20"}}
+		cache := newCachedExtensionRegistry(inner, []string{"node"}, time.Minute)
+
+		now := time.Now()
+		cache.now = func() time.Time { return now }
+		_, err := cache.DetectAll(root)
+		require.NoError(t, err)
+
+		now = now.Add(2 * time.Minute)
+		_, err = cache.DetectAll(root)
+		require.NoError(t, err)
+
+		assert.Equal(t, 2, inner.calls)
+	})
+
+	t.Run("compose file changes invalidate", func(t *testing.T) {
+		root := newCacheTestProject(t)
+		inner := &countingRegistry{results: map[string]interface{}{"docker": "up"}}
+		cache := newCachedExtensionRegistry(inner, []string{"docker"}, time.Minute)
+
+		_, err := cache.DetectAll(root)
+		require.NoError(t, err)
+
+		later := time.Now().Add(time.Hour)
+		require.NoError(t, os.Chtimes(filepath.Join(root, "docker-compose.yml"), later, later))
+		_, err = cache.DetectAll(root)
+		require.NoError(t, err)
+
+		// Compose files in worktrees count too
+		worktree := filepath.Join(root, "worktrees", "feature")
+		require.NoError(t, os.MkdirAll(worktree, 0755))
+		_, err = cache.DetectAll(root)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(filepath.Join(worktree, "docker-compose.yml"), []byte("services: {}"), 0644))
+		_, err = cache.DetectAll(root)
+		require.NoError(t, err)
+
+		assert.Equal(t, 4, inner.calls)
+	})
+
+	t.Run("different extensions do not share results", func(t *testing.T) {
+		root := newCacheTestProject(t)
+		inner := &countingRegistry{results: map[string]interface{}{}}
+
+		_, err := newCachedExtensionRegistry(inner, []string{"docker"}, time.Minute).DetectAll(root)
+		require.NoError(t, err)
+		_, err = newCachedExtensionRegistry(inner, []string{"docker", "node"}, time.Minute).DetectAll(root)
+		require.NoError(t, err)
+
+		assert.Equal(t, 2, inner.calls)
+	})
+
+	t.Run("corrupt cache is ignored", func(t *testing.T) {
+		root := newCacheTestProject(t)
+		path := extensionCachePath(root)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte("{not json"), 0644))
+
+		inner := &countingRegistry{results: map[string]interface{}{"docker": "up"}}
+		results, err := newCachedExtensionRegistry(inner, []string{"docker"}, time.Minute).DetectAll(root)
+		require.NoError(t, err)
+		assert.Equal(t, "up", results["docker"])
+		assert.Equal(t, 1, inner.calls)
+	})
+}
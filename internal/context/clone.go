@@ -0,0 +1,102 @@
+package context
+
+import "reflect"
+
+// Clone returns a deep copy of the context.
+//
+// Slices and maps, including arbitrarily nested extension data, are copied so
+// that mutating the clone never affects the original. Structs inside
+// extensions are copied field by field; unexported fields are copied shallowly.
+// The Error field is shared since errors are treated as immutable. Cyclic
+// extension data is not supported.
+//
+// Contexts returned by Detector.Detect are freshly allocated and safe to
+// mutate; use Clone when handing a context to code that may modify it while
+// the original is still in use (caching, watchers, post-processors).
+func (c *ProjectContext) Clone() *ProjectContext {
+	if c == nil {
+		return nil
+	}
+
+	clone := *c
+
+	clone.Extensions = deepCopy(c.Extensions).(map[string]interface{})
+	clone.ComposeFiles = deepCopy(c.ComposeFiles).([]string)
+	clone.ContainersStatus = deepCopy(c.ContainersStatus).(map[string]ContainerStatus)
+	clone.DetectedFrameworks = deepCopy(c.DetectedFrameworks).([]string)
+	clone.FrameworkVersions = deepCopy(c.FrameworkVersions).(map[string]string)
+	clone.FrameworkCommands = deepCopy(c.FrameworkCommands).(map[string]string)
+	clone.FrameworkMetadata = deepCopy(c.FrameworkMetadata).(map[string]map[string]string)
+
+	return &clone
+}
+
+// deepCopy returns a deep copy of v with the same dynamic type
+func deepCopy(v interface{}) interface{} {
+	if v == nil {
+		return nil
+	}
+	return deepCopyValue(reflect.ValueOf(v)).Interface()
+}
+
+// deepCopyValue recursively copies maps, slices, arrays, pointers and structs
+func deepCopyValue(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Map:
+		if v.IsNil() {
+			return reflect.Zero(v.Type())
+		}
+		copied := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			copied.SetMapIndex(iter.Key(), deepCopyValue(iter.Value()))
+		}
+		return copied
+
+	case reflect.Slice:
+		if v.IsNil() {
+			return reflect.Zero(v.Type())
+		}
+		copied := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			copied.Index(i).Set(deepCopyValue(v.Index(i)))
+		}
+		return copied
+
+	case reflect.Array:
+		copied := reflect.New(v.Type()).Elem()
+		for i := 0; i < v.Len(); i++ {
+			copied.Index(i).Set(deepCopyValue(v.Index(i)))
+		}
+		return copied
+
+	case reflect.Ptr:
+		if v.IsNil() {
+			return reflect.Zero(v.Type())
+		}
+		copied := reflect.New(v.Type().Elem())
+		copied.Elem().Set(deepCopyValue(v.Elem()))
+		return copied
+
+	case reflect.Interface:
+		if v.IsNil() {
+			return reflect.Zero(v.Type())
+		}
+		copied := reflect.New(v.Type()).Elem()
+		copied.Set(deepCopyValue(v.Elem()))
+		return copied
+
+	case reflect.Struct:
+		copied := reflect.New(v.Type()).Elem()
+		copied.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			if copied.Field(i).CanSet() {
+				copied.Field(i).Set(deepCopyValue(v.Field(i)))
+			}
+		}
+		return copied
+
+	default:
+		return v
+	}
+}
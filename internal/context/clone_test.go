@@ -0,0 +1,146 @@
+package context
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type cloneTestData struct {
+	Services []string
+	Labels   map[string]string
+	Nested   *cloneTestData
+}
+
+func newCloneTestContext() *ProjectContext {
+	return &ProjectContext{
+		WorkingDir:      "/project/worktrees/feature",
+		ProjectRoot:     "/project",
+		DevelopmentMode: ModeMultiWorktree,
+		Location:        LocationWorktree,
+		IsWorktree:      true,
+		WorktreeName:    "feature",
+		GitBranch:       "feature",
+		Extensions: map[string]interface{}{
+			"docker": map[string]interface{}{
+				"compose_files": []string{"docker-compose.yml"},
+				"services": []interface{}{
+					map[string]interface{}{"name": "php", "ports": []int{9000}},
+				},
+			},
+			"custom": &cloneTestData{
+				Services: []string{"api"},
+				Labels:   map[string]string{"tier": "web"},
+				Nested:   &cloneTestData{Services: []string{"worker"}},
+			},
+		},
+		ComposeFiles: []string{"docker-compose.yml", "docker-compose.override.yml"},
+		ContainersStatus: map[string]ContainerStatus{
+			"php": {Name: "php", Status: "running", StartedAt: time.Unix(1700000000, 0), Ports: []string{"9000"}},
+		},
+		DetectedFrameworks: []string{"laravel"},
+		FrameworkVersions:  map[string]string{"laravel": "11"},
+		FrameworkCommands:  map[string]string{"artisan": "php artisan"},
+		FrameworkMetadata:  map[string]map[string]string{"laravel": {"php": "8.3"}},
+		Error:              errors.New("partial detection"),
+	}
+}
+
+func TestProjectContext_Clone(t *testing.T) {
+	original := newCloneTestContext()
+	clone := original.Clone()
+
+	require.NotSame(t, original, clone)
+	assert.Equal(t, original, clone)
+
+	// Mutate every reference-typed field of the clone
+	clone.ComposeFiles[0] = "changed.yml"
+	clone.ContainersStatus["php"].Ports[0] = "1234"
+	clone.ContainersStatus["nginx"] = ContainerStatus{Name: "nginx"}
+	clone.DetectedFrameworks[0] = "symfony"
+	clone.FrameworkVersions["laravel"] = "10"
+	clone.FrameworkCommands["new"] = "cmd"
+	clone.FrameworkMetadata["laravel"]["php"] = "7.4"
+
+	docker := clone.Extensions["docker"].(map[string]interface{})
+	docker["compose_files"].([]string)[0] = "changed.yml"
+	service := docker["services"].([]interface{})[0].(map[string]interface{})
+	service["ports"].([]int)[0] = 1
+	service["name"] = "changed"
+
+	custom := clone.Extensions["custom"].(*cloneTestData)
+	custom.Services[0] = "changed"
+	custom.Labels["tier"] = "changed"
+	custom.Nested.Services[0] = "changed"
+	clone.Extensions["added"] = true
+
+	// The original is untouched
+	assert.Equal(t, newCloneTestContext(), original)
+}
+
+func TestProjectContext_Clone_Nil(t *testing.T) {
+	var ctx *ProjectContext
+	assert.Nil(t, ctx.Clone())
+
+	empty := (&ProjectContext{}).Clone()
+	assert.Nil(t, empty.Extensions)
+	assert.Nil(t, empty.ComposeFiles)
+}
+
+func TestProjectContext_Clone_JSONRoundTrip(t *testing.T) {
+	original := newCloneTestContext()
+	original.Error = nil
+
+	originalJSON, err := json.Marshal(original)
+	require.NoError(t, err)
+	cloneJSON, err := json.Marshal(original.Clone())
+	require.NoError(t, err)
+	assert.JSONEq(t, string(originalJSON), string(cloneJSON))
+
+	var decoded ProjectContext
+	require.NoError(t, json.Unmarshal(originalJSON, &decoded))
+	assert.Equal(t, original.ProjectRoot, decoded.ProjectRoot)
+	assert.Equal(t, original.ComposeFiles, decoded.ComposeFiles)
+	assert.Equal(t, original.FrameworkMetadata, decoded.FrameworkMetadata)
+	assert.Equal(t, decoded.Extensions, decoded.Clone().Extensions)
+}
+
+// TestCompatibilityFields_NoAliasing verifies that the deprecated Docker
+// fields and the docker extension data never share slices or maps
+func TestCompatibilityFields_NoAliasing(t *testing.T) {
+	t.Run("populate from extensions", func(t *testing.T) {
+		ctx := &ProjectContext{Extensions: map[string]interface{}{
+			"docker": map[string]interface{}{
+				"compose_files":     []string{"compose.yaml"},
+				"containers_status": map[string]ContainerStatus{"php": {Name: "php", Ports: []string{"9000"}}},
+			},
+		}}
+		PopulateCompatibilityFields(ctx)
+
+		ctx.ComposeFiles[0] = "changed.yml"
+		ctx.ContainersStatus["php"].Ports[0] = "1234"
+
+		docker := ctx.Extensions["docker"].(map[string]interface{})
+		assert.Equal(t, []string{"compose.yaml"}, docker["compose_files"])
+		assert.Equal(t, []string{"9000"}, docker["containers_status"].(map[string]ContainerStatus)["php"].Ports)
+	})
+
+	t.Run("update extensions from fields", func(t *testing.T) {
+		ctx := &ProjectContext{
+			ComposeFiles:     []string{"compose.yaml"},
+			ContainersStatus: map[string]ContainerStatus{"php": {Name: "php"}},
+		}
+		UpdateExtensionsFromCompatibility(ctx)
+
+		ctx.ComposeFiles[0] = "changed.yml"
+		ctx.ContainersStatus["nginx"] = ContainerStatus{Name: "nginx"}
+
+		docker := ctx.Extensions["docker"].(map[string]interface{})
+		assert.Equal(t, []string{"compose.yaml"}, docker["compose_files"])
+		assert.Len(t, docker["containers_status"], 1)
+	})
+}
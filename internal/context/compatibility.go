@@ -1,47 +1,59 @@
 package context
 
+import "encoding/json"
+
 // PopulateCompatibilityFields populates the deprecated Docker fields from the extensions map
 // This ensures backward compatibility with code that still uses the old Docker fields directly
+// The fields get their own copies, so changing them leaves the extension data alone
 func PopulateCompatibilityFields(ctx *ProjectContext) {
-	if ctx.Extensions == nil {
-		return
+	if composeFiles, ok := ctx.StringSlice("docker", "compose_files"); ok {
+		ctx.ComposeFiles = deepCopy(composeFiles).([]string)
 	}
 
-	dockerData, ok := ctx.Extensions["docker"]
-	if !ok {
-		return
+	if composeOverride, ok := ctx.String("docker", "compose_override"); ok {
+		ctx.ComposeOverride = composeOverride
 	}
 
-	// Type assert to expected Docker context structure
-	dockerCtx, ok := dockerData.(map[string]interface{})
-	if !ok {
-		return
+	if dockerRunning, ok := ctx.Bool("docker", "docker_running"); ok {
+		ctx.DockerRunning = dockerRunning
 	}
 
-	// Populate ComposeFiles
-	if composeFiles, ok := dockerCtx["compose_files"].([]string); ok {
-		ctx.ComposeFiles = composeFiles
+	if dockerContext, ok := ctx.String("docker", "docker_context"); ok {
+		ctx.DockerContext = dockerContext
 	}
 
-	// Populate ComposeOverride
-	if composeOverride, ok := dockerCtx["compose_override"].(string); ok {
-		ctx.ComposeOverride = composeOverride
+	if value, ok := ctx.extensionValue("docker", "containers_status"); ok {
+		if containersStatus, ok := decodeContainersStatus(value); ok {
+			ctx.ContainersStatus = deepCopy(containersStatus).(map[string]ContainerStatus)
+		}
 	}
+}
 
-	// Populate DockerRunning
-	if dockerRunning, ok := dockerCtx["docker_running"].(bool); ok {
-		ctx.DockerRunning = dockerRunning
+// decodeContainersStatus accepts container status in its native form or in
+// its JSON form, as returned from the context cache or by external plugins
+func decodeContainersStatus(value interface{}) (map[string]ContainerStatus, bool) {
+	if containersStatus, ok := value.(map[string]ContainerStatus); ok {
+		return containersStatus, true
+	}
+	if _, ok := value.(map[string]interface{}); !ok {
+		return nil, false
 	}
 
-	// Populate ContainersStatus
-	if containersStatus, ok := dockerCtx["containers_status"].(map[string]ContainerStatus); ok {
-		ctx.ContainersStatus = containersStatus
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, false
 	}
+	var containersStatus map[string]ContainerStatus
+	if err := json.Unmarshal(data, &containersStatus); err != nil {
+		return nil, false
+	}
+	return containersStatus, true
 }
 
 // UpdateExtensionsFromCompatibility updates the extensions map from the deprecated Docker fields
 // This allows plugins to access Docker data through the extensions system while maintaining
 // backward compatibility with code that sets the old fields
+// The extension data gets its own copies of the fields' slices and maps
 func UpdateExtensionsFromCompatibility(ctx *ProjectContext) {
 	if ctx.Extensions == nil {
 		ctx.Extensions = make(map[string]interface{})
@@ -51,6 +63,7 @@ func UpdateExtensionsFromCompatibility(ctx *ProjectContext) {
 	hasDockerData := len(ctx.ComposeFiles) > 0 ||
 		ctx.ComposeOverride != "" ||
 		ctx.DockerRunning ||
+		ctx.DockerContext != "" ||
 		len(ctx.ContainersStatus) > 0
 
 	if !hasDockerData {
@@ -60,7 +73,7 @@ func UpdateExtensionsFromCompatibility(ctx *ProjectContext) {
 	dockerCtx := make(map[string]interface{})
 
 	if len(ctx.ComposeFiles) > 0 {
-		dockerCtx["compose_files"] = ctx.ComposeFiles
+		dockerCtx["compose_files"] = deepCopy(ctx.ComposeFiles)
 	}
 
 	if ctx.ComposeOverride != "" {
@@ -69,8 +82,12 @@ func UpdateExtensionsFromCompatibility(ctx *ProjectContext) {
 
 	dockerCtx["docker_running"] = ctx.DockerRunning
 
+	if ctx.DockerContext != "" {
+		dockerCtx["docker_context"] = ctx.DockerContext
+	}
+
 	if len(ctx.ContainersStatus) > 0 {
-		dockerCtx["containers_status"] = ctx.ContainersStatus
+		dockerCtx["containers_status"] = deepCopy(ctx.ContainersStatus)
 	}
 
 	ctx.Extensions["docker"] = dockerCtx
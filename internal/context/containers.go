@@ -0,0 +1,98 @@
+package context
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// composePSEntry is a container as reported by `docker compose ps --format json`
+type composePSEntry struct {
+	Name       string
+	Service    string
+	State      string
+	Health     string
+	Publishers []composePSPublisher
+}
+
+// composePSPublisher is a port published by a compose container
+type composePSPublisher struct {
+	URL           string
+	TargetPort    int
+	PublishedPort int
+	Protocol      string
+}
+
+// parseComposePS parses the output of `docker compose ps --format json` into
+// container status keyed by service name. Compose releases before 2.21 print
+// a single JSON array, later ones print one JSON object per line; both are
+// accepted.
+func parseComposePS(output []byte) (map[string]ContainerStatus, error) {
+	var entries []composePSEntry
+
+	trimmed := bytes.TrimSpace(output)
+	if bytes.HasPrefix(trimmed, []byte("[")) {
+		if err := json.Unmarshal(trimmed, &entries); err != nil {
+			return nil, fmt.Errorf("failed to parse container status: %w", err)
+		}
+	} else {
+		scanner := bufio.NewScanner(bytes.NewReader(trimmed))
+		scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			line := bytes.TrimSpace(scanner.Bytes())
+			if len(line) == 0 {
+				continue
+			}
+			var entry composePSEntry
+			if err := json.Unmarshal(line, &entry); err != nil {
+				return nil, fmt.Errorf("failed to parse container status: %w", err)
+			}
+			entries = append(entries, entry)
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("failed to read container status: %w", err)
+		}
+	}
+
+	containers := make(map[string]ContainerStatus, len(entries))
+	for _, entry := range entries {
+		key := entry.Service
+		if key == "" {
+			key = entry.Name
+		}
+		if key == "" {
+			continue
+		}
+
+		status := ContainerStatus{
+			Name:   entry.Name,
+			Status: entry.State,
+			Health: entry.Health,
+		}
+		if status.Status == "" {
+			status.Status = string(ContainerUnknown)
+		}
+		if status.Health == "" {
+			status.Health = "none"
+		}
+		for _, publisher := range entry.Publishers {
+			status.Ports = append(status.Ports, publisher.String())
+		}
+		containers[key] = status
+	}
+	return containers, nil
+}
+
+// String formats the port the way `docker ps` does, e.g. 0.0.0.0:8080->80/tcp
+func (p composePSPublisher) String() string {
+	port := fmt.Sprintf("%d/%s", p.TargetPort, p.Protocol)
+	if p.PublishedPort == 0 {
+		return port
+	}
+	host := p.URL
+	if host == "" {
+		host = "0.0.0.0"
+	}
+	return fmt.Sprintf("%s:%d->%s", host, p.PublishedPort, port)
+}
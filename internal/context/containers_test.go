@@ -0,0 +1,82 @@
+package context
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseComposePS(t *testing.T) {
+	expected := map[string]ContainerStatus{
+		"php": {
+			Name:   "app-php-1",
+			Status: "running",
+			Health: "healthy",
+			Ports:  []string{"9000/tcp"},
+		},
+		"nginx": {
+			Name:   "app-nginx-1",
+			Status: "exited",
+			Health: "none",
+			Ports:  []string{"0.0.0.0:8080->80/tcp"},
+		},
+	}
+
+	t.Run("json lines", func(t *testing.T) {
+		output := `{"Name":"app-php-1","Service":"php","State":"running","Health":"healthy","Publishers":[{"URL":"","TargetPort":9000,"PublishedPort":0,"Protocol":"tcp"}]}
+{"Name":"app-nginx-1","Service":"nginx","State":"exited","Health":"","Publishers":[{"URL":"0.0.0.0","TargetPort":80,"PublishedPort":8080,"Protocol":"tcp"}]}
+`
+		containers, err := parseComposePS([]byte(output))
+		require.NoError(t, err)
+		assert.Equal(t, expected, containers)
+	})
+
+	t.Run("json array", func(t *testing.T) {
+		output := `[{"Name":"app-php-1","Service":"php","State":"running","Health":"healthy","Publishers":[{"TargetPort":9000,"Protocol":"tcp"}]},
+{"Name":"app-nginx-1","Service":"nginx","State":"exited","Publishers":[{"URL":"0.0.0.0","TargetPort":80,"PublishedPort":8080,"Protocol":"tcp"}]}]`
+		containers, err := parseComposePS([]byte(output))
+		require.NoError(t, err)
+		assert.Equal(t, expected, containers)
+	})
+
+	t.Run("no containers", func(t *testing.T) {
+		containers, err := parseComposePS([]byte("\n"))
+		require.NoError(t, err)
+		assert.Empty(t, containers)
+
+		containers, err = parseComposePS([]byte("[]"))
+		require.NoError(t, err)
+		assert.Empty(t, containers)
+	})
+
+	t.Run("malformed output", func(t *testing.T) {
+		_, err := parseComposePS([]byte("no configuration file provided"))
+		assert.Error(t, err)
+	})
+}
+
+func TestPopulateCompatibilityFields_ContainersStatusJSON(t *testing.T) {
+	native := map[string]ContainerStatus{
+		"php": {Name: "app-php-1", Status: "running", Health: "healthy", Ports: []string{"9000/tcp"}},
+	}
+
+	// Round trip through JSON, as the context cache does
+	data, err := json.Marshal(map[string]interface{}{
+		"docker": map[string]interface{}{"containers_status": native},
+	})
+	require.NoError(t, err)
+	var extensions map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &extensions))
+
+	ctx := &ProjectContext{Extensions: extensions}
+	PopulateCompatibilityFields(ctx)
+	assert.Equal(t, native, ctx.ContainersStatus)
+
+	ctx = &ProjectContext{Extensions: map[string]interface{}{
+		"docker": map[string]interface{}{"containers_status": "not a map"},
+	}}
+	PopulateCompatibilityFields(ctx)
+	assert.Nil(t, ctx.ContainersStatus)
+}
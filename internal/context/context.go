@@ -1,5 +1,11 @@
 package context
 
+import (
+	"time"
+
+	"github.com/glide-cli/glide/v3/pkg/plugin/sdk"
+)
+
 // Detect is a convenience function to detect the current project context
 func Detect() *ProjectContext {
 	detector, err := NewDetector()
@@ -21,8 +27,56 @@ func Detect() *ProjectContext {
 	return ctx
 }
 
-// DetectWithExtensions detects context with plugin-provided extensions
-func DetectWithExtensions(extensionProviders []interface{}) *ProjectContext {
+// DetectOption configures DetectWithExtensions
+type DetectOption func(*detectOptions)
+
+type detectOptions struct {
+	cacheTTL time.Duration
+	gitDirty bool
+	mode     DetectionMode
+}
+
+// WithoutCache runs every extension detector instead of reusing results
+// cached by a recent invocation
+func WithoutCache() DetectOption {
+	return func(o *detectOptions) {
+		o.cacheTTL = 0
+	}
+}
+
+// WithGitDirtyCheck detects uncommitted changes, which runs `git status`
+func WithGitDirtyCheck() DetectOption {
+	return func(o *detectOptions) {
+		o.gitDirty = true
+	}
+}
+
+// WithDetectionMode sets how much detection inspects; the default is
+// DetectionStandard
+func WithDetectionMode(mode DetectionMode) DetectOption {
+	return func(o *detectOptions) {
+		o.mode = mode
+	}
+}
+
+// WithCacheTTL sets how long cached extension results are reused. The
+// default is DefaultExtensionCacheTTL; zero disables the cache.
+func WithCacheTTL(ttl time.Duration) DetectOption {
+	return func(o *detectOptions) {
+		o.cacheTTL = ttl
+	}
+}
+
+// DetectWithExtensions detects context with plugin-provided extensions.
+// Extension results are cached in the project's .glide directory and reused
+// by invocations within the cache TTL, unless the project's files or the set
+// of extensions change.
+func DetectWithExtensions(extensionProviders []interface{}, opts ...DetectOption) *ProjectContext {
+	options := detectOptions{cacheTTL: DefaultExtensionCacheTTL}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
 	detector, err := NewDetector()
 	if err != nil {
 		return &ProjectContext{
@@ -30,10 +84,26 @@ func DetectWithExtensions(extensionProviders []interface{}) *ProjectContext {
 			Error:      err,
 		}
 	}
+	detector.SetGitDirtyCheck(options.gitDirty)
+	detector.SetDetectionMode(options.mode)
+
+	// Let extension-backed completions read what was detected
+	var extensions []sdk.ContextExtension
+	for _, p := range extensionProviders {
+		extensions = append(extensions, sdk.ProvidedExtensions(p)...)
+	}
 
 	// Set up extension registry from provided plugins
 	if len(extensionProviders) > 0 {
-		detector.SetExtensionRegistry(newPluginExtensionRegistry(extensionProviders))
+		registry := newPluginExtensionRegistry(extensionProviders)
+		if options.cacheTTL > 0 {
+			names := make([]string, len(extensions))
+			for i, ext := range extensions {
+				names[i] = ext.Name()
+			}
+			registry = newCachedExtensionRegistry(registry, names, options.cacheTTL)
+		}
+		detector.SetExtensionRegistry(registry)
 	}
 
 	ctx, err := detector.Detect()
@@ -41,6 +111,9 @@ func DetectWithExtensions(extensionProviders []interface{}) *ProjectContext {
 		// Even if detection fails, return the context with basic info
 		ctx.Error = err
 	}
+
+	sdk.SetDetectedContext(extensions, ctx.Extensions)
+
 	return ctx
 }
 
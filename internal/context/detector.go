@@ -1,9 +1,13 @@
 package context
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/glide-cli/glide/v3/pkg/logging"
 )
@@ -16,8 +20,42 @@ type Detector struct {
 	locationIdentifier LocationIdentifier
 	composeResolver    ComposeFileResolver
 	extensionRegistry  ExtensionRegistry
-	skipDockerCheck    bool // Skip expensive Docker daemon check
-	lazyDockerCheck    bool // Check Docker status lazily on first use
+	skipDockerCheck    bool          // Skip expensive Docker daemon check
+	lazyDockerCheck    bool          // Check Docker status lazily on first use
+	skipGitCheck       bool          // Skip git branch and commit detection
+	gitDirtyCheck      bool          // Run `git status` to detect uncommitted changes
+	containerCheck     bool          // Run `docker compose ps` to collect container status
+	dockerTimeout      time.Duration // Limit for each docker command, 0 means DefaultDockerCheckTimeout
+}
+
+// DefaultDockerCheckTimeout bounds the docker commands run during detection,
+// so an unreachable remote daemon does not stall every command
+const DefaultDockerCheckTimeout = 3 * time.Second
+
+// DetectionMode selects how much context detection inspects, trading
+// startup time for detail
+type DetectionMode string
+
+const (
+	// DetectionFast skips the Docker daemon check and git detection
+	DetectionFast DetectionMode = "fast"
+	// DetectionStandard checks the Docker daemon lazily, on first use
+	DetectionStandard DetectionMode = "standard"
+	// DetectionFull checks the Docker daemon during detection and also
+	// collects container status and uncommitted git changes
+	DetectionFull DetectionMode = "full"
+)
+
+// ParseDetectionMode parses a detection mode name; empty means standard
+func ParseDetectionMode(name string) (DetectionMode, error) {
+	switch mode := DetectionMode(strings.ToLower(strings.TrimSpace(name))); mode {
+	case "":
+		return DetectionStandard, nil
+	case DetectionFast, DetectionStandard, DetectionFull:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("unknown detection mode %q (want fast, standard or full)", name)
+	}
 }
 
 // ExtensionRegistry interface for plugin-provided context extensions
@@ -43,7 +81,7 @@ func NewDetector() (*Detector, error) {
 }
 
 // NewDetectorFast creates a detector optimized for fast startup
-// Skips expensive Docker daemon checks - use for startup and non-Docker commands
+// Skips expensive Docker daemon checks and git detection - use for startup and non-Docker commands
 func NewDetectorFast() (*Detector, error) {
 	wd, err := os.Getwd()
 	if err != nil {
@@ -57,6 +95,7 @@ func NewDetectorFast() (*Detector, error) {
 		locationIdentifier: NewStandardLocationIdentifier(),
 		composeResolver:    NewStandardComposeFileResolver(),
 		skipDockerCheck:    true,
+		skipGitCheck:       true,
 	}, nil
 }
 
@@ -106,7 +145,51 @@ func (d *Detector) SetExtensionRegistry(registry ExtensionRegistry) {
 	d.extensionRegistry = registry
 }
 
-// Detect analyzes the current environment and returns project context
+// SetGitDirtyCheck enables or disables detection of uncommitted changes.
+// This is off by default because it shells out to `git status`.
+func (d *Detector) SetGitDirtyCheck(enabled bool) {
+	d.gitDirtyCheck = enabled
+}
+
+// SetContainerStatusCheck enables or disables collecting container status.
+// This is off by default because it shells out to `docker compose ps`.
+func (d *Detector) SetContainerStatusCheck(enabled bool) {
+	d.containerCheck = enabled
+}
+
+// SetDetectionMode configures the detector's checks for mode. Fast mode
+// skips git detection entirely; full mode enables the git dirty and
+// container status checks. Standard mode leaves the git dirty check as it
+// was.
+func (d *Detector) SetDetectionMode(mode DetectionMode) {
+	switch mode {
+	case DetectionFast:
+		d.skipDockerCheck = true
+		d.lazyDockerCheck = false
+		d.containerCheck = false
+		d.skipGitCheck = true
+	case DetectionFull:
+		d.skipGitCheck = false
+		d.skipDockerCheck = false
+		d.lazyDockerCheck = false
+		d.containerCheck = true
+		d.gitDirtyCheck = true
+	default:
+		d.skipGitCheck = false
+		d.skipDockerCheck = false
+		d.lazyDockerCheck = true
+	}
+}
+
+// SetDockerCheckTimeout sets how long each docker command run during
+// detection may take. A timeout of 0 restores DefaultDockerCheckTimeout.
+func (d *Detector) SetDockerCheckTimeout(timeout time.Duration) {
+	d.dockerTimeout = timeout
+}
+
+// Detect analyzes the current environment and returns project context.
+// Each call returns a newly allocated context that shares no slices or maps
+// with other contexts, so callers may mutate it freely.
 func (d *Detector) Detect() (*ProjectContext, error) {
 	logging.Debug("Detecting project context", "workingDir", d.workingDir)
 
@@ -133,6 +216,11 @@ func (d *Detector) Detect() (*ProjectContext, error) {
 	ctx.Location = d.locationIdentifier.IdentifyLocation(ctx, d.workingDir)
 	logging.Debug("Identified location", "location", ctx.Location)
 
+	// Detect git branch and state
+	if !d.skipGitCheck {
+		d.detectGit(ctx)
+	}
+
 	// Detect plugin-provided context extensions
 	if d.extensionRegistry != nil {
 		extensions, err := d.extensionRegistry.DetectAll(ctx.ProjectRoot)
@@ -170,18 +258,93 @@ func (d *Detector) Detect() (*ProjectContext, error) {
 	return ctx, nil
 }
 
-// checkDockerStatus checks if Docker daemon is running
+// checkDockerStatus checks if Docker daemon is running. The docker CLI
+// inherits the environment, so DOCKER_HOST and DOCKER_CONTEXT select the
+// daemon just as they do for the user's own docker commands.
 func (d *Detector) checkDockerStatus(ctx *ProjectContext) {
-	cmd := exec.Command("docker", "info")
-	if err := cmd.Run(); err == nil {
+	ctx.DockerContext = d.dockerContext()
+
+	if _, err := d.runDocker("info"); err == nil {
 		ctx.DockerRunning = true
 
-		// Get container status if compose files are available
-		if len(ctx.ComposeFiles) > 0 {
+		// Get container status if requested and compose files are available
+		if d.containerCheck && len(ctx.ComposeFiles) > 0 {
 			d.getContainerStatus(ctx)
 		}
 	} else {
 		ctx.DockerRunning = false
+		logging.Debug("Docker daemon not reachable", "context", ctx.DockerContext, "error", err)
+	}
+}
+
+// dockerContext returns the name of the active docker context, or "" if it
+// cannot be determined
+func (d *Detector) dockerContext() string {
+	if name := os.Getenv("DOCKER_CONTEXT"); name != "" {
+		return name
+	}
+	output, err := d.runDocker("context", "show")
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// runDocker runs the docker CLI with the detector's timeout and returns its
+// standard output
+func (d *Detector) runDocker(args ...string) ([]byte, error) {
+	timeout := d.dockerTimeout
+	if timeout <= 0 {
+		timeout = DefaultDockerCheckTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "docker", args...)
+	// Don't wait on children that keep the output pipe open after a kill
+	cmd.WaitDelay = 100 * time.Millisecond
+	output, err := cmd.Output()
+	if ctx.Err() != nil {
+		return nil, fmt.Errorf("docker %s timed out after %s", strings.Join(args, " "), timeout)
+	}
+	return output, err
+}
+
+// detectGit populates the git branch and commit and, if enabled, the dirty
+// state. Outside a git repository the fields are left empty.
+func (d *Detector) detectGit(ctx *ProjectContext) {
+	gitDir, workTree, ok := findGitDir(d.workingDir, ctx.ProjectRoot)
+	if !ok && ctx.DevelopmentMode == ModeMultiWorktree {
+		// At the project root of a multi-worktree project, use the main repo
+		vcsDir := filepath.Join(ctx.ProjectRoot, "vcs")
+		gitDir, workTree, ok = findGitDir(vcsDir, vcsDir)
+	}
+	if !ok {
+		return
+	}
+
+	branch, detached, err := readGitHead(gitDir)
+	if err != nil {
+		logging.Debug("Failed to read git HEAD", "gitDir", gitDir, "error", err)
+		return
+	}
+	ctx.GitBranch = branch
+	ctx.GitDetached = detached
+	logging.Debug("Detected git branch", "branch", branch, "detached", detached)
+
+	commit, err := readGitCommit(gitDir)
+	if err != nil {
+		logging.Debug("Failed to resolve git commit", "gitDir", gitDir, "error", err)
+	}
+	ctx.GitCommit = commit
+
+	if d.gitDirtyCheck {
+		dirty, err := isGitDirty(workTree)
+		if err != nil {
+			logging.Debug("Failed to check git dirty state", "workTree", workTree, "error", err)
+			return
+		}
+		ctx.GitDirty = dirty
 	}
 }
 
@@ -212,15 +375,18 @@ func (d *Detector) getContainerStatus(ctx *ProjectContext) {
 	}
 	args = append(args, "ps", "--format", "json", "--all")
 
-	// Execute command
-	cmd := exec.Command("docker", args...)
-	_, err := cmd.Output()
+	output, err := d.runDocker(args...)
 	if err != nil {
+		logging.Debug("Failed to query container status", "error", err)
 		return
 	}
 
-	// Container status parsing is handled by docker.ContainerManager
-	// This basic check just verifies containers exist
+	containers, err := parseComposePS(output)
+	if err != nil {
+		logging.Debug("Failed to parse container status", "error", err)
+		return
+	}
+	ctx.ContainersStatus = containers
 }
 
 // DetectCommandScope determines if a command should run in global or local scope
@@ -0,0 +1,129 @@
+package context
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+)
+
+// DiffKind describes how a value differs between two contexts
+type DiffKind string
+
+const (
+	DiffAdded       DiffKind = "added"        // Key only present in the second context
+	DiffRemoved     DiffKind = "removed"      // Key only present in the first context
+	DiffChanged     DiffKind = "changed"      // Same type, different value
+	DiffTypeChanged DiffKind = "type_changed" // Value type differs (e.g. string vs list)
+)
+
+// DiffEntry is a single difference between two contexts
+type DiffEntry struct {
+	Path    string      `json:"path"`
+	Kind    DiffKind    `json:"kind"`
+	Old     interface{} `json:"old,omitempty"`
+	New     interface{} `json:"new,omitempty"`
+	OldType string      `json:"old_type,omitempty"`
+	NewType string      `json:"new_type,omitempty"`
+}
+
+// LoadContextFile reads a serialized context from a JSON file as a generic map
+func LoadContextFile(path string) (map[string]interface{}, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read context file: %w", err)
+	}
+
+	var result map[string]interface{}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse context file %s: %w", path, err)
+	}
+	return result, nil
+}
+
+// DiffMaps compares two decoded JSON objects and returns their differences,
+// sorted by path. Nested objects are compared key by key with dot-separated
+// paths (e.g. "extensions.docker.compose_files"); lists and scalars are
+// compared as whole values.
+func DiffMaps(a, b map[string]interface{}) []DiffEntry {
+	var entries []DiffEntry
+	diffObjects("", a, b, &entries)
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Path < entries[j].Path
+	})
+	return entries
+}
+
+// diffObjects appends the differences between two objects at prefix
+func diffObjects(prefix string, a, b map[string]interface{}, entries *[]DiffEntry) {
+	for key, oldValue := range a {
+		path := joinDiffPath(prefix, key)
+		newValue, ok := b[key]
+		if !ok {
+			*entries = append(*entries, DiffEntry{Path: path, Kind: DiffRemoved, Old: oldValue})
+			continue
+		}
+		diffValues(path, oldValue, newValue, entries)
+	}
+
+	for key, newValue := range b {
+		if _, ok := a[key]; !ok {
+			*entries = append(*entries, DiffEntry{Path: joinDiffPath(prefix, key), Kind: DiffAdded, New: newValue})
+		}
+	}
+}
+
+// diffValues appends the difference between two values at path, if any
+func diffValues(path string, oldValue, newValue interface{}, entries *[]DiffEntry) {
+	oldType, newType := jsonTypeName(oldValue), jsonTypeName(newValue)
+	if oldType != newType {
+		*entries = append(*entries, DiffEntry{
+			Path:    path,
+			Kind:    DiffTypeChanged,
+			Old:     oldValue,
+			New:     newValue,
+			OldType: oldType,
+			NewType: newType,
+		})
+		return
+	}
+
+	if oldMap, ok := oldValue.(map[string]interface{}); ok {
+		diffObjects(path, oldMap, newValue.(map[string]interface{}), entries)
+		return
+	}
+
+	if !reflect.DeepEqual(oldValue, newValue) {
+		*entries = append(*entries, DiffEntry{Path: path, Kind: DiffChanged, Old: oldValue, New: newValue})
+	}
+}
+
+// jsonTypeName returns the JSON type of a decoded value
+func jsonTypeName(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "bool"
+	case float64, json.Number:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "list"
+	case map[string]interface{}:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
+
+// joinDiffPath appends key to a dot-separated path
+func joinDiffPath(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
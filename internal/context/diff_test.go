@@ -0,0 +1,81 @@
+package context
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffMaps(t *testing.T) {
+	a := map[string]interface{}{
+		"ProjectRoot":     "/ci/project",
+		"DevelopmentMode": "single-repo",
+		"DockerRunning":   false,
+		"WorktreeName":    "",
+		"Extensions": map[string]interface{}{
+			"docker": map[string]interface{}{
+				"compose_files": "docker-compose.yml",
+				"running":       false,
+			},
+			"node": map[string]interface{}{"version": "20"},
+		},
+	}
+	b := map[string]interface{}{
+		"ProjectRoot":     "/home/dev/project",
+		"DevelopmentMode": "single-repo",
+		"DockerRunning":   true,
+		"Extensions": map[string]interface{}{
+			"docker": map[string]interface{}{
+				"compose_files": []interface{}{"docker-compose.yml", "docker-compose.override.yml"},
+				"running":       false,
+			},
+			"go": map[string]interface{}{"version": "1.24"},
+		},
+	}
+
+	entries := DiffMaps(a, b)
+
+	assert.Equal(t, []DiffEntry{
+		{Path: "DockerRunning", Kind: DiffChanged, Old: false, New: true},
+		{
+			Path:    "Extensions.docker.compose_files",
+			Kind:    DiffTypeChanged,
+			Old:     "docker-compose.yml",
+			New:     []interface{}{"docker-compose.yml", "docker-compose.override.yml"},
+			OldType: "string",
+			NewType: "list",
+		},
+		{Path: "Extensions.go", Kind: DiffAdded, New: map[string]interface{}{"version": "1.24"}},
+		{Path: "Extensions.node", Kind: DiffRemoved, Old: map[string]interface{}{"version": "20"}},
+		{Path: "ProjectRoot", Kind: DiffChanged, Old: "/ci/project", New: "/home/dev/project"},
+		{Path: "WorktreeName", Kind: DiffRemoved, Old: ""},
+	}, entries)
+}
+
+func TestDiffMaps_Identical(t *testing.T) {
+	m := map[string]interface{}{
+		"Extensions": map[string]interface{}{"docker": map[string]interface{}{"files": []interface{}{"a.yml"}}},
+	}
+	assert.Empty(t, DiffMaps(m, m))
+}
+
+func TestLoadContextFile(t *testing.T) {
+	dir := t.TempDir()
+
+	valid := filepath.Join(dir, "ctx.json")
+	require.NoError(t, os.WriteFile(valid, []byte(`{"ProjectRoot": "/project"}`), 0644))
+	data, err := LoadContextFile(valid)
+	require.NoError(t, err)
+	assert.Equal(t, "/project", data["ProjectRoot"])
+
+	invalid := filepath.Join(dir, "bad.json")
+	require.NoError(t, os.WriteFile(invalid, []byte(`[1, 2]`), 0644))
+	_, err = LoadContextFile(invalid)
+	assert.Error(t, err)
+
+	_, err = LoadContextFile(filepath.Join(dir, "missing.json"))
+	assert.Error(t, err)
+}
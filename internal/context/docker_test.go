@@ -0,0 +1,120 @@
+package context
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeDocker puts a docker executable running script first on PATH
+func fakeDocker(t *testing.T, script string) {
+	t.Helper()
+	dir := t.TempDir()
+	path := filepath.Join(dir, "docker")
+	require.NoError(t, os.WriteFile(path, []byte("#!/bin/sh\n"+script+"\n"), 0755))
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+func TestCheckDockerStatus(t *testing.T) {
+	t.Run("reports the active context", func(t *testing.T) {
+		fakeDocker(t, `case "$1" in
+context) echo remote-builder ;;
+info) exit 0 ;;
+esac`)
+		t.Setenv("DOCKER_CONTEXT", "")
+
+		ctx := &ProjectContext{}
+		(&Detector{}).checkDockerStatus(ctx)
+		assert.True(t, ctx.DockerRunning)
+		assert.Equal(t, "remote-builder", ctx.DockerContext)
+
+		UpdateExtensionsFromCompatibility(ctx)
+		value, ok := ctx.String("docker", "docker_context")
+		assert.True(t, ok)
+		assert.Equal(t, "remote-builder", value)
+	})
+
+	t.Run("DOCKER_CONTEXT wins", func(t *testing.T) {
+		fakeDocker(t, `case "$1" in
+context) echo default ;;
+info) exit 0 ;;
+esac`)
+		t.Setenv("DOCKER_CONTEXT", "staging")
+
+		ctx := &ProjectContext{}
+		(&Detector{}).checkDockerStatus(ctx)
+		assert.Equal(t, "staging", ctx.DockerContext)
+	})
+
+	t.Run("unreachable daemon times out", func(t *testing.T) {
+		fakeDocker(t, `case "$1" in
+context) echo remote ;;
+info) sleep 10 ;;
+esac`)
+		t.Setenv("DOCKER_CONTEXT", "")
+
+		d := &Detector{}
+		d.SetDockerCheckTimeout(200 * time.Millisecond)
+
+		ctx := &ProjectContext{DockerRunning: true}
+		start := time.Now()
+		d.checkDockerStatus(ctx)
+		assert.Less(t, time.Since(start), 2*time.Second)
+		assert.False(t, ctx.DockerRunning)
+		assert.Equal(t, "remote", ctx.DockerContext)
+	})
+}
+
+func TestDetectionMode(t *testing.T) {
+	fakeDocker(t, `case "$1" in
+context) echo default ;;
+info) exit 0 ;;
+compose) echo '{"Name":"app-php-1","Service":"php","State":"running","Health":"healthy"}' ;;
+esac`)
+	t.Setenv("DOCKER_CONTEXT", "")
+
+	root := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(root, ".git"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "docker-compose.yml"), []byte("services: {}\n"), 0644))
+
+	detect := func(t *testing.T, mode DetectionMode) *ProjectContext {
+		t.Helper()
+		d, err := NewDetector()
+		require.NoError(t, err)
+		d.workingDir = root
+		d.SetDetectionMode(mode)
+		ctx, err := d.Detect()
+		require.NoError(t, err)
+		return ctx
+	}
+
+	t.Run("full collects container status", func(t *testing.T) {
+		ctx := detect(t, DetectionFull)
+		assert.True(t, ctx.DockerRunning)
+		require.Contains(t, ctx.ContainersStatus, "php")
+		assert.Equal(t, "running", ctx.ContainersStatus["php"].Status)
+	})
+
+	t.Run("fast skips docker", func(t *testing.T) {
+		ctx := detect(t, DetectionFast)
+		assert.False(t, ctx.DockerRunning)
+		assert.Empty(t, ctx.ContainersStatus)
+	})
+
+	t.Run("parse", func(t *testing.T) {
+		mode, err := ParseDetectionMode("")
+		require.NoError(t, err)
+		assert.Equal(t, DetectionStandard, mode)
+
+		mode, err = ParseDetectionMode("Full")
+		require.NoError(t, err)
+		assert.Equal(t, DetectionFull, mode)
+
+		_, err = ParseDetectionMode("thorough")
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,182 @@
+package context
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/glide-cli/glide/v3/pkg/plugin/sdk"
+)
+
+// ExportPrefix is prepended to every variable produced by ExportVars
+const ExportPrefix = "GLIDE_"
+
+// ExtensionExportPrefix is prepended to variables flattened from extension
+// data, keeping them clear of core fields and of glide's own configuration
+// variables such as GLIDE_DOCKER_TIMEOUT
+const ExtensionExportPrefix = ExportPrefix + "EXT_"
+
+// ExportVars flattens the context into environment variables.
+//
+// Naming convention:
+//   - Core fields use GLIDE_<FIELD>, e.g. GLIDE_PROJECT_ROOT, GLIDE_GIT_BRANCH
+//   - Extension data uses GLIDE_EXT_<EXTENSION>_<KEY>, e.g. GLIDE_EXT_DOCKER_DOCKER_RUNNING
+//   - Nested maps append each key: GLIDE_EXT_<EXTENSION>_<KEY>_<SUBKEY>
+//   - Names are upper-cased and any character outside [A-Z0-9_] becomes "_"
+//
+// When sanitizing maps two keys to the same name (e.g. "max-nodes" and
+// "max_nodes"), the key that sorts first wins, so the output is stable.
+//
+// Slices are joined with spaces, booleans render as "true"/"false", and
+// extension keys starting with "_" (internal markers) are skipped. Empty
+// values are omitted; GLIDE_GIT_DIRTY is only set, to "true", when the opt-in
+// dirty check found uncommitted changes.
+func (c *ProjectContext) ExportVars() map[string]string {
+	vars := make(map[string]string)
+
+	set := func(name, value string) {
+		if value != "" {
+			vars[ExportPrefix+name] = value
+		}
+	}
+
+	set("WORKING_DIR", c.WorkingDir)
+	set("PROJECT_ROOT", c.ProjectRoot)
+	set("PROJECT_NAME", c.ProjectName)
+	set("DEVELOPMENT_MODE", string(c.DevelopmentMode))
+	set("LOCATION", string(c.Location))
+	set("WORKTREE_NAME", c.WorktreeName)
+	set("GIT_BRANCH", c.GitBranch)
+	set("GIT_COMMIT", c.GitCommit)
+	if c.GitDirty {
+		set("GIT_DIRTY", "true")
+	}
+	set("COMPOSE_FILES", strings.Join(c.ComposeFiles, " "))
+	set("COMPOSE_OVERRIDE", c.ComposeOverride)
+	set("DOCKER_RUNNING", fmt.Sprintf("%t", c.DockerRunning))
+	set("DETECTED_FRAMEWORKS", strings.Join(c.DetectedFrameworks, " "))
+
+	names, data := mapEntries(reflect.ValueOf(c.Extensions))
+	for _, name := range names {
+		flattenExportValue(vars, ExtensionExportPrefix+exportName(name), data[name])
+	}
+
+	return vars
+}
+
+// ShellExports returns the context as sorted POSIX shell export statements,
+// suitable for `eval "$(glide context --export)"`.
+func (c *ProjectContext) ShellExports() []string {
+	vars := c.ExportVars()
+
+	names := make([]string, 0, len(vars))
+	for name := range vars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	lines := make([]string, 0, len(names))
+	for _, name := range names {
+		lines = append(lines, fmt.Sprintf("export %s=%s", name, sdk.ShellQuote(vars[name])))
+	}
+	return lines
+}
+
+// flattenExportValue writes a value (recursing into maps) into vars under
+// name, leaving names that are already set untouched
+func flattenExportValue(vars map[string]string, name string, value reflect.Value) {
+	for value.IsValid() && (value.Kind() == reflect.Interface || value.Kind() == reflect.Ptr) {
+		if value.IsNil() {
+			return
+		}
+		value = value.Elem()
+	}
+	if !value.IsValid() {
+		return
+	}
+
+	switch value.Kind() {
+	case reflect.Map:
+		if value.Type().Key().Kind() != reflect.String {
+			return
+		}
+		keys, entries := mapEntries(value)
+		for _, key := range keys {
+			flattenExportValue(vars, name+"_"+exportName(key), entries[key])
+		}
+
+	case reflect.Slice, reflect.Array:
+		parts := make([]string, 0, value.Len())
+		for i := 0; i < value.Len(); i++ {
+			if s, ok := exportScalar(value.Index(i)); ok {
+				parts = append(parts, s)
+			}
+		}
+		if _, taken := vars[name]; !taken && len(parts) > 0 {
+			vars[name] = strings.Join(parts, " ")
+		}
+
+	default:
+		if _, taken := vars[name]; taken {
+			return
+		}
+		if s, ok := exportScalar(value); ok && s != "" {
+			vars[name] = s
+		}
+	}
+}
+
+// mapEntries returns the sorted keys and the values of a string-keyed map,
+// skipping internal keys that start with "_"
+func mapEntries(m reflect.Value) ([]string, map[string]reflect.Value) {
+	keys := make([]string, 0, m.Len())
+	entries := make(map[string]reflect.Value, m.Len())
+	iter := m.MapRange()
+	for iter.Next() {
+		key := iter.Key().String()
+		if strings.HasPrefix(key, "_") {
+			continue
+		}
+		keys = append(keys, key)
+		entries[key] = iter.Value()
+	}
+	sort.Strings(keys)
+	return keys, entries
+}
+
+// exportScalar renders a scalar value as a string; ok is false for composite values
+func exportScalar(value reflect.Value) (string, bool) {
+	for value.IsValid() && (value.Kind() == reflect.Interface || value.Kind() == reflect.Ptr) {
+		if value.IsNil() {
+			return "", false
+		}
+		value = value.Elem()
+	}
+	if !value.IsValid() {
+		return "", false
+	}
+
+	switch value.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return fmt.Sprintf("%v", value.Interface()), true
+	default:
+		return "", false
+	}
+}
+
+// exportName converts a key into an environment variable name segment
+func exportName(key string) string {
+	var sb strings.Builder
+	for _, r := range strings.ToUpper(key) {
+		if (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '_' {
+			sb.WriteRune(r)
+		} else {
+			sb.WriteRune('_')
+		}
+	}
+	return sb.String()
+}
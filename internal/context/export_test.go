@@ -0,0 +1,123 @@
+package context
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProjectContext_ExportVars(t *testing.T) {
+	ctx := &ProjectContext{
+		WorkingDir:      "/home/user/project",
+		ProjectRoot:     "/home/user/project",
+		DevelopmentMode: ModeSingleRepo,
+		Location:        LocationProject,
+		ComposeFiles:    []string{"docker-compose.yml", "docker-compose.override.yml"},
+		DockerRunning:   true,
+		Extensions: map[string]interface{}{
+			"docker": map[string]interface{}{
+				"compose_files":  []string{"a.yml", "b.yml"},
+				"docker_running": true,
+			},
+			"cloud-provider": map[string]interface{}{
+				"region": "us-east-1",
+				"limits": map[string]interface{}{"max-nodes": 3},
+			},
+			"_dockerCheckDeferred": true,
+		},
+	}
+
+	vars := ctx.ExportVars()
+
+	assert.Equal(t, "/home/user/project", vars["GLIDE_PROJECT_ROOT"])
+	assert.Equal(t, "single-repo", vars["GLIDE_DEVELOPMENT_MODE"])
+	assert.Equal(t, "project", vars["GLIDE_LOCATION"])
+	assert.Equal(t, "docker-compose.yml docker-compose.override.yml", vars["GLIDE_COMPOSE_FILES"])
+	assert.Equal(t, "true", vars["GLIDE_DOCKER_RUNNING"])
+
+	// Extensions are flattened with prefixed, sanitized names
+	assert.Equal(t, "a.yml b.yml", vars["GLIDE_EXT_DOCKER_COMPOSE_FILES"])
+	assert.Equal(t, "true", vars["GLIDE_EXT_DOCKER_DOCKER_RUNNING"])
+	assert.Equal(t, "us-east-1", vars["GLIDE_EXT_CLOUD_PROVIDER_REGION"])
+	assert.Equal(t, "3", vars["GLIDE_EXT_CLOUD_PROVIDER_LIMITS_MAX_NODES"])
+
+	// Empty values and internal markers are omitted
+	assert.NotContains(t, vars, "GLIDE_WORKTREE_NAME")
+	assert.NotContains(t, vars, "GLIDE_GIT_DIRTY")
+	for name := range vars {
+		assert.NotContains(t, name, "DEFERRED")
+	}
+}
+
+func TestProjectContext_ExportVarsCollisions(t *testing.T) {
+	ctx := &ProjectContext{
+		GitBranch: "main",
+		Extensions: map[string]interface{}{
+			"git":    map[string]interface{}{"branch": "other"},
+			"docker": map[string]interface{}{"timeout": 5},
+			"app": map[string]interface{}{
+				"max-nodes": 1,
+				"max_nodes": 2,
+				"max.nodes": 3,
+			},
+		},
+	}
+
+	for i := 0; i < 20; i++ {
+		vars := ctx.ExportVars()
+
+		assert.Equal(t, "main", vars["GLIDE_GIT_BRANCH"], "extensions must not override core fields")
+		assert.Equal(t, "other", vars["GLIDE_EXT_GIT_BRANCH"])
+		assert.NotContains(t, vars, "GLIDE_DOCKER_TIMEOUT", "extensions must not set glide configuration")
+		assert.Equal(t, "1", vars["GLIDE_EXT_APP_MAX_NODES"], "the first key in sorted order wins")
+	}
+}
+
+func TestProjectContext_ExportVarsGit(t *testing.T) {
+	ctx := &ProjectContext{GitBranch: "main", GitCommit: "abc1234", GitDirty: true}
+
+	vars := ctx.ExportVars()
+
+	assert.Equal(t, "main", vars["GLIDE_GIT_BRANCH"])
+	assert.Equal(t, "abc1234", vars["GLIDE_GIT_COMMIT"])
+	assert.Equal(t, "true", vars["GLIDE_GIT_DIRTY"])
+}
+
+func TestProjectContext_ShellExports(t *testing.T) {
+	ctx := &ProjectContext{
+		ProjectRoot: "/tmp/it's a project",
+		ProjectName: "demo",
+	}
+
+	lines := ctx.ShellExports()
+
+	assert.Equal(t, []string{
+		"export GLIDE_DOCKER_RUNNING='false'",
+		"export GLIDE_PROJECT_NAME='demo'",
+		`export GLIDE_PROJECT_ROOT='/tmp/it'\''s a project'`,
+	}, lines)
+}
+
+func TestProjectContext_ShellExports_EvalRoundTrip(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+
+	value := `spaces "quotes" 'single' $HOME ` + "`cmd`" + ` ; rm -rf /`
+	ctx := &ProjectContext{
+		ProjectRoot: value,
+		Extensions: map[string]interface{}{
+			"docker": map[string]interface{}{"compose_files": []string{"a b.yml", "c.yml"}},
+		},
+	}
+
+	script := strings.Join(ctx.ShellExports(), "\n") +
+		"\nprintf '%s\\n' \"$GLIDE_PROJECT_ROOT\" \"$GLIDE_EXT_DOCKER_COMPOSE_FILES\""
+	out, err := exec.Command("sh", "-c", script).Output()
+	require.NoError(t, err)
+
+	assert.Equal(t, value+"\na b.yml c.yml\n", string(out))
+}
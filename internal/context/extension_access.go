@@ -0,0 +1,93 @@
+package context
+
+import "encoding/json"
+
+// extensionFields returns the top-level fields of an extension's data.
+// Extensions usually provide a map[string]interface{}; any other value, such
+// as a struct, is viewed through its JSON encoding.
+func (c *ProjectContext) extensionFields(ext string) (map[string]interface{}, bool) {
+	if c == nil || c.Extensions == nil {
+		return nil, false
+	}
+
+	data, ok := c.Extensions[ext]
+	if !ok || data == nil {
+		return nil, false
+	}
+
+	if fields, ok := data.(map[string]interface{}); ok {
+		return fields, true
+	}
+
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return nil, false
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(encoded, &fields); err != nil {
+		return nil, false
+	}
+	return fields, true
+}
+
+// extensionValue returns the raw value of key in an extension's data
+func (c *ProjectContext) extensionValue(ext, key string) (interface{}, bool) {
+	fields, ok := c.extensionFields(ext)
+	if !ok {
+		return nil, false
+	}
+	value, ok := fields[key]
+	return value, ok
+}
+
+// String returns the string stored under key in the named extension's data.
+// It returns "" and false if the extension or key is missing or the value is
+// not a string.
+func (c *ProjectContext) String(ext, key string) (string, bool) {
+	value, ok := c.extensionValue(ext, key)
+	if !ok {
+		return "", false
+	}
+	s, ok := value.(string)
+	return s, ok
+}
+
+// Bool returns the bool stored under key in the named extension's data.
+// It returns false and false if the extension or key is missing or the value
+// is not a bool.
+func (c *ProjectContext) Bool(ext, key string) (bool, bool) {
+	value, ok := c.extensionValue(ext, key)
+	if !ok {
+		return false, false
+	}
+	b, ok := value.(bool)
+	return b, ok
+}
+
+// StringSlice returns the strings stored under key in the named extension's
+// data. Both []string and the []interface{} produced by JSON decoding are
+// accepted. It returns nil and false if the extension or key is missing or
+// the value is not a list of strings.
+func (c *ProjectContext) StringSlice(ext, key string) ([]string, bool) {
+	value, ok := c.extensionValue(ext, key)
+	if !ok {
+		return nil, false
+	}
+
+	switch v := value.(type) {
+	case []string:
+		return v, true
+	case []interface{}:
+		result := make([]string, 0, len(v))
+		for _, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return nil, false
+			}
+			result = append(result, s)
+		}
+		return result, true
+	default:
+		return nil, false
+	}
+}
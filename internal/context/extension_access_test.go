@@ -0,0 +1,144 @@
+package context
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type accessTestData struct {
+	Services []string `json:"services"`
+	Running  bool     `json:"running"`
+}
+
+func newAccessTestContext() *ProjectContext {
+	return &ProjectContext{
+		Extensions: map[string]interface{}{
+			"docker": map[string]interface{}{
+				"compose_files":    []string{"docker-compose.yml", "docker-compose.override.yml"},
+				"compose_override": "docker-compose.override.yml",
+				"docker_running":   true,
+				"mixed":            []interface{}{"a", 1},
+				"count":            3,
+			},
+			"decoded": map[string]interface{}{
+				"compose_files": []interface{}{"compose.yaml"},
+			},
+			"typed":  accessTestData{Services: []string{"web"}, Running: true},
+			"scalar": "not a map",
+			"empty":  nil,
+		},
+	}
+}
+
+func TestProjectContextString(t *testing.T) {
+	ctx := newAccessTestContext()
+
+	tests := []struct {
+		name   string
+		ext    string
+		key    string
+		want   string
+		wantOK bool
+	}{
+		{"present", "docker", "compose_override", "docker-compose.override.yml", true},
+		{"missing key", "docker", "project_name", "", false},
+		{"missing extension", "kubernetes", "namespace", "", false},
+		{"wrong type", "docker", "docker_running", "", false},
+		{"extension is not a map", "scalar", "value", "", false},
+		{"nil extension data", "empty", "value", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ctx.String(tt.ext, tt.key)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestProjectContextBool(t *testing.T) {
+	ctx := newAccessTestContext()
+
+	tests := []struct {
+		name   string
+		ext    string
+		key    string
+		want   bool
+		wantOK bool
+	}{
+		{"present", "docker", "docker_running", true, true},
+		{"struct data", "typed", "running", true, true},
+		{"missing key", "docker", "healthy", false, false},
+		{"missing extension", "kubernetes", "ready", false, false},
+		{"wrong type", "docker", "compose_override", false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ctx.Bool(tt.ext, tt.key)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestProjectContextStringSlice(t *testing.T) {
+	ctx := newAccessTestContext()
+
+	tests := []struct {
+		name   string
+		ext    string
+		key    string
+		want   []string
+		wantOK bool
+	}{
+		{"native slice", "docker", "compose_files", []string{"docker-compose.yml", "docker-compose.override.yml"}, true},
+		{"JSON-decoded slice", "decoded", "compose_files", []string{"compose.yaml"}, true},
+		{"struct data", "typed", "services", []string{"web"}, true},
+		{"missing key", "docker", "services", nil, false},
+		{"missing extension", "kubernetes", "namespaces", nil, false},
+		{"wrong type", "docker", "count", nil, false},
+		{"mixed element types", "docker", "mixed", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ctx.StringSlice(tt.ext, tt.key)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestProjectContextAccessorsWithoutExtensions(t *testing.T) {
+	var nilCtx *ProjectContext
+	_, ok := nilCtx.String("docker", "compose_override")
+	assert.False(t, ok)
+
+	_, ok = (&ProjectContext{}).StringSlice("docker", "compose_files")
+	assert.False(t, ok)
+}
+
+func TestPopulateCompatibilityFields_JSONDecoded(t *testing.T) {
+	// Extension data read back from JSON has []interface{} lists
+	encoded, err := json.Marshal(map[string]interface{}{
+		"compose_files":    []string{"docker-compose.yml"},
+		"compose_override": "override.yml",
+		"docker_running":   true,
+	})
+	require.NoError(t, err)
+
+	var decoded map[string]interface{}
+	require.NoError(t, json.Unmarshal(encoded, &decoded))
+
+	ctx := &ProjectContext{Extensions: map[string]interface{}{"docker": decoded}}
+	PopulateCompatibilityFields(ctx)
+
+	assert.Equal(t, []string{"docker-compose.yml"}, ctx.ComposeFiles)
+	assert.Equal(t, "override.yml", ctx.ComposeOverride)
+	assert.True(t, ctx.DockerRunning)
+}
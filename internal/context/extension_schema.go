@@ -0,0 +1,60 @@
+package context
+
+import (
+	"context"
+	"sort"
+
+	"github.com/glide-cli/glide/v3/pkg/plugin/sdk"
+)
+
+// ExtensionValidation is the result of checking one context extension's
+// detection output against the schema it declares
+type ExtensionValidation struct {
+	Extension string
+	Plugin    string
+	Detected  bool                  // Detect returned data (false if not applicable)
+	DetectErr error                 // Detect failed; nothing was validated
+	Errors    []sdk.ValidationError // Differences from the declared schema
+}
+
+// OK reports whether detection succeeded and matched the schema
+func (v ExtensionValidation) OK() bool {
+	return v.DetectErr == nil && len(v.Errors) == 0
+}
+
+// ValidateExtensions runs Detect for every extension provided by providers
+// that implements sdk.DataSchemaProvider and checks the result against the
+// declared schema. Extensions without a schema are skipped. Results are
+// sorted by extension name.
+func ValidateExtensions(ctx context.Context, providers []interface{}, projectRoot string) []ExtensionValidation {
+	var results []ExtensionValidation
+
+	for _, p := range providers {
+		for _, ext := range sdk.ProvidedExtensions(p) {
+			schemaProvider, ok := ext.(sdk.DataSchemaProvider)
+			if !ok {
+				continue
+			}
+
+			result := ExtensionValidation{
+				Extension: ext.Name(),
+				Plugin:    providerName(p),
+			}
+
+			data, err := ext.Detect(ctx, projectRoot)
+			if err != nil {
+				result.DetectErr = err
+			} else if data != nil {
+				result.Detected = true
+				result.Errors = sdk.ValidateExtensionData(schemaProvider.DataSchema(), data)
+			}
+
+			results = append(results, result)
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Extension < results[j].Extension
+	})
+	return results
+}
@@ -0,0 +1,143 @@
+package context
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// shortSHALength is the number of characters recorded for a detached HEAD
+const shortSHALength = 7
+
+// findGitDir locates the git directory for dir by walking up to the nearest
+// .git entry, stopping after stopDir. A .git file (used by linked worktrees)
+// is followed to the gitdir it points at. It returns the git directory and
+// the work tree root.
+func findGitDir(dir, stopDir string) (gitDir string, workTree string, ok bool) {
+	current := dir
+	for {
+		gitPath := filepath.Join(current, ".git")
+		if info, err := os.Stat(gitPath); err == nil {
+			if info.IsDir() {
+				return gitPath, current, true
+			}
+			if target, err := readGitDirFile(gitPath); err == nil {
+				return target, current, true
+			}
+		}
+
+		parent := filepath.Dir(current)
+		if current == stopDir || parent == current {
+			return "", "", false
+		}
+		current = parent
+	}
+}
+
+// readGitDirFile resolves a ".git" file of the form "gitdir: <path>"
+func readGitDirFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	line := strings.TrimSpace(string(data))
+	if !strings.HasPrefix(line, "gitdir:") {
+		return "", os.ErrInvalid
+	}
+
+	target := strings.TrimSpace(strings.TrimPrefix(line, "gitdir:"))
+	if !filepath.IsAbs(target) {
+		target = filepath.Join(filepath.Dir(path), target)
+	}
+	return filepath.Clean(target), nil
+}
+
+// readGitHead reads HEAD from gitDir without invoking git.
+// For a detached HEAD the short commit SHA is returned with detached set.
+func readGitHead(gitDir string) (branch string, detached bool, err error) {
+	data, err := os.ReadFile(filepath.Join(gitDir, "HEAD"))
+	if err != nil {
+		return "", false, err
+	}
+
+	head := strings.TrimSpace(string(data))
+	if ref, ok := strings.CutPrefix(head, "ref:"); ok {
+		ref = strings.TrimSpace(ref)
+		return strings.TrimPrefix(ref, "refs/heads/"), false, nil
+	}
+
+	if len(head) > shortSHALength {
+		head = head[:shortSHALength]
+	}
+	return head, true, nil
+}
+
+// readGitCommit returns the short SHA of the commit HEAD points at, reading
+// loose and packed refs without invoking git. It returns "" for a branch that
+// has no commits yet.
+func readGitCommit(gitDir string) (string, error) {
+	data, err := os.ReadFile(filepath.Join(gitDir, "HEAD"))
+	if err != nil {
+		return "", err
+	}
+
+	sha := strings.TrimSpace(string(data))
+	if ref, ok := strings.CutPrefix(sha, "ref:"); ok {
+		if sha, err = resolveGitRef(gitDir, strings.TrimSpace(ref)); err != nil {
+			return "", err
+		}
+	}
+
+	if len(sha) > shortSHALength {
+		sha = sha[:shortSHALength]
+	}
+	return sha, nil
+}
+
+// resolveGitRef returns the commit SHA ref points at. Linked worktrees keep
+// their branches in the main repository, named by the gitdir's commondir file.
+func resolveGitRef(gitDir, ref string) (string, error) {
+	dirs := []string{gitDir}
+	if common, err := os.ReadFile(filepath.Join(gitDir, "commondir")); err == nil {
+		commonDir := strings.TrimSpace(string(common))
+		if !filepath.IsAbs(commonDir) {
+			commonDir = filepath.Join(gitDir, commonDir)
+		}
+		dirs = append(dirs, filepath.Clean(commonDir))
+	}
+
+	for _, dir := range dirs {
+		if data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(ref))); err == nil {
+			return strings.TrimSpace(string(data)), nil
+		}
+	}
+
+	for _, dir := range dirs {
+		data, err := os.ReadFile(filepath.Join(dir, "packed-refs"))
+		if err != nil {
+			continue
+		}
+		for _, line := range strings.Split(string(data), "\n") {
+			if sha, name, ok := strings.Cut(strings.TrimSpace(line), " "); ok && name == ref {
+				return sha, nil
+			}
+		}
+	}
+
+	// A branch without commits has no ref yet
+	return "", nil
+}
+
+// isGitDirty reports whether the work tree has uncommitted changes.
+// This shells out to `git status --porcelain` and is comparatively slow.
+func isGitDirty(workTree string) (bool, error) {
+	cmd := exec.Command("git", "-C", workTree, "status", "--porcelain")
+	out, err := cmd.Output()
+	if err != nil {
+		return false, err
+	}
+	return len(bytes.TrimSpace(out)) > 0, nil
+}
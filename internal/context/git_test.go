@@ -0,0 +1,196 @@
+package context
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeGitHead(t *testing.T, gitDir, head string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(gitDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(gitDir, "HEAD"), []byte(head+"\n"), 0644))
+}
+
+func TestReadGitHead(t *testing.T) {
+	t.Run("branch", func(t *testing.T) {
+		gitDir := filepath.Join(t.TempDir(), ".git")
+		writeGitHead(t, gitDir, "ref: refs/heads/feature/login")
+
+		branch, detached, err := readGitHead(gitDir)
+		require.NoError(t, err)
+		assert.Equal(t, "feature/login", branch)
+		assert.False(t, detached)
+	})
+
+	t.Run("detached HEAD records short SHA", func(t *testing.T) {
+		gitDir := filepath.Join(t.TempDir(), ".git")
+		writeGitHead(t, gitDir, "3f2a9c1d8e7b6a5f4e3d2c1b0a9f8e7d6c5b4a39")
+
+		branch, detached, err := readGitHead(gitDir)
+		require.NoError(t, err)
+		assert.Equal(t, "3f2a9c1", branch)
+		assert.True(t, detached)
+	})
+
+	t.Run("missing HEAD", func(t *testing.T) {
+		_, _, err := readGitHead(t.TempDir())
+		assert.Error(t, err)
+	})
+}
+
+func TestReadGitCommit(t *testing.T) {
+	const sha = "3f2a9c1d8e7b6a5f4e3d2c1b0a9f8e7d6c5b4a39"
+
+	t.Run("loose ref", func(t *testing.T) {
+		gitDir := filepath.Join(t.TempDir(), ".git")
+		writeGitHead(t, gitDir, "ref: refs/heads/main")
+		require.NoError(t, os.MkdirAll(filepath.Join(gitDir, "refs", "heads"), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(gitDir, "refs", "heads", "main"), []byte(sha+"\n"), 0644))
+
+		commit, err := readGitCommit(gitDir)
+		require.NoError(t, err)
+		assert.Equal(t, "3f2a9c1", commit)
+	})
+
+	t.Run("packed ref in the main repo of a linked worktree", func(t *testing.T) {
+		mainGitDir := filepath.Join(t.TempDir(), ".git")
+		writeGitHead(t, mainGitDir, "ref: refs/heads/main")
+		require.NoError(t, os.WriteFile(filepath.Join(mainGitDir, "packed-refs"),
+			[]byte("# pack-refs with: peeled fully-peeled sorted\n"+sha+" refs/heads/feature\n"), 0644))
+
+		worktreeGitDir := filepath.Join(mainGitDir, "worktrees", "feature")
+		writeGitHead(t, worktreeGitDir, "ref: refs/heads/feature")
+		require.NoError(t, os.WriteFile(filepath.Join(worktreeGitDir, "commondir"), []byte("../..\n"), 0644))
+
+		commit, err := readGitCommit(worktreeGitDir)
+		require.NoError(t, err)
+		assert.Equal(t, "3f2a9c1", commit)
+	})
+
+	t.Run("detached HEAD", func(t *testing.T) {
+		gitDir := filepath.Join(t.TempDir(), ".git")
+		writeGitHead(t, gitDir, sha)
+
+		commit, err := readGitCommit(gitDir)
+		require.NoError(t, err)
+		assert.Equal(t, "3f2a9c1", commit)
+	})
+
+	t.Run("branch without commits", func(t *testing.T) {
+		gitDir := filepath.Join(t.TempDir(), ".git")
+		writeGitHead(t, gitDir, "ref: refs/heads/main")
+
+		commit, err := readGitCommit(gitDir)
+		require.NoError(t, err)
+		assert.Empty(t, commit)
+	})
+}
+
+func TestFindGitDir(t *testing.T) {
+	root := t.TempDir()
+	mainGitDir := filepath.Join(root, "vcs", ".git")
+	writeGitHead(t, mainGitDir, "ref: refs/heads/main")
+
+	// Linked worktree: .git is a file pointing into the main repo
+	worktreeGitDir := filepath.Join(mainGitDir, "worktrees", "feature")
+	writeGitHead(t, worktreeGitDir, "ref: refs/heads/feature")
+	worktree := filepath.Join(root, "worktrees", "feature")
+	require.NoError(t, os.MkdirAll(filepath.Join(worktree, "src"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(worktree, ".git"),
+		[]byte("gitdir: ../../vcs/.git/worktrees/feature\n"), 0644))
+
+	t.Run("worktree gitdir file is followed", func(t *testing.T) {
+		gitDir, workTree, ok := findGitDir(filepath.Join(worktree, "src"), root)
+		require.True(t, ok)
+		assert.Equal(t, worktreeGitDir, gitDir)
+		assert.Equal(t, worktree, workTree)
+	})
+
+	t.Run("search stops at the boundary", func(t *testing.T) {
+		_, _, ok := findGitDir(root, root)
+		assert.False(t, ok)
+	})
+}
+
+func TestDetector_DetectGit(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "worktrees"), 0755))
+	writeGitHead(t, filepath.Join(root, "vcs", ".git"), "ref: refs/heads/main")
+
+	d := &Detector{workingDir: root}
+	ctx := &ProjectContext{ProjectRoot: root, DevelopmentMode: ModeMultiWorktree}
+	d.detectGit(ctx)
+
+	assert.Equal(t, "main", ctx.GitBranch)
+	assert.False(t, ctx.GitDetached)
+	assert.False(t, ctx.GitDirty, "dirty state is only computed when enabled")
+}
+
+func TestDetector_DetectGitDirty(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	root := t.TempDir()
+	out, err := exec.Command("git", "init", "-q", "-b", "main", root).CombinedOutput()
+	require.NoError(t, err, string(out))
+
+	d := &Detector{workingDir: root}
+	d.SetGitDirtyCheck(true)
+
+	ctx := &ProjectContext{ProjectRoot: root}
+	d.detectGit(ctx)
+	assert.Equal(t, "main", ctx.GitBranch)
+	assert.False(t, ctx.GitDirty)
+	assert.Empty(t, ctx.GitCommit, "no commits yet")
+
+	require.NoError(t, os.WriteFile(filepath.Join(root, "new.txt"), []byte("x"), 0644))
+	ctx = &ProjectContext{ProjectRoot: root}
+	d.detectGit(ctx)
+	assert.True(t, ctx.GitDirty)
+
+	out, err = exec.Command("git", "-C", root, "-c", "user.name=glide", "-c", "user.email=glide@example.com",
+		"commit", "-q", "--allow-empty", "-m", "initial").CombinedOutput()
+	require.NoError(t, err, string(out))
+	head, err := exec.Command("git", "-C", root, "rev-parse", "--short=7", "HEAD").Output()
+	require.NoError(t, err)
+
+	ctx = &ProjectContext{ProjectRoot: root}
+	d.detectGit(ctx)
+	assert.Equal(t, strings.TrimSpace(string(head)), ctx.GitCommit)
+}
+
+func TestDetector_DetectGitOutsideRepository(t *testing.T) {
+	root := t.TempDir()
+
+	d := &Detector{workingDir: root}
+	ctx := &ProjectContext{ProjectRoot: root}
+	d.detectGit(ctx)
+
+	assert.Empty(t, ctx.GitBranch)
+	assert.Empty(t, ctx.GitCommit)
+}
+
+func TestDetector_DetectSkipsGitInFastMode(t *testing.T) {
+	root := t.TempDir()
+	writeGitHead(t, filepath.Join(root, ".git"), "ref: refs/heads/main")
+
+	detect := func(mode DetectionMode) *ProjectContext {
+		d, err := NewDetector()
+		require.NoError(t, err)
+		d.workingDir = root
+		d.SetDetectionMode(mode)
+		ctx, err := d.Detect()
+		require.NoError(t, err)
+		return ctx
+	}
+
+	assert.Equal(t, "main", detect(DetectionStandard).GitBranch)
+	assert.Empty(t, detect(DetectionFast).GitBranch)
+}
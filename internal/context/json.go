@@ -0,0 +1,211 @@
+package context
+
+import (
+	"encoding/json"
+	"errors"
+	"regexp"
+	"strings"
+)
+
+// RedactedValue replaces sensitive values in Redacted contexts
+const RedactedValue = "****"
+
+// sensitiveKeyPattern matches extension data keys whose values are redacted,
+// such as DB_PASSWORD or api_token
+var sensitiveKeyPattern = regexp.MustCompile(`(?i)(passw(or)?d|secret|token|api_?key|private_?key|credential)`)
+
+// contextJSON is the canonical JSON shape of a ProjectContext.
+//
+// The deprecated Docker fields are not serialized separately: they are merged
+// into extensions.docker (compose_files, compose_override, docker_running,
+// docker_context, containers_status), the location plugins read them from.
+// Extension keys starting with "_" are internal and omitted.
+type contextJSON struct {
+	WorkingDir      string          `json:"working_dir"`
+	ProjectRoot     string          `json:"project_root"`
+	ProjectName     string          `json:"project_name,omitempty"`
+	DevelopmentMode DevelopmentMode `json:"development_mode"`
+	Location        LocationType    `json:"location"`
+	IsRoot          bool            `json:"is_root,omitempty"`
+	IsMainRepo      bool            `json:"is_main_repo,omitempty"`
+	IsWorktree      bool            `json:"is_worktree,omitempty"`
+	WorktreeName    string          `json:"worktree_name,omitempty"`
+
+	Git *gitJSON `json:"git,omitempty"`
+
+	Extensions map[string]interface{} `json:"extensions,omitempty"`
+
+	DetectedFrameworks []string                     `json:"detected_frameworks,omitempty"`
+	FrameworkVersions  map[string]string            `json:"framework_versions,omitempty"`
+	FrameworkCommands  map[string]string            `json:"framework_commands,omitempty"`
+	FrameworkMetadata  map[string]map[string]string `json:"framework_metadata,omitempty"`
+
+	CommandScope string `json:"command_scope,omitempty"`
+	Error        string `json:"error,omitempty"`
+}
+
+// gitJSON holds the git state of a serialized context
+type gitJSON struct {
+	Branch   string `json:"branch,omitempty"`
+	Commit   string `json:"commit,omitempty"`
+	Detached bool   `json:"detached,omitempty"`
+	Dirty    bool   `json:"dirty,omitempty"`
+}
+
+// MarshalJSON encodes the context in its canonical shape (see contextJSON)
+func (c *ProjectContext) MarshalJSON() ([]byte, error) {
+	out := contextJSON{
+		WorkingDir:         c.WorkingDir,
+		ProjectRoot:        c.ProjectRoot,
+		ProjectName:        c.ProjectName,
+		DevelopmentMode:    c.DevelopmentMode,
+		Location:           c.Location,
+		IsRoot:             c.IsRoot,
+		IsMainRepo:         c.IsMainRepo,
+		IsWorktree:         c.IsWorktree,
+		WorktreeName:       c.WorktreeName,
+		Extensions:         c.serializedExtensions(),
+		DetectedFrameworks: c.DetectedFrameworks,
+		FrameworkVersions:  c.FrameworkVersions,
+		FrameworkCommands:  c.FrameworkCommands,
+		FrameworkMetadata:  c.FrameworkMetadata,
+		CommandScope:       c.CommandScope,
+	}
+	if c.GitBranch != "" || c.GitCommit != "" {
+		out.Git = &gitJSON{Branch: c.GitBranch, Commit: c.GitCommit, Detached: c.GitDetached, Dirty: c.GitDirty}
+	}
+	if c.Error != nil {
+		out.Error = c.Error.Error()
+	}
+	return json.Marshal(out)
+}
+
+// UnmarshalJSON decodes a context from its canonical shape, restoring the
+// deprecated Docker fields from extensions.docker
+func (c *ProjectContext) UnmarshalJSON(data []byte) error {
+	var in contextJSON
+	if err := json.Unmarshal(data, &in); err != nil {
+		return err
+	}
+
+	*c = ProjectContext{
+		WorkingDir:         in.WorkingDir,
+		ProjectRoot:        in.ProjectRoot,
+		ProjectName:        in.ProjectName,
+		DevelopmentMode:    in.DevelopmentMode,
+		Location:           in.Location,
+		IsRoot:             in.IsRoot,
+		IsMainRepo:         in.IsMainRepo,
+		IsWorktree:         in.IsWorktree,
+		WorktreeName:       in.WorktreeName,
+		Extensions:         in.Extensions,
+		DetectedFrameworks: in.DetectedFrameworks,
+		FrameworkVersions:  in.FrameworkVersions,
+		FrameworkCommands:  in.FrameworkCommands,
+		FrameworkMetadata:  in.FrameworkMetadata,
+		CommandScope:       in.CommandScope,
+	}
+	if in.Git != nil {
+		c.GitBranch = in.Git.Branch
+		c.GitCommit = in.Git.Commit
+		c.GitDetached = in.Git.Detached
+		c.GitDirty = in.Git.Dirty
+	}
+	if in.Error != "" {
+		c.Error = errors.New(in.Error)
+	}
+
+	PopulateCompatibilityFields(c)
+	return nil
+}
+
+// serializedExtensions returns the extensions to serialize: the public
+// extensions, with the deprecated Docker fields merged into "docker"
+func (c *ProjectContext) serializedExtensions() map[string]interface{} {
+	extensions := make(map[string]interface{}, len(c.Extensions)+1)
+	for name, data := range c.Extensions {
+		if !strings.HasPrefix(name, "_") {
+			extensions[name] = data
+		}
+	}
+
+	docker := make(map[string]interface{})
+	if existing, ok := extensions["docker"].(map[string]interface{}); ok {
+		for key, value := range existing {
+			docker[key] = value
+		}
+	}
+	if len(c.ComposeFiles) > 0 {
+		docker["compose_files"] = c.ComposeFiles
+	}
+	if c.ComposeOverride != "" {
+		docker["compose_override"] = c.ComposeOverride
+	}
+	if c.DockerRunning {
+		docker["docker_running"] = true
+	}
+	if c.DockerContext != "" {
+		docker["docker_context"] = c.DockerContext
+	}
+	if len(c.ContainersStatus) > 0 {
+		docker["containers_status"] = c.ContainersStatus
+	}
+	if _, isMap := extensions["docker"].(map[string]interface{}); isMap || len(docker) > 0 {
+		extensions["docker"] = docker
+	}
+
+	if len(extensions) == 0 {
+		return nil
+	}
+	return extensions
+}
+
+// Redacted returns a copy of the context safe to print or share: extension
+// values under keys that look sensitive (passwords, secrets, tokens, API
+// keys, credentials) are replaced with RedactedValue, at any depth. An
+// extension whose data cannot be checked is replaced with RedactedValue as a
+// whole.
+func (c *ProjectContext) Redacted() *ProjectContext {
+	if c == nil {
+		return nil
+	}
+
+	redacted := c.Clone()
+	for name, data := range redacted.Extensions {
+		// Normalize structs to their JSON form so their keys can be checked
+		encoded, err := json.Marshal(data)
+		if err != nil {
+			redacted.Extensions[name] = RedactedValue
+			continue
+		}
+		var generic interface{}
+		if err := json.Unmarshal(encoded, &generic); err != nil {
+			redacted.Extensions[name] = RedactedValue
+			continue
+		}
+		redacted.Extensions[name] = redactValue(generic)
+	}
+	return redacted
+}
+
+// redactValue replaces values under sensitive keys in decoded JSON data
+func redactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			if sensitiveKeyPattern.MatchString(key) {
+				v[key] = RedactedValue
+			} else {
+				v[key] = redactValue(item)
+			}
+		}
+		return v
+	case []interface{}:
+		for i, item := range v {
+			v[i] = redactValue(item)
+		}
+		return v
+	default:
+		return v
+	}
+}
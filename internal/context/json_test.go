@@ -0,0 +1,127 @@
+package context
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProjectContext_MarshalJSON(t *testing.T) {
+	ctx := &ProjectContext{
+		WorkingDir:      "/project",
+		ProjectRoot:     "/project",
+		DevelopmentMode: ModeSingleRepo,
+		Location:        LocationProject,
+		GitBranch:       "main",
+		GitCommit:       "3f2a9c1",
+		Extensions: map[string]interface{}{
+			"docker":               map[string]interface{}{"services": []string{"php"}},
+			"_dockerCheckDeferred": true,
+		},
+		ComposeFiles:  []string{"compose.yaml"},
+		DockerRunning: true,
+		Error:         errors.New("partial detection"),
+	}
+
+	data, err := json.Marshal(ctx)
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `{
+		"working_dir": "/project",
+		"project_root": "/project",
+		"development_mode": "single-repo",
+		"location": "project",
+		"git": {"branch": "main", "commit": "3f2a9c1"},
+		"extensions": {
+			"docker": {"services": ["php"], "compose_files": ["compose.yaml"], "docker_running": true}
+		},
+		"error": "partial detection"
+	}`, string(data))
+
+	// Serializing leaves the context itself alone
+	assert.Equal(t, map[string]interface{}{"services": []string{"php"}}, ctx.Extensions["docker"])
+}
+
+func TestProjectContext_UnmarshalJSON(t *testing.T) {
+	original := &ProjectContext{
+		WorkingDir:      "/project/worktrees/feature",
+		ProjectRoot:     "/project",
+		DevelopmentMode: ModeMultiWorktree,
+		Location:        LocationWorktree,
+		IsWorktree:      true,
+		WorktreeName:    "feature",
+		GitBranch:       "feature",
+		GitDirty:        true,
+		Extensions: map[string]interface{}{
+			"node": map[string]interface{}{"version": "20"},
+		},
+		ComposeFiles:    []string{"compose.yaml"},
+		ComposeOverride: "compose.override.yaml",
+		DockerContext:   "remote",
+	}
+
+	data, err := json.Marshal(original)
+	require.NoError(t, err)
+
+	var decoded ProjectContext
+	require.NoError(t, json.Unmarshal(data, &decoded))
+
+	assert.Equal(t, original.WorktreeName, decoded.WorktreeName)
+	assert.Equal(t, original.GitBranch, decoded.GitBranch)
+	assert.True(t, decoded.GitDirty)
+	assert.Equal(t, original.ComposeFiles, decoded.ComposeFiles)
+	assert.Equal(t, original.ComposeOverride, decoded.ComposeOverride)
+	assert.Equal(t, original.DockerContext, decoded.DockerContext)
+	assert.Equal(t, "20", decoded.Extensions["node"].(map[string]interface{})["version"])
+}
+
+func TestProjectContext_Redacted(t *testing.T) {
+	original := &ProjectContext{
+		ProjectRoot: "/project",
+		Extensions: map[string]interface{}{
+			"docker": map[string]interface{}{
+				"env": map[string]string{"DB_PASSWORD": "hunter2", "DB_HOST": "db"},
+				"services": []interface{}{
+					map[string]interface{}{"name": "api", "api_key": "abc"},
+				},
+			},
+			"custom": struct {
+				Token string `json:"token"`
+				Name  string `json:"name"`
+			}{Token: "t0k", Name: "app"},
+		},
+	}
+
+	redacted := original.Redacted()
+
+	docker := redacted.Extensions["docker"].(map[string]interface{})
+	env := docker["env"].(map[string]interface{})
+	assert.Equal(t, RedactedValue, env["DB_PASSWORD"])
+	assert.Equal(t, "db", env["DB_HOST"])
+	service := docker["services"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, RedactedValue, service["api_key"])
+	assert.Equal(t, "api", service["name"])
+
+	custom := redacted.Extensions["custom"].(map[string]interface{})
+	assert.Equal(t, RedactedValue, custom["token"])
+	assert.Equal(t, "app", custom["name"])
+
+	// The original keeps its values
+	assert.Equal(t, "hunter2", original.Extensions["docker"].(map[string]interface{})["env"].(map[string]string)["DB_PASSWORD"])
+}
+
+func TestProjectContext_Redacted_UnencodableExtension(t *testing.T) {
+	original := &ProjectContext{
+		Extensions: map[string]interface{}{
+			// Channels cannot be encoded, so the keys cannot be checked
+			"broken": map[string]interface{}{"password": "hunter2", "updates": make(chan int)},
+		},
+	}
+
+	redacted := original.Redacted()
+
+	assert.Equal(t, RedactedValue, redacted.Extensions["broken"])
+}
@@ -3,6 +3,7 @@ package context
 import (
 	"context"
 
+	"github.com/glide-cli/glide/v3/pkg/logging"
 	"github.com/glide-cli/glide/v3/pkg/plugin/sdk"
 )
 
@@ -11,35 +12,48 @@ type pluginExtensionAdapter struct {
 	providers []interface{}
 }
 
-// DetectAll runs detection for all registered plugins that provide context extensions
+// DetectAll runs detection for all registered plugins that provide context extensions.
+// A plugin may contribute several extensions; if two extensions share a name,
+// the first one registered wins and the collision is logged. Extensions are
+// detected concurrently, and any still running after sdk.DefaultDetectTimeout
+// are left out of the results. Failed extensions are logged at debug level.
 func (a *pluginExtensionAdapter) DetectAll(projectRoot string) (map[string]interface{}, error) {
-	results := make(map[string]interface{})
-	ctx := context.Background()
+	registry := sdk.NewExtensionRegistry()
+	registry.SetDetectTimeout(sdk.DefaultDetectTimeout)
+	owners := make(map[string]string)
 
 	for _, p := range a.providers {
-		// Check if plugin provides context extension
-		provider, ok := p.(sdk.ContextProvider)
-		if !ok {
-			continue
-		}
-
-		ext := provider.ProvideContext()
-		if ext == nil {
-			continue
-		}
-
-		// Detect extension data
-		data, err := ext.Detect(ctx, projectRoot)
-		if err != nil {
-			// Continue with other extensions if one fails
-			// Don't break the entire detection process
-			continue
+		owner := providerName(p)
+
+		for _, ext := range sdk.ProvidedExtensions(p) {
+			name := ext.Name()
+			if name == "" {
+				continue
+			}
+
+			if existing, ok := owners[name]; ok {
+				logging.Warn("Ignoring duplicate context extension",
+					"extension", name, "plugin", owner, "registeredBy", existing)
+				continue
+			}
+			owners[name] = owner
+
+			// Empty and duplicate names are filtered above
+			_ = registry.Register(ext)
 		}
+	}
 
-		if data != nil {
-			results[ext.Name()] = data
-		}
+	results, failures, err := registry.DetectAllWithErrors(context.Background(), projectRoot)
+	for name, failure := range failures {
+		logging.Debug("Context extension detection failed", "extension", name, "error", failure)
 	}
+	return results, err
+}
 
-	return results, nil
+// providerName returns a plugin's name for diagnostics, if it has one
+func providerName(p interface{}) string {
+	if named, ok := p.(interface{ Name() string }); ok {
+		return named.Name()
+	}
+	return "unknown"
 }
@@ -0,0 +1,130 @@
+package context
+
+import (
+	"context"
+	"testing"
+
+	"github.com/glide-cli/glide/v3/pkg/plugin/sdk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubExtension struct {
+	name string
+	data interface{}
+}
+
+func (e *stubExtension) Name() string { return e.name }
+
+func (e *stubExtension) Detect(ctx context.Context, projectRoot string) (interface{}, error) {
+	return e.data, nil
+}
+
+func (e *stubExtension) Merge(existing interface{}, new interface{}) (interface{}, error) {
+	return new, nil
+}
+
+type multiExtensionPlugin struct {
+	name       string
+	extensions []sdk.ContextExtension
+}
+
+func (p *multiExtensionPlugin) Name() string                            { return p.name }
+func (p *multiExtensionPlugin) ProvideContexts() []sdk.ContextExtension { return p.extensions }
+
+type singleExtensionPlugin struct {
+	extension sdk.ContextExtension
+}
+
+func (p *singleExtensionPlugin) ProvideContext() sdk.ContextExtension { return p.extension }
+
+func TestPluginExtensionAdapter_MultipleExtensions(t *testing.T) {
+	adapter := &pluginExtensionAdapter{providers: []interface{}{
+		&singleExtensionPlugin{extension: &stubExtension{name: "docker", data: "compose"}},
+		&multiExtensionPlugin{name: "cloud", extensions: []sdk.ContextExtension{
+			&stubExtension{name: "aws", data: map[string]interface{}{"region": "eu-west-1"}},
+			&stubExtension{name: "kubernetes", data: map[string]interface{}{"cluster": "dev"}},
+		}},
+	}}
+
+	results, err := adapter.DetectAll("/project")
+	require.NoError(t, err)
+
+	assert.Equal(t, "compose", results["docker"])
+	assert.Equal(t, map[string]interface{}{"region": "eu-west-1"}, results["aws"])
+	assert.Equal(t, map[string]interface{}{"cluster": "dev"}, results["kubernetes"])
+}
+
+func TestPluginExtensionAdapter_NameCollision(t *testing.T) {
+	adapter := &pluginExtensionAdapter{providers: []interface{}{
+		&multiExtensionPlugin{name: "cloud", extensions: []sdk.ContextExtension{
+			&stubExtension{name: "aws", data: "first"},
+			&stubExtension{name: "aws", data: "duplicate"},
+		}},
+		&singleExtensionPlugin{extension: &stubExtension{name: "aws", data: "other-plugin"}},
+	}}
+
+	results, err := adapter.DetectAll("/project")
+	require.NoError(t, err)
+
+	assert.Len(t, results, 1)
+	assert.Equal(t, "first", results["aws"])
+}
+
+// schemaExtension declares the shape of its detection output
+type schemaExtension struct {
+	stubExtension
+	schema *sdk.ConfigSchema
+}
+
+func (e *schemaExtension) DataSchema() *sdk.ConfigSchema { return e.schema }
+
+func TestValidateExtensions(t *testing.T) {
+	dockerSchema := &sdk.ConfigSchema{
+		Name: "docker",
+		Fields: []sdk.FieldSchema{
+			{Name: "docker_running", Type: "bool", Required: true},
+			{Name: "compose_files", Type: "array"},
+			{Name: "compose_override", Type: "string"},
+		},
+	}
+
+	providers := []interface{}{
+		&multiExtensionPlugin{name: "docker", extensions: []sdk.ContextExtension{
+			&schemaExtension{
+				stubExtension: stubExtension{name: "docker", data: map[string]interface{}{
+					"docker_running": true,
+					"compose_files":  "docker-compose.yml", // Drifted: should be a list
+				}},
+				schema: dockerSchema,
+			},
+			&schemaExtension{
+				stubExtension: stubExtension{name: "compose", data: struct {
+					DockerRunning bool     `json:"docker_running"`
+					ComposeFiles  []string `json:"compose_files"`
+				}{true, []string{"docker-compose.yml"}}},
+				schema: dockerSchema,
+			},
+			&schemaExtension{stubExtension: stubExtension{name: "absent"}, schema: dockerSchema},
+			&stubExtension{name: "unschematized", data: "anything"},
+		}},
+	}
+
+	results := ValidateExtensions(context.Background(), providers, "/project")
+	require.Len(t, results, 3)
+
+	assert.Equal(t, "absent", results[0].Extension)
+	assert.False(t, results[0].Detected)
+	assert.True(t, results[0].OK())
+
+	assert.Equal(t, "compose", results[1].Extension)
+	assert.True(t, results[1].Detected)
+	assert.True(t, results[1].OK(), "typed structs and []string should match the schema: %v", results[1].Errors)
+
+	assert.Equal(t, "docker", results[2].Extension)
+	assert.Equal(t, "docker", results[2].Plugin)
+	assert.False(t, results[2].OK())
+	require.Len(t, results[2].Errors, 1)
+	assert.Equal(t, "compose_files", results[2].Errors[0].Field)
+	assert.Contains(t, results[2].Errors[0].Message, "expected array")
+}
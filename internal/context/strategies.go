@@ -49,12 +49,24 @@ func NewStandardProjectRootFinder() *StandardProjectRootFinder {
 	}
 }
 
-// FindRoot finds the project root directory
+// FindRoot finds the project root directory. Inside a multi-worktree
+// layout (vcs/ plus worktrees/<name>/) the root is the directory holding
+// both, even when the checkout itself has a .git file or .glide.yml.
 func (f *StandardProjectRootFinder) FindRoot(workingDir string) (string, error) {
 	current := workingDir
 	traversed := 0
 
 	for traversed < f.maxTraversal {
+		// Check if we're in vcs/ or worktrees/<name>/ of a multi-worktree project
+		if root, ok := multiWorktreeRootOf(current); ok {
+			return root, nil
+		}
+
+		// Check for multi-worktree structure (has vcs/ directory)
+		if isMultiWorktreeRoot(current) {
+			return current, nil
+		}
+
 		// Check for .glide.yml file (indicates a Glide project)
 		glidePath := filepath.Join(current, ".glide.yml")
 		if _, err := os.Stat(glidePath); err == nil {
@@ -62,41 +74,10 @@ func (f *StandardProjectRootFinder) FindRoot(workingDir string) (string, error)
 			return current, nil
 		}
 
-		// Check for multi-worktree structure (has vcs/ directory)
-		vcsPath := filepath.Join(current, "vcs")
-		if info, err := os.Stat(vcsPath); err == nil && info.IsDir() {
-			// Check if vcs contains a git repo
-			gitPath := filepath.Join(vcsPath, ".git")
-			if _, err := os.Stat(gitPath); err == nil {
-				return current, nil
-			}
-		}
-
 		// Check for single-repo structure (has .git in current)
 		gitPath := filepath.Join(current, ".git")
 		if _, err := os.Stat(gitPath); err == nil {
-			// Make sure this isn't inside vcs/ or worktrees/
-			if !strings.Contains(current, "/vcs") && !strings.Contains(current, "/worktrees/") {
-				return current, nil
-			}
-		}
-
-		// Check if we're inside a worktree
-		if strings.Contains(current, "/worktrees/") {
-			// Find the project root (should be two levels up from worktrees/*/
-			parts := strings.Split(current, "/worktrees/")
-			if len(parts) > 0 {
-				return parts[0], nil
-			}
-		}
-
-		// Check if we're inside vcs/
-		if strings.Contains(current, "/vcs") {
-			// Project root should be one level up
-			parts := strings.Split(current, "/vcs")
-			if len(parts) > 0 {
-				return parts[0], nil
-			}
+			return current, nil
 		}
 
 		// Move up one directory
@@ -111,6 +92,33 @@ func (f *StandardProjectRootFinder) FindRoot(workingDir string) (string, error)
 	return "", ErrProjectRootNotFound
 }
 
+// isMultiWorktreeRoot reports whether dir holds the main repository of a
+// multi-worktree project in vcs/
+func isMultiWorktreeRoot(dir string) bool {
+	info, err := os.Stat(filepath.Join(dir, "vcs"))
+	if err != nil || !info.IsDir() {
+		return false
+	}
+	_, err = os.Stat(filepath.Join(dir, "vcs", ".git"))
+	return err == nil
+}
+
+// multiWorktreeRootOf returns the project root if dir is the vcs/ directory
+// or a worktrees/<name>/ directory of a multi-worktree project
+func multiWorktreeRootOf(dir string) (string, bool) {
+	parent := filepath.Dir(dir)
+	if filepath.Base(dir) == "vcs" && isMultiWorktreeRoot(parent) {
+		return parent, true
+	}
+
+	grandparent := filepath.Dir(parent)
+	if filepath.Base(parent) == "worktrees" && isMultiWorktreeRoot(grandparent) {
+		return grandparent, true
+	}
+
+	return "", false
+}
+
 // StandardDevelopmentModeDetector implements standard mode detection
 type StandardDevelopmentModeDetector struct{}
 
@@ -202,51 +210,66 @@ func NewStandardComposeFileResolver() *StandardComposeFileResolver {
 	return &StandardComposeFileResolver{}
 }
 
-// ResolveFiles finds all docker-compose files based on location
+// composeFileNames are the base compose file names, canonical Compose v2
+// names first
+var composeFileNames = []string{
+	"compose.yaml",
+	"compose.yml",
+	"docker-compose.yaml",
+	"docker-compose.yml",
+}
+
+// composeOverrideFileNames are the compose override file names, in the same
+// order as composeFileNames
+var composeOverrideFileNames = []string{
+	"compose.override.yaml",
+	"compose.override.yml",
+	"docker-compose.override.yaml",
+	"docker-compose.override.yml",
+}
+
+// ResolveFiles finds all docker-compose files based on location. Every base
+// file found is returned before every override, each in the order of
+// composeFileNames, so the resulting -f flags are predictable. The first
+// override found is also stored as ctx.ComposeOverride.
 func (r *StandardComposeFileResolver) ResolveFiles(ctx *ProjectContext) []string {
 	files := []string{}
 
+	var baseDir string
 	switch ctx.Location {
 	case LocationMainRepo:
-		// From vcs/: docker-compose.yml + ../docker-compose.override.yml
-		composePath := filepath.Join(ctx.ProjectRoot, "vcs", "docker-compose.yml")
-		if _, err := os.Stat(composePath); err == nil {
-			files = append(files, composePath)
-		}
-
-		overridePath := filepath.Join(ctx.ProjectRoot, "docker-compose.override.yml")
-		if _, err := os.Stat(overridePath); err == nil {
-			ctx.ComposeOverride = overridePath
-			files = append(files, overridePath)
-		}
-
+		// From vcs/: compose file + ../compose override
+		baseDir = filepath.Join(ctx.ProjectRoot, "vcs")
 	case LocationWorktree:
-		// From worktrees/*/: docker-compose.yml + ../../docker-compose.override.yml
-		worktreePath := filepath.Join(ctx.ProjectRoot, "worktrees", ctx.WorktreeName)
-		composePath := filepath.Join(worktreePath, "docker-compose.yml")
-		if _, err := os.Stat(composePath); err == nil {
-			files = append(files, composePath)
-		}
-
-		overridePath := filepath.Join(ctx.ProjectRoot, "docker-compose.override.yml")
-		if _, err := os.Stat(overridePath); err == nil {
-			ctx.ComposeOverride = overridePath
-			files = append(files, overridePath)
-		}
-
+		// From worktrees/*/: compose file + ../../compose override
+		baseDir = filepath.Join(ctx.ProjectRoot, "worktrees", ctx.WorktreeName)
 	case LocationProject:
-		// Single-repo mode: docker-compose.yml + docker-compose.override.yml
-		composePath := filepath.Join(ctx.ProjectRoot, "docker-compose.yml")
-		if _, err := os.Stat(composePath); err == nil {
-			files = append(files, composePath)
-		}
+		// Single-repo mode: compose file + override side by side
+		baseDir = ctx.ProjectRoot
+	default:
+		return files
+	}
 
-		overridePath := filepath.Join(ctx.ProjectRoot, "docker-compose.override.yml")
-		if _, err := os.Stat(overridePath); err == nil {
-			ctx.ComposeOverride = overridePath
-			files = append(files, overridePath)
-		}
+	files = append(files, existingFiles(baseDir, composeFileNames)...)
+
+	// Overrides always live at the project root
+	overrides := existingFiles(ctx.ProjectRoot, composeOverrideFileNames)
+	if len(overrides) > 0 {
+		ctx.ComposeOverride = overrides[0]
 	}
+	files = append(files, overrides...)
 
 	return files
 }
+
+// existingFiles returns the paths of the named files that exist in dir
+func existingFiles(dir string, names []string) []string {
+	var found []string
+	for _, name := range names {
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); err == nil {
+			found = append(found, path)
+		}
+	}
+	return found
+}
@@ -66,6 +66,59 @@ func TestStandardProjectRootFinder_FindRoot(t *testing.T) {
 	}
 }
 
+// TestDetector_MultiWorktreeLayout verifies mode and location detection in
+// a vcs/ + worktrees/<name>/ project, where the checkouts carry their own
+// .git file and .glide.yml
+func TestDetector_MultiWorktreeLayout(t *testing.T) {
+	root := t.TempDir()
+	vcs := filepath.Join(root, "vcs")
+	worktree := filepath.Join(root, "worktrees", "feature-login")
+	require.NoError(t, os.MkdirAll(filepath.Join(vcs, ".git"), 0755))
+	require.NoError(t, os.MkdirAll(filepath.Join(worktree, "src", "app"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(worktree, ".git"), []byte("gitdir: ../../vcs/.git/worktrees/feature-login\n"), 0644))
+	for _, dir := range []string{vcs, worktree} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, ".glide.yml"), []byte("commands: {}\n"), 0644))
+	}
+
+	tests := []struct {
+		name         string
+		workingDir   string
+		wantLocation LocationType
+		wantWorktree string
+	}{
+		{name: "project root", workingDir: root, wantLocation: LocationRoot},
+		{name: "main repo", workingDir: vcs, wantLocation: LocationMainRepo},
+		{name: "worktree", workingDir: worktree, wantLocation: LocationWorktree, wantWorktree: "feature-login"},
+		{name: "inside a worktree", workingDir: filepath.Join(worktree, "src", "app"), wantLocation: LocationWorktree, wantWorktree: "feature-login"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := NewDetectorFast()
+			require.NoError(t, err)
+			d.workingDir = tt.workingDir
+
+			ctx, err := d.Detect()
+			require.NoError(t, err)
+			assert.Equal(t, root, ctx.ProjectRoot)
+			assert.Equal(t, ModeMultiWorktree, ctx.DevelopmentMode)
+			assert.Equal(t, tt.wantLocation, ctx.Location)
+			assert.Equal(t, tt.wantWorktree, ctx.WorktreeName)
+		})
+	}
+}
+
+func TestStandardProjectRootFinder_PathsNamedLikeTheLayout(t *testing.T) {
+	// A single repo whose path merely contains "vcs" or "worktrees"
+	root := filepath.Join(t.TempDir(), "vcs-tools", "worktrees", "app")
+	require.NoError(t, os.MkdirAll(filepath.Join(root, ".git"), 0755))
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "cmd"), 0755))
+
+	got, err := NewStandardProjectRootFinder().FindRoot(filepath.Join(root, "cmd"))
+	require.NoError(t, err)
+	assert.Equal(t, root, got)
+}
+
 func TestNewStandardDevelopmentModeDetector(t *testing.T) {
 	detector := NewStandardDevelopmentModeDetector()
 	assert.NotNil(t, detector)
@@ -245,6 +298,81 @@ func TestStandardComposeFileResolver_ResolveFiles(t *testing.T) {
 	}
 }
 
+func TestStandardComposeFileResolver_ComposeFileNames(t *testing.T) {
+	resolver := NewStandardComposeFileResolver()
+
+	tests := []struct {
+		name         string
+		location     LocationType
+		files        []string
+		wantFiles    []string
+		wantOverride string
+	}{
+		{
+			name:      "compose v2 names",
+			location:  LocationProject,
+			files:     []string{"compose.yaml"},
+			wantFiles: []string{"compose.yaml"},
+		},
+		{
+			name:         "every match with bases before overrides",
+			location:     LocationProject,
+			files:        []string{"docker-compose.override.yml", "docker-compose.yml", "compose.override.yaml", "compose.yml"},
+			wantFiles:    []string{"compose.yml", "docker-compose.yml", "compose.override.yaml", "docker-compose.override.yml"},
+			wantOverride: "compose.override.yaml",
+		},
+		{
+			name:         "yaml extension",
+			location:     LocationProject,
+			files:        []string{"docker-compose.yaml", "docker-compose.override.yaml"},
+			wantFiles:    []string{"docker-compose.yaml", "docker-compose.override.yaml"},
+			wantOverride: "docker-compose.override.yaml",
+		},
+		{
+			name:         "main repo",
+			location:     LocationMainRepo,
+			files:        []string{"vcs/compose.yaml", "compose.override.yml", "compose.yaml"},
+			wantFiles:    []string{"vcs/compose.yaml", "compose.override.yml"},
+			wantOverride: "compose.override.yml",
+		},
+		{
+			name:      "worktree",
+			location:  LocationWorktree,
+			files:     []string{"worktrees/feature/compose.yml", "vcs/compose.yml"},
+			wantFiles: []string{"worktrees/feature/compose.yml"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempDir := t.TempDir()
+			for _, file := range tt.files {
+				path := filepath.Join(tempDir, file)
+				require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+				require.NoError(t, os.WriteFile(path, []byte("services: {}\n"), 0644))
+			}
+
+			ctx := &ProjectContext{
+				ProjectRoot:  tempDir,
+				Location:     tt.location,
+				WorktreeName: "feature",
+			}
+
+			wantFiles := make([]string, len(tt.wantFiles))
+			for i, file := range tt.wantFiles {
+				wantFiles[i] = filepath.Join(tempDir, file)
+			}
+			assert.Equal(t, wantFiles, resolver.ResolveFiles(ctx))
+
+			wantOverride := ""
+			if tt.wantOverride != "" {
+				wantOverride = filepath.Join(tempDir, tt.wantOverride)
+			}
+			assert.Equal(t, wantOverride, ctx.ComposeOverride)
+		})
+	}
+}
+
 func TestProjectContext_LocationHelpers(t *testing.T) {
 	tests := []struct {
 		name string
@@ -43,11 +43,11 @@ const (
 
 // ContainerStatus represents the status of a Docker container
 type ContainerStatus struct {
-	Name      string
-	Status    string // running, stopped, exited, etc.
-	Health    string // healthy, unhealthy, starting, none
-	StartedAt time.Time
-	Ports     []string
+	Name      string    `json:"name"`
+	Status    string    `json:"status"` // running, stopped, exited, etc.
+	Health    string    `json:"health"` // healthy, unhealthy, starting, none
+	StartedAt time.Time `json:"started_at,omitempty"`
+	Ports     []string  `json:"ports,omitempty"`
 }
 
 // ProjectContext contains all context information about the current project
@@ -67,6 +67,12 @@ type ProjectContext struct {
 	IsWorktree   bool   // True if in worktrees/*/ (multi-worktree only)
 	WorktreeName string // Name of current worktree if applicable
 
+	// Git state
+	GitBranch   string // Current branch, or short commit SHA when HEAD is detached
+	GitCommit   string // Short SHA of the checked out commit; empty before the first commit
+	GitDetached bool   // True if HEAD is detached
+	GitDirty    bool   // True if the work tree has uncommitted changes (only set when dirty checks are enabled)
+
 	// Plugin extensions
 	Extensions map[string]interface{} // Plugin-provided context extensions
 
@@ -74,6 +80,7 @@ type ProjectContext struct {
 	ComposeFiles     []string                   // Resolved docker-compose files
 	ComposeOverride  string                     // Path to override file
 	DockerRunning    bool                       // Is Docker daemon running
+	DockerContext    string                     // Active docker context, e.g. from `docker context use`
 	ContainersStatus map[string]ContainerStatus // Status of all containers
 
 	// Framework detection
@@ -102,15 +102,16 @@ func (b *CommandBuilder) Build() *exec.Cmd {
 	// Configure I/O
 	b.configureIO(execCmd)
 
+	if b.ctx != nil {
+		killProcessGroupOnCancel(execCmd)
+	}
+
 	return execCmd
 }
 
 // configureEnvironment sets up the command environment
 func (b *CommandBuilder) configureEnvironment(execCmd *exec.Cmd) {
-	if len(b.cmd.Environment) > 0 {
-		execCmd.Env = os.Environ()
-		execCmd.Env = append(execCmd.Env, b.cmd.Environment...)
-	}
+	execCmd.Env = buildEnv(b.cmd.InheritEnv, b.cmd.Options.GlobalEnv, b.cmd.Environment)
 }
 
 // configureIO sets up standard I/O for the command
@@ -215,7 +216,7 @@ func (b *CommandBuilder) ExecuteAndCollectResult(execCmd *exec.Cmd, stdout, stde
 func (b *CommandBuilder) handleError(err error, result *Result) {
 	if b.ctx != nil && b.ctx.Err() == context.DeadlineExceeded {
 		result.Timeout = true
-		result.Error = err
+		result.Error = fmt.Errorf("%w: %w", ErrTimeout, err)
 	} else if exitError, ok := err.(*exec.ExitError); ok {
 		result.ExitCode = exitError.ExitCode()
 		result.Error = err
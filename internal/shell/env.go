@@ -0,0 +1,56 @@
+package shell
+
+import (
+	"os"
+	"strings"
+)
+
+// buildEnv returns the environment for a command: the parent process
+// environment if inherit is set, overlaid with each layer of KEY=VALUE
+// entries in turn. A later value for a key replaces an earlier one in place,
+// so explicitly set variables win over inherited ones and the order is
+// stable.
+//
+// Without inherit and without any entries the result is nil, so exec falls
+// back to the parent environment as it does for a zero-value Command;
+// otherwise it is never nil.
+func buildEnv(inherit bool, layers ...[]string) []string {
+	var base []string
+	if inherit {
+		base = os.Environ()
+	} else if !hasEntries(layers) {
+		return nil
+	}
+
+	env := make([]string, 0, len(base))
+	index := make(map[string]int, len(base))
+	add := func(entry string) {
+		key, _, _ := strings.Cut(entry, "=")
+		if i, ok := index[key]; ok {
+			env[i] = entry
+			return
+		}
+		index[key] = len(env)
+		env = append(env, entry)
+	}
+
+	for _, entry := range base {
+		add(entry)
+	}
+	for _, layer := range layers {
+		for _, entry := range layer {
+			add(entry)
+		}
+	}
+	return env
+}
+
+// hasEntries reports whether any layer has an entry
+func hasEntries(layers [][]string) bool {
+	for _, layer := range layers {
+		if len(layer) > 0 {
+			return true
+		}
+	}
+	return false
+}
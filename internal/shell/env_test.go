@@ -0,0 +1,106 @@
+package shell
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildEnv(t *testing.T) {
+	t.Setenv("GLIDE_ENV_TEST_INHERITED", "parent")
+	t.Setenv("GLIDE_ENV_TEST_OVERRIDDEN", "parent")
+
+	env := buildEnv(true,
+		[]string{"GLIDE_ENV_TEST_GLOBAL=global", "GLIDE_ENV_TEST_OVERRIDDEN=global"},
+		[]string{"GLIDE_ENV_TEST_OVERRIDDEN=command", "GLIDE_ENV_TEST_NEW=command"},
+	)
+
+	values := make(map[string][]string)
+	var order []string
+	for _, entry := range env {
+		key, value, _ := strings.Cut(entry, "=")
+		if strings.HasPrefix(key, "GLIDE_ENV_TEST_") {
+			values[key] = append(values[key], value)
+			order = append(order, key)
+		}
+	}
+	assert.Equal(t, map[string][]string{
+		"GLIDE_ENV_TEST_INHERITED":  {"parent"},
+		"GLIDE_ENV_TEST_OVERRIDDEN": {"command"},
+		"GLIDE_ENV_TEST_GLOBAL":     {"global"},
+		"GLIDE_ENV_TEST_NEW":        {"command"},
+	}, values, "each variable appears once with the most specific value")
+	assert.Equal(t, []string{"GLIDE_ENV_TEST_GLOBAL", "GLIDE_ENV_TEST_NEW"}, order[len(order)-2:],
+		"new variables follow the inherited ones in the order given")
+
+	assert.Equal(t, []string{"ONLY=1"}, buildEnv(false, []string{"ONLY=1"}))
+	assert.Nil(t, buildEnv(false), "no variables at all falls back to the parent environment")
+	assert.Nil(t, buildEnv(false, nil, []string{}))
+}
+
+func TestCommand_WithEnvVars(t *testing.T) {
+	cmd := NewCommand("env").WithEnv("FIRST=1").WithEnvVars(map[string]string{
+		"ZETA":  "z",
+		"ALPHA": "a",
+	})
+	assert.Equal(t, []string{"FIRST=1", "ALPHA=a", "ZETA=z"}, cmd.Environment)
+}
+
+func TestExecutor_Environment(t *testing.T) {
+	t.Setenv("GLIDE_ENV_TEST", "inherited")
+	executor := NewExecutor(Options{GlobalEnv: []string{"GLIDE_ENV_TEST=global", "GLIDE_ENV_GLOBAL=global"}})
+	script := `echo "$GLIDE_ENV_TEST $GLIDE_ENV_GLOBAL"`
+
+	t.Run("command variables win", func(t *testing.T) {
+		cmd := NewCommand("sh", "-c", script).WithEnvVars(map[string]string{"GLIDE_ENV_TEST": "command"})
+
+		result, err := executor.Execute(cmd)
+		require.NoError(t, err)
+		assert.Equal(t, "command global", strings.TrimSpace(string(result.Stdout)))
+
+		cmd.CaptureOutput = true
+		result, err = executor.ExecuteWithContext(context.Background(), cmd)
+		require.NoError(t, err)
+		assert.Equal(t, "command global", strings.TrimSpace(string(result.Stdout)))
+
+		cmd.UseStrategy = true
+		result, err = executor.Execute(cmd)
+		require.NoError(t, err)
+		assert.Equal(t, "command global", strings.TrimSpace(string(result.Stdout)))
+	})
+
+	t.Run("global variables reach strategies", func(t *testing.T) {
+		cmd := NewCommand("sh", "-c", script)
+		cmd.CaptureOutput = true
+
+		result, err := executor.ExecuteWithContext(context.Background(), cmd)
+		require.NoError(t, err)
+		assert.Equal(t, "global global", strings.TrimSpace(string(result.Stdout)))
+		assert.Empty(t, cmd.Options.GlobalEnv, "the caller's command is left as is")
+	})
+
+	t.Run("without inheritance", func(t *testing.T) {
+		cmd := NewCommand("/bin/sh", "-c", `echo "[$GLIDE_ENV_TEST][$HOME][$ONLY]"`).WithEnv("ONLY=1")
+		cmd.InheritEnv = false
+
+		result, err := NewExecutor(Options{}).Execute(cmd)
+		require.NoError(t, err)
+		assert.Equal(t, "[][][1]", strings.TrimSpace(string(result.Stdout)))
+
+		cmd.CaptureOutput = true
+		result, err = NewExecutor(Options{}).ExecuteWithContext(context.Background(), cmd)
+		require.NoError(t, err)
+		assert.Equal(t, "[][][1]", strings.TrimSpace(string(result.Stdout)))
+	})
+
+	t.Run("zero-value command keeps the parent environment", func(t *testing.T) {
+		cmd := &Command{Name: "sh", Args: []string{"-c", `echo "$GLIDE_ENV_TEST"`}, CaptureOutput: true}
+
+		result, err := NewExecutor(Options{}).ExecuteWithContext(context.Background(), cmd)
+		require.NoError(t, err)
+		assert.Equal(t, "inherited", strings.TrimSpace(string(result.Stdout)))
+	})
+}
@@ -3,6 +3,7 @@ package shell
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -12,8 +13,56 @@ import (
 	"time"
 
 	"github.com/fatih/color"
+	"golang.org/x/term"
 )
 
+// ErrTimeout is the Result error of a command that was killed because its
+// timeout expired. Check for it with errors.Is.
+var ErrTimeout = errors.New("command timed out")
+
+// ExitError is returned by Run, RunCapture and RunWithTimeout when a command
+// exits non-zero. Code reports the exit code, which the error handler uses as
+// glide's own exit code.
+type ExitError struct {
+	Command string
+	Status  int
+	Err     error // The underlying execution error, if any
+}
+
+func (e *ExitError) Error() string {
+	return fmt.Sprintf("command failed with exit code %d", e.Status)
+}
+
+// Code returns the command's exit code
+func (e *ExitError) Code() int {
+	return e.Status
+}
+
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+// ResultError returns the error for a finished command: ErrTimeout if it
+// timed out, an ExitError if it exited non-zero, and otherwise the result's
+// execution error. Callers running commands through a CommandExecutor use it
+// to report failures the way Run does.
+func ResultError(cmd *Command, result *Result) error {
+	if result.Timeout {
+		if result.Error != nil {
+			return result.Error
+		}
+		return fmt.Errorf("%w after %s", ErrTimeout, cmd.Timeout)
+	}
+	if result.ExitCode != 0 {
+		return &ExitError{Command: cmd.String(), Status: result.ExitCode, Err: result.Error}
+	}
+	return result.Error
+}
+
+// processWaitDelay bounds how long output is awaited after a command is
+// killed, in case something outside its process group holds the pipes
+const processWaitDelay = time.Second
+
 // Executor handles command execution
 type Executor struct {
 	options  Options
@@ -30,14 +79,23 @@ func NewExecutor(options Options) *Executor {
 	}
 }
 
-// Execute runs a command based on its mode or strategy
+// Execute runs a command based on its mode or strategy, retrying failed runs
+// as configured
 func (e *Executor) Execute(cmd *Command) (*Result, error) {
+	return e.withRetries(context.Background(), cmd, func() (*Result, error) {
+		return e.execute(cmd)
+	})
+}
+
+// execute runs a command once
+func (e *Executor) execute(cmd *Command) (*Result, error) {
 	if e.verbose {
 		color.Cyan("› %s", cmd.String())
 	}
 
 	// Use strategy pattern if enabled
 	if cmd.UseStrategy {
+		cmd = e.withGlobalEnv(cmd)
 		strategy := e.selector.Select(cmd)
 		return strategy.Execute(context.Background(), cmd)
 	}
@@ -46,9 +104,9 @@ func (e *Executor) Execute(cmd *Command) (*Result, error) {
 	start := time.Now()
 	switch cmd.Mode {
 	case ModePassthrough:
-		return e.executePassthrough(cmd, start)
+		return e.executePassthrough(context.Background(), cmd, start)
 	case ModeInteractive:
-		return e.executeInteractive(cmd, start)
+		return e.executeInteractive(context.Background(), cmd, start)
 	case ModeCapture:
 		return e.executeCapture(cmd, start)
 	case ModeBackground:
@@ -58,21 +116,53 @@ func (e *Executor) Execute(cmd *Command) (*Result, error) {
 	}
 }
 
-// ExecuteWithContext runs a command with a context for cancellation using strategy pattern
+// ExecuteWithContext runs a command with a context for cancellation using
+// strategy pattern, retrying failed runs as configured. Cancelling ctx also
+// stops further retries.
 func (e *Executor) ExecuteWithContext(ctx context.Context, cmd *Command) (*Result, error) {
+	return e.withRetries(ctx, cmd, func() (*Result, error) {
+		return e.executeWithContext(ctx, cmd)
+	})
+}
+
+// executeWithContext runs a command once with the strategy pattern
+func (e *Executor) executeWithContext(ctx context.Context, cmd *Command) (*Result, error) {
 	if e.verbose {
 		color.Cyan("› %s", cmd.String())
 	}
 
+	// Apply the executor's defaults to a copy, leaving the caller's command as is
+	cmd = e.withGlobalEnv(cmd)
+	if cmd.Timeout == 0 && cmd.Options.Timeout == 0 && e.options.DefaultTimeout > 0 {
+		withDefaults := *cmd
+		withDefaults.Timeout = e.options.DefaultTimeout
+		cmd = &withDefaults
+	}
+
+	// Interactive commands need the terminal rather than a strategy's pipes
+	if cmd.Mode == ModeInteractive || cmd.AllocateTTY {
+		return e.executeInteractive(ctx, cmd, time.Now())
+	}
+
 	// Always use strategy pattern when context is provided
 	cmd.UseStrategy = true
 	strategy := e.selector.Select(cmd)
 	return strategy.Execute(ctx, cmd)
 }
 
+// withGlobalEnv returns a copy of cmd carrying the executor's GlobalEnv for
+// strategies to apply, or cmd itself if there is none
+func (e *Executor) withGlobalEnv(cmd *Command) *Command {
+	if len(e.options.GlobalEnv) == 0 {
+		return cmd
+	}
+	withEnv := *cmd
+	withEnv.Options.GlobalEnv = e.options.GlobalEnv
+	return &withEnv
+}
+
 // executePassthrough runs a command with direct I/O passthrough
-func (e *Executor) executePassthrough(cmd *Command, start time.Time) (*Result, error) {
-	ctx := context.Background()
+func (e *Executor) executePassthrough(ctx context.Context, cmd *Command, start time.Time) (*Result, error) {
 	if cmd.Timeout > 0 {
 		var cancel context.CancelFunc
 		ctx, cancel = context.WithTimeout(ctx, cmd.Timeout)
@@ -87,11 +177,7 @@ func (e *Executor) executePassthrough(cmd *Command, start time.Time) (*Result, e
 	}
 
 	// Configure environment
-	if cmd.InheritEnv {
-		execCmd.Env = os.Environ()
-	}
-	execCmd.Env = append(execCmd.Env, e.options.GlobalEnv...)
-	execCmd.Env = append(execCmd.Env, cmd.Environment...)
+	execCmd.Env = buildEnv(cmd.InheritEnv, e.options.GlobalEnv, cmd.Environment)
 
 	// Direct I/O passthrough
 	execCmd.Stdin = os.Stdin
@@ -105,8 +191,9 @@ func (e *Executor) executePassthrough(cmd *Command, start time.Time) (*Result, e
 		defer cleanupSignals()
 	}
 
-	// Run the command
-	err := execCmd.Run()
+	// Run the command in its own process group so a timeout also stops
+	// the children it spawns
+	err := runProcessGroup(execCmd)
 
 	result := &Result{
 		Duration: time.Since(start),
@@ -115,7 +202,7 @@ func (e *Executor) executePassthrough(cmd *Command, start time.Time) (*Result, e
 	if err != nil {
 		if ctx.Err() == context.DeadlineExceeded {
 			result.Timeout = true
-			result.Error = fmt.Errorf("command timed out after %s", cmd.Timeout)
+			result.Error = fmt.Errorf("%w after %s", ErrTimeout, cmd.Timeout)
 			return result, nil
 		} else if exitError, ok := err.(*exec.ExitError); ok {
 			result.ExitCode = exitError.ExitCode()
@@ -128,11 +215,61 @@ func (e *Executor) executePassthrough(cmd *Command, start time.Time) (*Result, e
 	return result, nil
 }
 
-// executeInteractive runs a command with TTY allocation
-func (e *Executor) executeInteractive(cmd *Command, start time.Time) (*Result, error) {
-	// For interactive commands, we use passthrough with TTY settings
-	// This is simplified - full TTY support would require pty package
-	return e.executePassthrough(cmd, start)
+// executeInteractive runs a command on a pseudo-terminal proxied to the
+// user's terminal, so shells and editors behave as if run directly. The
+// command's stderr shares the terminal with its stdout. Commands without
+// AllocateTTY, runs without a terminal on stdin (e.g. in CI) and platforms
+// without PTY support fall back to passthrough.
+func (e *Executor) executeInteractive(ctx context.Context, cmd *Command, start time.Time) (*Result, error) {
+	stdin := os.Stdin
+	if f, ok := cmd.Stdin.(*os.File); ok {
+		stdin = f
+	}
+	if !cmd.AllocateTTY || !ptySupported || !term.IsTerminal(int(stdin.Fd())) {
+		return e.executePassthrough(ctx, cmd, start)
+	}
+
+	var stdout io.Writer = os.Stdout
+	if cmd.Stdout != nil {
+		stdout = cmd.Stdout
+	}
+
+	if cmd.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cmd.Timeout)
+		defer cancel()
+	}
+
+	execCmd := exec.CommandContext(ctx, cmd.Name, cmd.Args...)
+	if cmd.WorkingDir != "" {
+		execCmd.Dir = cmd.WorkingDir
+	}
+	execCmd.Env = buildEnv(cmd.InheritEnv, e.options.GlobalEnv, cmd.Environment)
+
+	// The command leads a new session on the pseudo-terminal, so a timeout
+	// can kill its whole process group
+	killGroupOnCancel(execCmd)
+
+	err := runWithPTY(execCmd, stdin, stdout)
+
+	result := &Result{
+		Duration: time.Since(start),
+	}
+
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			result.Timeout = true
+			result.Error = fmt.Errorf("%w after %s", ErrTimeout, cmd.Timeout)
+			return result, nil
+		} else if exitError, ok := err.(*exec.ExitError); ok {
+			result.ExitCode = exitError.ExitCode()
+		} else {
+			result.ExitCode = -1
+			result.Error = err
+		}
+	}
+
+	return result, nil
 }
 
 // executeCapture runs a command and captures output
@@ -152,11 +289,9 @@ func (e *Executor) executeCapture(cmd *Command, start time.Time) (*Result, error
 	}
 
 	// Configure environment
-	if cmd.InheritEnv {
-		execCmd.Env = os.Environ()
-	}
-	execCmd.Env = append(execCmd.Env, e.options.GlobalEnv...)
-	execCmd.Env = append(execCmd.Env, cmd.Environment...)
+	execCmd.Env = buildEnv(cmd.InheritEnv, e.options.GlobalEnv, cmd.Environment)
+
+	killProcessGroupOnCancel(execCmd)
 
 	// Capture output
 	var stdout, stderr bytes.Buffer
@@ -186,7 +321,7 @@ func (e *Executor) executeCapture(cmd *Command, start time.Time) (*Result, error
 	if err != nil {
 		if ctx.Err() == context.DeadlineExceeded {
 			result.Timeout = true
-			result.Error = fmt.Errorf("command timed out after %s", cmd.Timeout)
+			result.Error = fmt.Errorf("%w after %s", ErrTimeout, cmd.Timeout)
 			return result, nil
 		} else if exitError, ok := err.(*exec.ExitError); ok {
 			result.ExitCode = exitError.ExitCode()
@@ -209,11 +344,7 @@ func (e *Executor) executeBackground(cmd *Command, start time.Time) (*Result, er
 	}
 
 	// Configure environment
-	if cmd.InheritEnv {
-		execCmd.Env = os.Environ()
-	}
-	execCmd.Env = append(execCmd.Env, e.options.GlobalEnv...)
-	execCmd.Env = append(execCmd.Env, cmd.Environment...)
+	execCmd.Env = buildEnv(cmd.InheritEnv, e.options.GlobalEnv, cmd.Environment)
 
 	// Start the command
 	err := execCmd.Start()
@@ -262,27 +393,23 @@ func (e *Executor) Run(name string, args ...string) error {
 	if err != nil {
 		return err
 	}
-	if result.Error != nil {
-		return result.Error
-	}
-	if result.ExitCode != 0 {
-		return fmt.Errorf("command failed with exit code %d", result.ExitCode)
-	}
-	return nil
+	return ResultError(cmd, result)
 }
 
-// RunCapture runs a command and returns captured output
+// RunCapture runs a command and returns captured output. If the command
+// exits non-zero, the output is its stderr.
 func (e *Executor) RunCapture(name string, args ...string) (string, error) {
 	cmd := NewCommand(name, args...)
 	result, err := e.Execute(cmd)
 	if err != nil {
 		return "", err
 	}
-	if result.Error != nil {
-		return "", result.Error
-	}
-	if result.ExitCode != 0 {
-		return string(result.Stderr), fmt.Errorf("command failed with exit code %d", result.ExitCode)
+	if err := ResultError(cmd, result); err != nil {
+		var exitErr *ExitError
+		if errors.As(err, &exitErr) {
+			return string(result.Stderr), err
+		}
+		return "", err
 	}
 	return string(result.Stdout), nil
 }
@@ -294,14 +421,5 @@ func (e *Executor) RunWithTimeout(timeout time.Duration, name string, args ...st
 	if err != nil {
 		return err
 	}
-	if result.Timeout {
-		return fmt.Errorf("command timed out after %s", timeout)
-	}
-	if result.Error != nil {
-		return result.Error
-	}
-	if result.ExitCode != 0 {
-		return fmt.Errorf("command failed with exit code %d", result.ExitCode)
-	}
-	return nil
+	return ResultError(cmd, result)
 }
@@ -0,0 +1,91 @@
+//go:build !windows
+
+package shell
+
+import (
+	"os"
+	"os/exec"
+	"os/signal"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+	"golang.org/x/term"
+)
+
+// killProcessGroupOnCancel runs the command in its own process group and
+// makes context cancellation kill the whole group, so shells and the
+// children they spawn don't outlive a timeout.
+//
+// Commands reading from a terminal are left in the foreground process group;
+// moving them would stop them with SIGTTIN on their first read. Use
+// runProcessGroup for those.
+func killProcessGroupOnCancel(execCmd *exec.Cmd) {
+	if f, ok := execCmd.Stdin.(*os.File); ok && term.IsTerminal(int(f.Fd())) {
+		return
+	}
+
+	if execCmd.SysProcAttr == nil {
+		execCmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	execCmd.SysProcAttr.Setpgid = true
+	killGroupOnCancel(execCmd)
+}
+
+// killGroupOnCancel makes context cancellation kill the process group led by
+// the command, which must be started as a group or session leader
+func killGroupOnCancel(execCmd *exec.Cmd) {
+	execCmd.Cancel = func() error {
+		// The group shares the leader's pid; a negative pid signals the group
+		return syscall.Kill(-execCmd.Process.Pid, syscall.SIGKILL)
+	}
+	execCmd.WaitDelay = processWaitDelay
+}
+
+// runProcessGroup runs the command in its own process group that context
+// cancellation kills as a whole. If stdin is the terminal we are in the
+// foreground of, the group becomes the terminal's foreground group while it
+// runs, so it can read from the terminal and receives keys like Ctrl+C, and
+// the terminal is handed back once it exits.
+func runProcessGroup(execCmd *exec.Cmd) error {
+	if execCmd.SysProcAttr == nil {
+		execCmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	execCmd.SysProcAttr.Setpgid = true
+	killGroupOnCancel(execCmd)
+
+	tty, ok := foregroundTerminal(execCmd.Stdin)
+	if !ok {
+		return execCmd.Run()
+	}
+
+	execCmd.SysProcAttr.Foreground = true
+	execCmd.SysProcAttr.Ctty = tty
+	defer reclaimTerminal(tty)
+	return execCmd.Run()
+}
+
+// foregroundTerminal returns the descriptor of stdin if it is a terminal
+// whose foreground process group is ours
+func foregroundTerminal(stdin interface{}) (int, bool) {
+	f, ok := stdin.(*os.File)
+	if !ok || !term.IsTerminal(int(f.Fd())) {
+		return 0, false
+	}
+	fd := int(f.Fd())
+	pgrp, err := unix.IoctlGetInt(fd, unix.TIOCGPGRP)
+	if err != nil || pgrp != syscall.Getpgrp() {
+		return 0, false
+	}
+	return fd, true
+}
+
+// reclaimTerminal makes our process group the terminal's foreground group
+// again. A background group changing it is sent SIGTTOU, which is ignored
+// meanwhile.
+func reclaimTerminal(tty int) {
+	if !signal.Ignored(syscall.SIGTTOU) {
+		signal.Ignore(syscall.SIGTTOU)
+		defer signal.Reset(syscall.SIGTTOU)
+	}
+	_ = unix.IoctlSetPointerInt(tty, unix.TIOCSPGRP, syscall.Getpgrp())
+}
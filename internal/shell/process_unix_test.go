@@ -0,0 +1,89 @@
+//go:build !windows
+
+package shell
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// processGone reports whether pid has exited; zombies count as exited since
+// the test process may not be the one reaping them
+func processGone(pid int) bool {
+	if err := syscall.Kill(pid, 0); errors.Is(err, syscall.ESRCH) {
+		return true
+	}
+	stat, err := os.ReadFile("/proc/" + strconv.Itoa(pid) + "/stat")
+	if err != nil {
+		return false
+	}
+	fields := strings.Fields(string(stat))
+	return len(fields) > 2 && fields[2] == "Z"
+}
+
+func TestExecutor_TimeoutKillsProcessGroup(t *testing.T) {
+	pidFile := t.TempDir() + "/child.pid"
+	script := "sleep 30 & echo $! > " + pidFile + "; wait"
+
+	tests := []struct {
+		name string
+		run  func(cmd *Command) (*Result, error)
+	}{
+		{"ExecuteWithContext", func(cmd *Command) (*Result, error) {
+			return NewExecutor(Options{}).ExecuteWithContext(context.Background(), cmd)
+		}},
+		{"DefaultTimeout", func(cmd *Command) (*Result, error) {
+			cmd.Timeout = 0
+			return NewExecutor(Options{DefaultTimeout: 200 * time.Millisecond}).ExecuteWithContext(context.Background(), cmd)
+		}},
+		{"Execute capture", func(cmd *Command) (*Result, error) {
+			return NewExecutor(Options{}).Execute(cmd)
+		}},
+		{"Execute passthrough", func(cmd *Command) (*Result, error) {
+			cmd.Mode = ModePassthrough
+			return NewExecutor(Options{}).Execute(cmd)
+		}},
+		{"ExecuteWithContext interactive", func(cmd *Command) (*Result, error) {
+			cmd.Mode = ModeInteractive
+			return NewExecutor(Options{}).ExecuteWithContext(context.Background(), cmd)
+		}},
+		{"Execute interactive PTY", func(cmd *Command) (*Result, error) {
+			cmd.Mode = ModeInteractive
+			cmd.AllocateTTY = true
+			cmd.Stdin = userTerminal(t, 24, 80)
+			cmd.Stdout = &bytes.Buffer{}
+			return NewExecutor(Options{}).Execute(cmd)
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := NewCommand("sh", "-c", script).WithTimeout(200 * time.Millisecond)
+
+			start := time.Now()
+			result, err := tt.run(cmd)
+			require.NoError(t, err)
+			assert.Less(t, time.Since(start), 5*time.Second)
+
+			assert.True(t, result.Timeout)
+			assert.ErrorIs(t, result.Error, ErrTimeout)
+
+			data, err := os.ReadFile(pidFile)
+			require.NoError(t, err)
+			pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+			require.NoError(t, err)
+			assert.Eventually(t, func() bool { return processGone(pid) }, 2*time.Second, 20*time.Millisecond,
+				"background child %d should be killed with its shell", pid)
+		})
+	}
+}
@@ -0,0 +1,24 @@
+//go:build windows
+
+package shell
+
+import "os/exec"
+
+// killProcessGroupOnCancel only bounds how long output is awaited after
+// cancellation on Windows, where exec kills just the direct child
+func killProcessGroupOnCancel(execCmd *exec.Cmd) {
+	execCmd.WaitDelay = processWaitDelay
+}
+
+// killGroupOnCancel only bounds how long output is awaited after
+// cancellation on Windows, where exec kills just the direct child
+func killGroupOnCancel(execCmd *exec.Cmd) {
+	execCmd.WaitDelay = processWaitDelay
+}
+
+// runProcessGroup runs the command; on Windows cancellation kills just the
+// direct child
+func runProcessGroup(execCmd *exec.Cmd) error {
+	execCmd.WaitDelay = processWaitDelay
+	return execCmd.Run()
+}
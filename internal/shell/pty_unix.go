@@ -0,0 +1,112 @@
+//go:build !windows
+
+package shell
+
+import (
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"syscall"
+
+	"github.com/creack/pty"
+	"golang.org/x/sys/unix"
+	"golang.org/x/term"
+)
+
+// ptySupported reports whether interactive commands get a pseudo-terminal
+const ptySupported = true
+
+// runWithPTY runs execCmd on a new pseudo-terminal, copying the user's
+// terminal in to it and its output to out until the command exits. in is put
+// in raw mode meanwhile, so keys like Ctrl+C reach the command, and window
+// size changes (SIGWINCH) are forwarded.
+func runWithPTY(execCmd *exec.Cmd, in *os.File, out io.Writer) error {
+	// Start at the terminal's size so the command never sees an unsized one
+	size, err := pty.GetsizeFull(in)
+	if err != nil {
+		size = nil
+	}
+	ptmx, err := pty.StartWithSize(execCmd, size)
+	if err != nil {
+		return err
+	}
+	defer ptmx.Close()
+
+	resize := make(chan os.Signal, 1)
+	resized := make(chan struct{})
+	signal.Notify(resize, syscall.SIGWINCH)
+	go func() {
+		defer close(resized)
+		for range resize {
+			_ = pty.InheritSize(in, ptmx)
+		}
+	}()
+	// Stop resizing before the deferred Close of ptmx
+	defer func() {
+		signal.Stop(resize)
+		close(resize)
+		<-resized
+	}()
+
+	if state, err := term.MakeRaw(int(in.Fd())); err == nil {
+		defer func() { _ = term.Restore(int(in.Fd()), state) }()
+	}
+
+	// Stop copying from the terminal once the command is done, so no
+	// keystrokes meant for whatever runs next are swallowed
+	stopR, stopW, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+	defer stopR.Close()
+	copied := make(chan struct{})
+	go func() {
+		defer close(copied)
+		_ = copyInput(ptmx, in, stopR)
+	}()
+
+	// Ends with EIO once the command and its children close the terminal
+	_, _ = io.Copy(out, ptmx)
+
+	err = execCmd.Wait()
+	stopW.Close()
+	<-copied
+	return err
+}
+
+// copyInput copies from in to dst until in reaches EOF or stop becomes
+// readable (its write end is closed). It waits for input with select rather
+// than a blocking read so that it can be stopped.
+func copyInput(dst io.Writer, in, stop *os.File) error {
+	inFd, stopFd := int(in.Fd()), int(stop.Fd())
+	buf := make([]byte, 32*1024)
+	for {
+		var fds unix.FdSet
+		fds.Set(inFd)
+		fds.Set(stopFd)
+		if _, err := unix.Select(max(inFd, stopFd)+1, &fds, nil, nil, nil); err != nil {
+			if errors.Is(err, unix.EINTR) {
+				continue
+			}
+			return err
+		}
+		if fds.IsSet(stopFd) {
+			return nil
+		}
+
+		n, err := in.Read(buf)
+		if n > 0 {
+			if _, werr := dst.Write(buf[:n]); werr != nil {
+				return werr
+			}
+		}
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+	}
+}
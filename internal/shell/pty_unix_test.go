@@ -0,0 +1,119 @@
+//go:build !windows
+
+package shell
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/creack/pty"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/term"
+)
+
+// userTerminal opens a pseudo-terminal standing in for the user's terminal
+func userTerminal(t *testing.T, rows, cols uint16) *os.File {
+	t.Helper()
+	master, tty, err := pty.Open()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		tty.Close()
+		master.Close()
+	})
+	require.NoError(t, pty.Setsize(tty, &pty.Winsize{Rows: rows, Cols: cols}))
+	return tty
+}
+
+func TestExecutor_InteractivePTY(t *testing.T) {
+	t.Run("command runs on a terminal", func(t *testing.T) {
+		tty := userTerminal(t, 33, 101)
+		before, err := term.GetState(int(tty.Fd()))
+		require.NoError(t, err)
+
+		var out bytes.Buffer
+		cmd := NewInteractiveCommand("sh", "-c", `test -t 0 && test -t 1 && echo "tty $(stty size)"; exit 4`)
+		cmd.Stdin = tty
+		cmd.Stdout = &out
+
+		result, err := NewExecutor(Options{}).Execute(cmd)
+		require.NoError(t, err)
+		assert.Equal(t, 4, result.ExitCode)
+		assert.Contains(t, out.String(), "tty 33 101")
+
+		after, err := term.GetState(int(tty.Fd()))
+		require.NoError(t, err)
+		assert.Equal(t, before, after, "terminal mode should be restored")
+	})
+
+	t.Run("window size changes are forwarded", func(t *testing.T) {
+		tty := userTerminal(t, 24, 80)
+
+		var out bytes.Buffer
+		cmd := NewInteractiveCommand("sh", "-c", `sleep 0.5; stty size`)
+		cmd.Stdin = tty
+		cmd.Stdout = &out
+
+		resized := make(chan struct{})
+		go func() {
+			defer close(resized)
+			time.Sleep(100 * time.Millisecond)
+			_ = pty.Setsize(tty, &pty.Winsize{Rows: 50, Cols: 132})
+			_ = syscall.Kill(os.Getpid(), syscall.SIGWINCH)
+		}()
+
+		result, err := NewExecutor(Options{}).ExecuteWithContext(context.Background(), cmd)
+		<-resized
+		require.NoError(t, err)
+		assert.Equal(t, 0, result.ExitCode)
+		assert.Contains(t, out.String(), "50 132")
+	})
+
+	t.Run("input after exit is left for the next reader", func(t *testing.T) {
+		master, tty, err := pty.Open()
+		require.NoError(t, err)
+		defer master.Close()
+		defer tty.Close()
+
+		cmd := NewInteractiveCommand("true")
+		cmd.Stdin = tty
+		cmd.Stdout = &bytes.Buffer{}
+
+		_, err = NewExecutor(Options{}).Execute(cmd)
+		require.NoError(t, err)
+
+		_, err = master.Write([]byte("next\n"))
+		require.NoError(t, err)
+
+		line := make(chan string, 1)
+		go func() {
+			buf := make([]byte, 64)
+			n, _ := tty.Read(buf)
+			line <- string(buf[:n])
+		}()
+		select {
+		case got := <-line:
+			assert.Equal(t, "next\n", got)
+		case <-time.After(2 * time.Second):
+			t.Fatal("input was consumed by the finished command")
+		}
+	})
+
+	t.Run("falls back to passthrough without a terminal", func(t *testing.T) {
+		r, w, err := os.Pipe()
+		require.NoError(t, err)
+		w.Close()
+		defer r.Close()
+
+		cmd := NewInteractiveCommand("sh", "-c", "exit 2")
+		cmd.Stdin = r
+
+		result, err := NewExecutor(Options{}).Execute(cmd)
+		require.NoError(t, err)
+		assert.Equal(t, 2, result.ExitCode)
+	})
+}
@@ -0,0 +1,18 @@
+//go:build windows
+
+package shell
+
+import (
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+)
+
+// ptySupported reports whether interactive commands get a pseudo-terminal;
+// on Windows they run with passthrough I/O instead
+const ptySupported = false
+
+func runWithPTY(execCmd *exec.Cmd, in *os.File, out io.Writer) error {
+	return errors.New("pseudo-terminals are not supported on Windows")
+}
@@ -0,0 +1,75 @@
+package shell
+
+import (
+	"context"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+// DefaultRetryBackoff is the wait before the first retry when neither the
+// command nor the executor sets RetryBackoff
+const DefaultRetryBackoff = 500 * time.Millisecond
+
+// MaxRetryBackoff caps the wait between retries however many have been made
+const MaxRetryBackoff = 30 * time.Second
+
+// withRetries calls run until it succeeds, the command's retries are used
+// up, or ctx is cancelled, and returns the last result. Retries wait with
+// exponential backoff, capped at MaxRetryBackoff.
+//
+// Retried commands run again from scratch, so they should not read from a
+// one-shot Stdin.
+func (e *Executor) withRetries(ctx context.Context, cmd *Command, run func() (*Result, error)) (*Result, error) {
+	retries := cmd.Retries
+	if retries == 0 {
+		retries = e.options.Retries
+	}
+	backoff := cmd.RetryBackoff
+	if backoff == 0 {
+		backoff = e.options.RetryBackoff
+	}
+	if backoff == 0 {
+		backoff = DefaultRetryBackoff
+	}
+	retryIf := cmd.RetryIf
+	if retryIf == nil {
+		retryIf = failedWithExitCode
+	}
+
+	for attempt := 0; ; attempt++ {
+		result, err := run()
+		if err != nil || result == nil || attempt >= retries || cmd.Mode == ModeBackground || !retryIf(result) {
+			return result, err
+		}
+
+		wait := retryDelay(backoff, attempt)
+		if e.verbose {
+			color.Yellow("› retrying in %s (%d/%d): %s", wait, attempt+1, retries, cmd.String())
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return result, err
+		case <-timer.C:
+		}
+	}
+}
+
+// retryDelay returns the wait before retry number attempt+1: backoff
+// doubled once per earlier attempt, capped at MaxRetryBackoff
+func retryDelay(backoff time.Duration, attempt int) time.Duration {
+	wait := backoff
+	for i := 0; i < attempt && wait < MaxRetryBackoff; i++ {
+		wait *= 2
+	}
+	return min(wait, MaxRetryBackoff)
+}
+
+// failedWithExitCode is the default retry condition: the command ran and
+// exited non-zero
+func failedWithExitCode(result *Result) bool {
+	return result.ExitCode > 0
+}
@@ -0,0 +1,103 @@
+package shell
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// flakyCommand returns a command that fails with exit code 3 until its
+// succeedOn-th run, and the file counting its runs
+func flakyCommand(t *testing.T, succeedOn int) (*Command, string) {
+	t.Helper()
+	counter := filepath.Join(t.TempDir(), "runs")
+	script := `n=$(cat "$1" 2>/dev/null || echo 0); n=$((n+1)); echo $n > "$1"; echo "run $n"; [ $n -ge ` +
+		strconv.Itoa(succeedOn) + ` ] || exit 3`
+	return NewCommand("sh", "-c", script, "sh", counter), counter
+}
+
+func runCount(t *testing.T, counter string) string {
+	t.Helper()
+	data, err := os.ReadFile(counter)
+	require.NoError(t, err)
+	return strings.TrimSpace(string(data))
+}
+
+func TestExecutor_Retries(t *testing.T) {
+	t.Run("retries until success", func(t *testing.T) {
+		cmd, counter := flakyCommand(t, 3)
+		cmd.Retries = 5
+		cmd.RetryBackoff = time.Millisecond
+
+		result, err := NewExecutor(Options{}).Execute(cmd)
+		require.NoError(t, err)
+		assert.Equal(t, 0, result.ExitCode)
+		assert.Equal(t, "run 3\n", string(result.Stdout))
+		assert.Equal(t, "3", runCount(t, counter))
+	})
+
+	t.Run("returns the last result when retries run out", func(t *testing.T) {
+		cmd, counter := flakyCommand(t, 9)
+		cmd.CaptureOutput = true
+
+		executor := NewExecutor(Options{Retries: 2, RetryBackoff: time.Millisecond})
+		result, err := executor.ExecuteWithContext(context.Background(), cmd)
+		require.NoError(t, err)
+		assert.Equal(t, 3, result.ExitCode)
+		assert.Equal(t, "run 3\n", string(result.Stdout))
+		assert.Equal(t, "3", runCount(t, counter))
+	})
+
+	t.Run("RetryIf limits retries to transient failures", func(t *testing.T) {
+		cmd, counter := flakyCommand(t, 3)
+		cmd.Retries = 5
+		cmd.RetryBackoff = time.Millisecond
+		cmd.RetryIf = func(result *Result) bool { return result.ExitCode == 75 }
+
+		result, err := NewExecutor(Options{}).Execute(cmd)
+		require.NoError(t, err)
+		assert.Equal(t, 3, result.ExitCode)
+		assert.Equal(t, "1", runCount(t, counter))
+	})
+
+	t.Run("no retries by default", func(t *testing.T) {
+		cmd, counter := flakyCommand(t, 3)
+
+		result, err := NewExecutor(Options{}).Execute(cmd)
+		require.NoError(t, err)
+		assert.Equal(t, 3, result.ExitCode)
+		assert.Equal(t, "1", runCount(t, counter))
+	})
+
+	t.Run("cancellation stops retrying", func(t *testing.T) {
+		cmd, counter := flakyCommand(t, 9)
+		cmd.CaptureOutput = true
+		cmd.Retries = 5
+		cmd.RetryBackoff = time.Minute
+
+		ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		result, err := NewExecutor(Options{}).ExecuteWithContext(ctx, cmd)
+		require.NoError(t, err)
+		assert.Less(t, time.Since(start), 5*time.Second)
+		assert.Equal(t, 3, result.ExitCode)
+		assert.Equal(t, "1", runCount(t, counter))
+	})
+}
+
+func TestRetryDelay(t *testing.T) {
+	assert.Equal(t, 100*time.Millisecond, retryDelay(100*time.Millisecond, 0))
+	assert.Equal(t, 400*time.Millisecond, retryDelay(100*time.Millisecond, 2))
+	assert.Equal(t, MaxRetryBackoff, retryDelay(100*time.Millisecond, 20))
+	assert.Equal(t, MaxRetryBackoff, retryDelay(time.Second, 100), "large attempt counts must not overflow")
+	assert.Equal(t, MaxRetryBackoff, retryDelay(time.Minute, 0))
+}
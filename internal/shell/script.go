@@ -0,0 +1,91 @@
+package shell
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+// DefaultShell returns the shell used to run script strings on this OS,
+// including the flag that takes the script: "cmd /C" on Windows, "sh -c"
+// elsewhere.
+func DefaultShell() []string {
+	return defaultShellFor(runtime.GOOS)
+}
+
+// defaultShellFor returns the default shell for goos
+func defaultShellFor(goos string) []string {
+	if goos == "windows" {
+		return []string{"cmd", "/C"}
+	}
+	return []string{"sh", "-c"}
+}
+
+// ShellArgs returns the argv prefix for running a script with the named
+// interpreter, adding the flag that interpreter expects before the script:
+// /C for cmd, -Command for PowerShell and -c for everything else.
+// If name already includes arguments (e.g. "bash -eu") they are kept.
+func ShellArgs(name string) []string {
+	fields := strings.Fields(name)
+	if len(fields) == 0 {
+		return DefaultShell()
+	}
+
+	switch interpreterName(fields) {
+	case "cmd":
+		return append(fields, "/C")
+	case "powershell", "pwsh":
+		return append(fields, "-Command")
+	default:
+		return append(fields, "-c")
+	}
+}
+
+// interpreterName returns the lower-cased program name of an interpreter
+// command line, looking through "env" as used in #! lines
+func interpreterName(fields []string) string {
+	// Accept both separators so Windows paths resolve on any OS
+	name := func(s string) string {
+		if i := strings.LastIndexAny(s, `/\`); i >= 0 {
+			s = s[i+1:]
+		}
+		return strings.TrimSuffix(strings.ToLower(s), ".exe")
+	}
+
+	program := name(fields[0])
+	if program == "env" {
+		for _, f := range fields[1:] {
+			if !strings.HasPrefix(f, "-") {
+				return name(f)
+			}
+		}
+	}
+	return program
+}
+
+// NewScriptCommand creates a passthrough command that runs script with the
+// executor's shell (Options.Shell, or DefaultShell if unset)
+func (e *Executor) NewScriptCommand(script string) *Command {
+	shell := e.options.Shell
+	if len(shell) == 0 {
+		shell = DefaultShell()
+	}
+
+	args := append(append([]string{}, shell[1:]...), script)
+	return NewPassthroughCommand(shell[0], args...)
+}
+
+// RunScript runs a script string with the executor's shell
+func (e *Executor) RunScript(script string) error {
+	result, err := e.Execute(e.NewScriptCommand(script))
+	if err != nil {
+		return err
+	}
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.ExitCode != 0 {
+		return fmt.Errorf("command failed with exit code %d", result.ExitCode)
+	}
+	return nil
+}
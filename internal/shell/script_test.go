@@ -0,0 +1,71 @@
+package shell
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultShell(t *testing.T) {
+	switch runtime.GOOS {
+	case "windows":
+		assert.Equal(t, []string{"cmd", "/C"}, DefaultShell())
+	default:
+		assert.Equal(t, []string{"sh", "-c"}, DefaultShell())
+	}
+
+	assert.Equal(t, []string{"cmd", "/C"}, defaultShellFor("windows"))
+	assert.Equal(t, []string{"sh", "-c"}, defaultShellFor("linux"))
+	assert.Equal(t, []string{"sh", "-c"}, defaultShellFor("darwin"))
+}
+
+func TestShellArgs(t *testing.T) {
+	tests := []struct {
+		name string
+		want []string
+	}{
+		{"", DefaultShell()},
+		{"bash", []string{"bash", "-c"}},
+		{"bash -eu", []string{"bash", "-eu", "-c"}},
+		{"/usr/bin/env bash", []string{"/usr/bin/env", "bash", "-c"}},
+		{"cmd", []string{"cmd", "/C"}},
+		{"CMD.EXE", []string{"CMD.EXE", "/C"}},
+		{"powershell", []string{"powershell", "-Command"}},
+		{"pwsh -NoProfile", []string{"pwsh", "-NoProfile", "-Command"}},
+		{`C:\Windows\System32\WindowsPowerShell\v1.0\powershell.exe`, []string{`C:\Windows\System32\WindowsPowerShell\v1.0\powershell.exe`, "-Command"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ShellArgs(tt.name))
+		})
+	}
+}
+
+func TestExecutor_NewScriptCommand(t *testing.T) {
+	t.Run("default shell", func(t *testing.T) {
+		cmd := NewExecutor(Options{}).NewScriptCommand("echo hi")
+		shell := DefaultShell()
+		assert.Equal(t, shell[0], cmd.Name)
+		assert.Equal(t, append(shell[1:], "echo hi"), cmd.Args)
+		assert.Equal(t, ModePassthrough, cmd.Mode)
+	})
+
+	t.Run("configured shell", func(t *testing.T) {
+		cmd := NewExecutor(Options{Shell: []string{"powershell", "-Command"}}).NewScriptCommand("Get-Date")
+		assert.Equal(t, "powershell", cmd.Name)
+		assert.Equal(t, []string{"-Command", "Get-Date"}, cmd.Args)
+	})
+}
+
+func TestExecutor_RunScript(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("POSIX shell scripts")
+	}
+
+	e := NewExecutor(Options{})
+	require.NoError(t, e.RunScript("for i in 1 2; do :; done"))
+	assert.Error(t, e.RunScript("exit 3"))
+}
@@ -2,8 +2,12 @@ package shell
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	// "io"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -184,6 +188,57 @@ func TestExecutor_Execute(t *testing.T) {
 	})
 }
 
+func TestExecutor_WorkingDir(t *testing.T) {
+	dir, err := filepath.EvalSymlinks(t.TempDir())
+	require.NoError(t, err)
+
+	executor := NewExecutor(Options{})
+
+	t.Run("capture mode", func(t *testing.T) {
+		result, err := executor.Execute(NewCommand("pwd").WithWorkingDir(dir))
+		require.NoError(t, err)
+		assert.Equal(t, dir, strings.TrimSpace(string(result.Stdout)))
+	})
+
+	t.Run("with context", func(t *testing.T) {
+		cmd := NewCommand("pwd").WithWorkingDir(dir)
+		cmd.CaptureOutput = true
+		result, err := executor.ExecuteWithContext(context.Background(), cmd)
+		require.NoError(t, err)
+		assert.Equal(t, dir, strings.TrimSpace(string(result.Stdout)))
+	})
+
+	t.Run("streaming", func(t *testing.T) {
+		var lines []string
+		_, err := executor.ExecuteStream(NewCommand("pwd").WithWorkingDir(dir), func(line []byte) {
+			lines = append(lines, string(line))
+		}, nil)
+		require.NoError(t, err)
+		assert.Equal(t, []string{dir}, lines)
+	})
+}
+
+func TestExecutor_RunExitCodes(t *testing.T) {
+	executor := NewExecutor(Options{})
+
+	err := executor.Run("sh", "-c", "exit 3")
+	var exitErr *ExitError
+	require.ErrorAs(t, err, &exitErr)
+	assert.Equal(t, 3, exitErr.Code())
+	assert.EqualError(t, err, "command failed with exit code 3")
+
+	output, err := executor.RunCapture("sh", "-c", "echo broken >&2; exit 4")
+	require.ErrorAs(t, err, &exitErr)
+	assert.Equal(t, 4, exitErr.Code())
+	assert.Equal(t, "broken\n", output)
+
+	err = executor.RunWithTimeout(50*time.Millisecond, "sleep", "1")
+	assert.ErrorIs(t, err, ErrTimeout)
+	assert.False(t, errors.As(err, &exitErr))
+
+	assert.NoError(t, executor.Run("true"))
+}
+
 func TestResult_ExitCode(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -0,0 +1,198 @@
+// Package shelltest provides a scriptable shell.CommandExecutor for testing
+// code that runs external commands, without the commands being installed.
+package shelltest
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/glide-cli/glide/v3/internal/shell"
+	"github.com/stretchr/testify/assert"
+)
+
+// MockExecutor records the commands it is asked to run and answers them with
+// scripted results instead of running them. Streaming through
+// shell.ExecuteStream replays the scripted output.
+//
+// Example:
+//
+//	executor := shelltest.NewMockExecutor()
+//	executor.On("docker", "compose", "ps").Return(&shell.Result{ExitCode: 1}, nil)
+//	// ... run code using executor ...
+//	executor.AssertCalled(t, "docker", "compose", "up", "-d")
+type MockExecutor struct {
+	// DefaultResult answers commands without a matching response. A nil
+	// DefaultResult answers with a successful empty result.
+	DefaultResult *shell.Result
+
+	mu        sync.Mutex
+	responses []*MockResponse
+	calls     []shell.Command
+}
+
+// MockResponse is a scripted answer to matching commands
+type MockResponse struct {
+	name    string
+	args    []string
+	anyArgs bool
+	result  *shell.Result
+	err     error
+}
+
+// Ensure MockExecutor satisfies CommandExecutor
+var _ shell.CommandExecutor = (*MockExecutor)(nil)
+
+// NewMockExecutor creates a mock executor without scripted responses
+func NewMockExecutor() *MockExecutor {
+	return &MockExecutor{}
+}
+
+// On scripts the response to running name with exactly args. Responses are
+// matched in the order they were added.
+func (m *MockExecutor) On(name string, args ...string) *MockResponse {
+	return m.addResponse(&MockResponse{name: name, args: args})
+}
+
+// OnAnyArgs scripts the response to running name with any arguments
+func (m *MockExecutor) OnAnyArgs(name string) *MockResponse {
+	return m.addResponse(&MockResponse{name: name, anyArgs: true})
+}
+
+func (m *MockExecutor) addResponse(response *MockResponse) *MockResponse {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.responses = append(m.responses, response)
+	return response
+}
+
+// Return sets the result and error returned for matching commands
+func (r *MockResponse) Return(result *shell.Result, err error) {
+	r.result = result
+	r.err = err
+}
+
+func (r *MockResponse) matches(cmd *shell.Command) bool {
+	if cmd.Name != r.name {
+		return false
+	}
+	return r.anyArgs || equalArgs(cmd.Args, r.args)
+}
+
+// Execute records cmd and returns its scripted response
+func (m *MockExecutor) Execute(cmd *shell.Command) (*shell.Result, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	recorded := *cmd
+	recorded.Args = append([]string(nil), cmd.Args...)
+	recorded.Environment = append([]string(nil), cmd.Environment...)
+	m.calls = append(m.calls, recorded)
+
+	for _, response := range m.responses {
+		if response.matches(cmd) {
+			return copyResult(response.result), response.err
+		}
+	}
+	if m.DefaultResult != nil {
+		return copyResult(m.DefaultResult), nil
+	}
+	return &shell.Result{}, nil
+}
+
+// ExecuteWithContext records cmd and returns its scripted response
+func (m *MockExecutor) ExecuteWithContext(ctx context.Context, cmd *shell.Command) (*shell.Result, error) {
+	return m.Execute(cmd)
+}
+
+// Calls returns the commands run so far, in order
+func (m *MockExecutor) Calls() []shell.Command {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]shell.Command(nil), m.calls...)
+}
+
+// CallCount returns how often name was run with exactly args
+func (m *MockExecutor) CallCount(name string, args ...string) int {
+	count := 0
+	for _, call := range m.Calls() {
+		if call.Name == name && equalArgs(call.Args, args) {
+			count++
+		}
+	}
+	return count
+}
+
+// Reset forgets the recorded commands, keeping the scripted responses
+func (m *MockExecutor) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls = nil
+}
+
+// AssertCalled checks that name was run with exactly args at least once
+func (m *MockExecutor) AssertCalled(t testing.TB, name string, args ...string) bool {
+	t.Helper()
+	return assert.True(t, m.CallCount(name, args...) > 0,
+		"expected %q to be run, got:\n%s", commandLine(name, args), m.callList())
+}
+
+// AssertNotCalled checks that name was never run with exactly args
+func (m *MockExecutor) AssertNotCalled(t testing.TB, name string, args ...string) bool {
+	t.Helper()
+	return assert.Zero(t, m.CallCount(name, args...),
+		"expected %q not to be run, got:\n%s", commandLine(name, args), m.callList())
+}
+
+// AssertCallCount checks that name was run with exactly args count times
+func (m *MockExecutor) AssertCallCount(t testing.TB, count int, name string, args ...string) bool {
+	t.Helper()
+	return assert.Equal(t, count, m.CallCount(name, args...),
+		"unexpected number of runs of %q, got:\n%s", commandLine(name, args), m.callList())
+}
+
+// AssertNumberOfCalls checks how many commands were run in total
+func (m *MockExecutor) AssertNumberOfCalls(t testing.TB, count int) bool {
+	t.Helper()
+	return assert.Len(t, m.Calls(), count, "unexpected number of commands, got:\n%s", m.callList())
+}
+
+// callList renders the recorded commands for failure messages
+func (m *MockExecutor) callList() string {
+	calls := m.Calls()
+	if len(calls) == 0 {
+		return "  (no commands)"
+	}
+	lines := make([]string, len(calls))
+	for i, call := range calls {
+		lines[i] = "  " + commandLine(call.Name, call.Args)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func commandLine(name string, args []string) string {
+	return strings.TrimSpace(name + " " + shell.JoinArgs(args))
+}
+
+func equalArgs(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// copyResult keeps callers from modifying a scripted result shared by
+// several calls
+func copyResult(result *shell.Result) *shell.Result {
+	if result == nil {
+		return nil
+	}
+	copied := *result
+	return &copied
+}
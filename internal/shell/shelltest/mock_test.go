@@ -0,0 +1,64 @@
+package shelltest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/glide-cli/glide/v3/internal/shell"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMockExecutor(t *testing.T) {
+	executor := NewMockExecutor()
+	executor.On("docker", "compose", "ps").Return(&shell.Result{Stdout: []byte("web\ndb\n")}, nil)
+	executor.OnAnyArgs("docker").Return(&shell.Result{ExitCode: 1}, nil)
+	executor.On("git", "fetch").Return(nil, errors.New("offline"))
+
+	result, err := executor.Execute(shell.NewCommand("docker", "compose", "ps"))
+	require.NoError(t, err)
+	assert.Equal(t, "web\ndb\n", string(result.Stdout))
+
+	result, err = executor.ExecuteWithContext(context.Background(), shell.NewCommand("docker", "compose", "up", "-d"))
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.ExitCode)
+
+	_, err = executor.Execute(shell.NewCommand("git", "fetch"))
+	assert.EqualError(t, err, "offline")
+
+	result, err = executor.Execute(shell.NewCommand("npm", "test"))
+	require.NoError(t, err)
+	assert.Equal(t, 0, result.ExitCode, "unscripted commands succeed")
+
+	executor.AssertCalled(t, "docker", "compose", "up", "-d")
+	executor.AssertNotCalled(t, "docker", "compose", "down")
+	executor.AssertCallCount(t, 1, "docker", "compose", "ps")
+	executor.AssertNumberOfCalls(t, 4)
+	assert.Equal(t, "npm", executor.Calls()[3].Name)
+
+	executor.Reset()
+	executor.AssertNumberOfCalls(t, 0)
+}
+
+func TestMockExecutor_Stream(t *testing.T) {
+	executor := NewMockExecutor()
+	executor.DefaultResult = &shell.Result{Stdout: []byte("pulling\ndone\n")}
+
+	var lines []string
+	_, err := shell.ExecuteStream(executor, shell.NewCommand("docker", "pull", "nginx"), func(line []byte) {
+		lines = append(lines, string(line))
+	}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"pulling", "done"}, lines)
+	executor.AssertCalled(t, "docker", "pull", "nginx")
+}
+
+func TestMockExecutor_RecordsCopies(t *testing.T) {
+	executor := NewMockExecutor()
+	cmd := shell.NewCommand("docker", "compose", "up")
+	_, _ = executor.Execute(cmd)
+	cmd.Args[1] = "down"
+
+	executor.AssertCalled(t, "docker", "compose", "up")
+}
@@ -72,10 +72,9 @@ func (s *TimeoutStrategy) Execute(ctx context.Context, cmd *Command) (*Result, e
 	basic := NewBasicStrategy()
 	result, err := basic.Execute(timeoutCtx, cmd)
 
-	// BasicStrategy should handle timeout detection, but double-check
-	if !result.Timeout && timeoutCtx.Err() == context.DeadlineExceeded {
+	if result.Timeout || timeoutCtx.Err() == context.DeadlineExceeded {
 		result.Timeout = true
-		result.Error = fmt.Errorf("command timed out after %s", timeout)
+		result.Error = fmt.Errorf("%w after %s", ErrTimeout, timeout)
 	}
 
 	return result, err
@@ -0,0 +1,134 @@
+package shell
+
+import (
+	"bytes"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+// StreamingExecutor is implemented by executors that can report output while
+// a command runs. It is optional; use ExecuteStream to stream through any
+// CommandExecutor.
+type StreamingExecutor interface {
+	// ExecuteStream runs cmd, calling onStdout and onStderr with each line of
+	// output as it arrives, and returns the result with the full captured
+	// output once the command exits. Either callback may be nil.
+	ExecuteStream(cmd *Command, onStdout, onStderr func([]byte)) (*Result, error)
+}
+
+// Ensure Executor satisfies StreamingExecutor
+var _ StreamingExecutor = (*Executor)(nil)
+
+// ExecuteStream runs cmd with executor, streaming output lines to the
+// callbacks. Executors that don't implement StreamingExecutor run the command
+// to completion and the captured output is replayed to the callbacks
+// afterwards.
+func ExecuteStream(executor CommandExecutor, cmd *Command, onStdout, onStderr func([]byte)) (*Result, error) {
+	if streaming, ok := executor.(StreamingExecutor); ok {
+		return streaming.ExecuteStream(cmd, onStdout, onStderr)
+	}
+
+	result, err := executor.Execute(cmd)
+	if result != nil {
+		replayLines(result.Stdout, onStdout)
+		replayLines(result.Stderr, onStderr)
+	}
+	return result, err
+}
+
+// ExecuteStream runs the command in capture mode, calling onStdout and
+// onStderr with each line (without its line ending) as soon as it is
+// written. The callbacks run on the goroutines copying the output, so
+// onStdout and onStderr may be called concurrently with each other, but
+// each is called for one line at a time. Lines are copies and may be kept.
+// Streamed commands are not retried, since their output is already shown.
+func (e *Executor) ExecuteStream(cmd *Command, onStdout, onStderr func([]byte)) (*Result, error) {
+	stdout := newLineWriter(onStdout)
+	stderr := newLineWriter(onStderr)
+
+	streamed := *cmd
+	streamed.Mode = ModeCapture
+	streamed.UseStrategy = false
+	streamed.Stdout = teeWriter(cmd.Stdout, stdout)
+	streamed.Stderr = teeWriter(cmd.Stderr, stderr)
+
+	if e.verbose {
+		color.Cyan("› %s", cmd.String())
+	}
+	result, err := e.executeCapture(&streamed, time.Now())
+
+	stdout.Flush()
+	stderr.Flush()
+	return result, err
+}
+
+// teeWriter returns a writer writing to w and, if set, to existing
+func teeWriter(existing io.Writer, w io.Writer) io.Writer {
+	if existing == nil {
+		return w
+	}
+	return io.MultiWriter(existing, w)
+}
+
+// lineWriter splits written data into lines and passes each to a callback
+type lineWriter struct {
+	mu      sync.Mutex
+	partial []byte
+	onLine  func([]byte)
+}
+
+func newLineWriter(onLine func([]byte)) *lineWriter {
+	return &lineWriter{onLine: onLine}
+}
+
+// Write passes every complete line in p to the callback and keeps the rest
+// until the next write
+func (w *lineWriter) Write(p []byte) (int, error) {
+	if w.onLine == nil {
+		return len(p), nil
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.partial = append(w.partial, p...)
+	for {
+		i := bytes.IndexByte(w.partial, '\n')
+		if i < 0 {
+			break
+		}
+		w.emit(w.partial[:i])
+		w.partial = w.partial[i+1:]
+	}
+	return len(p), nil
+}
+
+// Flush passes any unterminated last line to the callback
+func (w *lineWriter) Flush() {
+	if w.onLine == nil {
+		return
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if len(w.partial) > 0 {
+		w.emit(w.partial)
+		w.partial = nil
+	}
+}
+
+func (w *lineWriter) emit(line []byte) {
+	line = bytes.TrimSuffix(line, []byte("\r"))
+	w.onLine(append([]byte(nil), line...))
+}
+
+// replayLines passes each line of output to onLine
+func replayLines(output []byte, onLine func([]byte)) {
+	w := newLineWriter(onLine)
+	_, _ = w.Write(output)
+	w.Flush()
+}
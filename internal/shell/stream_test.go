@@ -0,0 +1,101 @@
+package shell
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecutor_ExecuteStream(t *testing.T) {
+	t.Run("lines arrive while the command runs", func(t *testing.T) {
+		executor := NewExecutor(Options{})
+
+		var mu sync.Mutex
+		var stdout, stderr []string
+		firstLine := make(chan time.Time, 1)
+
+		start := time.Now()
+		result, err := executor.ExecuteStream(
+			NewCommand("sh", "-c", "echo one; echo oops >&2; sleep 0.3; printf 'two\\nthree'; exit 3"),
+			func(line []byte) {
+				mu.Lock()
+				defer mu.Unlock()
+				if len(stdout) == 0 {
+					firstLine <- time.Now()
+				}
+				stdout = append(stdout, string(line))
+			},
+			func(line []byte) {
+				mu.Lock()
+				defer mu.Unlock()
+				stderr = append(stderr, string(line))
+			},
+		)
+		require.NoError(t, err)
+
+		assert.Less(t, (<-firstLine).Sub(start), result.Duration-200*time.Millisecond,
+			"first line should be reported before the command exits")
+		assert.Equal(t, []string{"one", "two", "three"}, stdout)
+		assert.Equal(t, []string{"oops"}, stderr)
+		assert.Equal(t, 3, result.ExitCode)
+		assert.Equal(t, "one\ntwo\nthree", string(result.Stdout))
+		assert.Equal(t, "oops\n", string(result.Stderr))
+	})
+
+	t.Run("nil callbacks and command writers", func(t *testing.T) {
+		executor := NewExecutor(Options{})
+
+		var out bytes.Buffer
+		cmd := NewCommand("echo", "hello")
+		cmd.Stdout = &out
+
+		result, err := executor.ExecuteStream(cmd, nil, nil)
+		require.NoError(t, err)
+		assert.Equal(t, "hello\n", string(result.Stdout))
+		assert.Equal(t, "hello\n", out.String())
+	})
+}
+
+func TestExecuteStream_ReplaysNonStreamingExecutors(t *testing.T) {
+	executor := &capturedExecutor{result: &Result{Stdout: []byte("a\r\nb\n"), Stderr: []byte("warn")}}
+
+	var stdout, stderr []string
+	result, err := ExecuteStream(executor, NewCommand("tool"),
+		func(line []byte) { stdout = append(stdout, string(line)) },
+		func(line []byte) { stderr = append(stderr, string(line)) },
+	)
+	require.NoError(t, err)
+	assert.Same(t, executor.result, result)
+	assert.Equal(t, []string{"a", "b"}, stdout)
+	assert.Equal(t, []string{"warn"}, stderr)
+}
+
+func TestTimingExecutor_ExecuteStream(t *testing.T) {
+	timing := NewTimingExecutor(NewExecutor(Options{}), nil)
+
+	var lines []string
+	_, err := timing.ExecuteStream(NewCommand("echo", "hi"), func(line []byte) {
+		lines = append(lines, string(line))
+	}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"hi"}, lines)
+	assert.Equal(t, 1, timing.Recorder().Report().Count)
+}
+
+// capturedExecutor is a CommandExecutor returning a fixed result
+type capturedExecutor struct {
+	result *Result
+}
+
+func (e *capturedExecutor) Execute(cmd *Command) (*Result, error) {
+	return e.result, nil
+}
+
+func (e *capturedExecutor) ExecuteWithContext(ctx context.Context, cmd *Command) (*Result, error) {
+	return e.result, nil
+}
@@ -0,0 +1,211 @@
+package shell
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CommandExecutor is the execution interface implemented by Executor and its decorators
+type CommandExecutor interface {
+	Execute(cmd *Command) (*Result, error)
+	ExecuteWithContext(ctx context.Context, cmd *Command) (*Result, error)
+}
+
+// Ensure Executor satisfies CommandExecutor
+var _ CommandExecutor = (*Executor)(nil)
+
+// ProgramTiming aggregates the durations of all invocations of one program
+type ProgramTiming struct {
+	Program string
+	Count   int
+	Total   time.Duration
+	Max     time.Duration
+}
+
+// TimingReport summarizes recorded command durations
+type TimingReport struct {
+	Count    int
+	Total    time.Duration
+	Programs []ProgramTiming // Sorted by total duration, slowest first
+}
+
+// String renders the report as a human-readable table
+func (r TimingReport) String() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Command profile: %d command(s), %s total\n", r.Count, r.Total.Round(time.Millisecond)))
+	for _, p := range r.Programs {
+		share := 0.0
+		if r.Total > 0 {
+			share = float64(p.Total) / float64(r.Total) * 100
+		}
+		sb.WriteString(fmt.Sprintf("  %-20s %4dx  %10s  (%5.1f%%, max %s)\n",
+			p.Program, p.Count, p.Total.Round(time.Millisecond), share, p.Max.Round(time.Millisecond)))
+	}
+	return sb.String()
+}
+
+// TimingRecorder aggregates command durations by program.
+// It is safe for concurrent use.
+type TimingRecorder struct {
+	mu       sync.Mutex
+	programs map[string]*ProgramTiming
+	count    int
+	total    time.Duration
+}
+
+// NewTimingRecorder creates an empty timing recorder
+func NewTimingRecorder() *TimingRecorder {
+	return &TimingRecorder{
+		programs: make(map[string]*ProgramTiming),
+	}
+}
+
+// Record adds one invocation of program taking duration d
+func (r *TimingRecorder) Record(program string, d time.Duration) {
+	program = filepath.Base(program)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry, ok := r.programs[program]
+	if !ok {
+		entry = &ProgramTiming{Program: program}
+		r.programs[program] = entry
+	}
+	entry.Count++
+	entry.Total += d
+	if d > entry.Max {
+		entry.Max = d
+	}
+
+	r.count++
+	r.total += d
+}
+
+// Report returns a snapshot of the aggregated timings
+func (r *TimingRecorder) Report() TimingReport {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	report := TimingReport{
+		Count:    r.count,
+		Total:    r.total,
+		Programs: make([]ProgramTiming, 0, len(r.programs)),
+	}
+	for _, p := range r.programs {
+		report.Programs = append(report.Programs, *p)
+	}
+	sort.Slice(report.Programs, func(i, j int) bool {
+		if report.Programs[i].Total != report.Programs[j].Total {
+			return report.Programs[i].Total > report.Programs[j].Total
+		}
+		return report.Programs[i].Program < report.Programs[j].Program
+	})
+
+	return report
+}
+
+// Reset discards all recorded timings
+func (r *TimingRecorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.programs = make(map[string]*ProgramTiming)
+	r.count = 0
+	r.total = 0
+}
+
+// TimingExecutor decorates a CommandExecutor and records the wall-clock
+// duration of every command it runs. Scripts run through a shell (sh -c
+// "...") are recorded under the program they start with, so a report shows
+// docker or npm rather than sh. Unlike an audit log it keeps only
+// aggregates, for finding which programs dominate a workflow's runtime.
+//
+// Example:
+//
+//	timing := shell.NewTimingExecutor(shell.NewExecutor(shell.Options{}), nil)
+//	timing.Execute(shell.NewCommand("docker", "compose", "up", "-d"))
+//	fmt.Print(timing.Recorder().Report())
+type TimingExecutor struct {
+	next     CommandExecutor
+	recorder *TimingRecorder
+}
+
+// NewTimingExecutor wraps next. If recorder is nil a new one is created;
+// pass a shared recorder to aggregate across several executors.
+func NewTimingExecutor(next CommandExecutor, recorder *TimingRecorder) *TimingExecutor {
+	if recorder == nil {
+		recorder = NewTimingRecorder()
+	}
+	return &TimingExecutor{
+		next:     next,
+		recorder: recorder,
+	}
+}
+
+// Execute runs the command through the wrapped executor and records its duration
+func (t *TimingExecutor) Execute(cmd *Command) (*Result, error) {
+	start := time.Now()
+	result, err := t.next.Execute(cmd)
+	t.recorder.Record(timedProgram(cmd), time.Since(start))
+	return result, err
+}
+
+// ExecuteWithContext runs the command through the wrapped executor and records its duration
+func (t *TimingExecutor) ExecuteWithContext(ctx context.Context, cmd *Command) (*Result, error) {
+	start := time.Now()
+	result, err := t.next.ExecuteWithContext(ctx, cmd)
+	t.recorder.Record(timedProgram(cmd), time.Since(start))
+	return result, err
+}
+
+// ExecuteStream streams the command's output through the wrapped executor and
+// records its duration
+func (t *TimingExecutor) ExecuteStream(cmd *Command, onStdout, onStderr func([]byte)) (*Result, error) {
+	start := time.Now()
+	result, err := ExecuteStream(t.next, cmd, onStdout, onStderr)
+	t.recorder.Record(timedProgram(cmd), time.Since(start))
+	return result, err
+}
+
+// Recorder returns the recorder holding this executor's timings
+func (t *TimingExecutor) Recorder() *TimingRecorder {
+	return t.recorder
+}
+
+// scriptShells are the interpreters whose scripts are timed under the
+// program the script starts with
+var scriptShells = map[string]bool{
+	"sh": true, "bash": true, "zsh": true, "dash": true, "ksh": true, "fish": true,
+	"cmd": true, "powershell": true, "pwsh": true,
+}
+
+// timedProgram returns the program a command's duration is recorded under:
+// for a shell running a script, the first program of the script (skipping
+// any #! line), otherwise the command itself
+func timedProgram(cmd *Command) string {
+	n := len(cmd.Args)
+	if n < 2 || !scriptShells[interpreterName(append([]string{cmd.Name}, cmd.Args[:n-2]...))] {
+		return cmd.Name
+	}
+	switch strings.ToLower(cmd.Args[n-2]) {
+	case "-c", "/c", "-command":
+	default:
+		return cmd.Name
+	}
+
+	script := cmd.Args[n-1]
+	if strings.HasPrefix(script, "#!") {
+		_, script, _ = strings.Cut(script, "\n")
+	}
+	fields := strings.Fields(script)
+	if len(fields) == 0 {
+		return cmd.Name
+	}
+	return fields[0]
+}
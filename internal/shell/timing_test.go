@@ -0,0 +1,115 @@
+package shell
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sleepExecutor is a CommandExecutor that takes a fixed time per command
+type sleepExecutor struct {
+	delay time.Duration
+	err   error
+}
+
+func (e *sleepExecutor) Execute(cmd *Command) (*Result, error) {
+	time.Sleep(e.delay)
+	return &Result{}, e.err
+}
+
+func (e *sleepExecutor) ExecuteWithContext(ctx context.Context, cmd *Command) (*Result, error) {
+	return e.Execute(cmd)
+}
+
+func TestTimingExecutor_RecordsPerProgram(t *testing.T) {
+	timing := NewTimingExecutor(&sleepExecutor{delay: 5 * time.Millisecond}, nil)
+
+	_, err := timing.Execute(NewCommand("docker", "compose", "ps"))
+	require.NoError(t, err)
+	_, err = timing.ExecuteWithContext(context.Background(), NewCommand("/usr/bin/docker", "ps"))
+	require.NoError(t, err)
+	_, err = timing.Execute(NewCommand("npm", "test"))
+	require.NoError(t, err)
+
+	report := timing.Recorder().Report()
+	assert.Equal(t, 3, report.Count)
+	assert.GreaterOrEqual(t, report.Total, 15*time.Millisecond)
+
+	require.Len(t, report.Programs, 2)
+	assert.Equal(t, "docker", report.Programs[0].Program, "slowest program first, paths stripped")
+	assert.Equal(t, 2, report.Programs[0].Count)
+	assert.Equal(t, "npm", report.Programs[1].Program)
+	assert.GreaterOrEqual(t, report.Programs[0].Max, 5*time.Millisecond)
+}
+
+func TestTimingExecutor_PassesThroughErrors(t *testing.T) {
+	failure := errors.New("boom")
+	timing := NewTimingExecutor(&sleepExecutor{err: failure}, nil)
+
+	_, err := timing.Execute(NewCommand("false"))
+	assert.ErrorIs(t, err, failure)
+	assert.Equal(t, 1, timing.Recorder().Report().Count, "failed commands are still timed")
+}
+
+func TestTimingExecutor_SharedRecorder(t *testing.T) {
+	recorder := NewTimingRecorder()
+	a := NewTimingExecutor(&sleepExecutor{}, recorder)
+	b := NewTimingExecutor(NewExecutor(Options{}), recorder)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _ = a.Execute(NewCommand("git", "status"))
+		}()
+	}
+	wg.Wait()
+	_, err := b.Execute(NewCommand("true"))
+	require.NoError(t, err)
+
+	report := recorder.Report()
+	assert.Equal(t, 11, report.Count)
+	assert.Contains(t, report.String(), "git")
+
+	recorder.Reset()
+	assert.Equal(t, 0, recorder.Report().Count)
+	assert.Empty(t, recorder.Report().Programs)
+}
+
+func TestTimingReport_String(t *testing.T) {
+	recorder := NewTimingRecorder()
+	recorder.Record("docker", 3*time.Second)
+	recorder.Record("npm", time.Second)
+
+	out := recorder.Report().String()
+	assert.Contains(t, out, "2 command(s), 4s total")
+	assert.Contains(t, out, "75.0%")
+	assert.Contains(t, out, "25.0%")
+}
+
+func TestTimingExecutor_RecordsScriptsByProgram(t *testing.T) {
+	tests := []struct {
+		name string
+		cmd  *Command
+		want string
+	}{
+		{"shell script", NewCommand("sh", "-c", "docker compose up -d && docker ps"), "docker"},
+		{"shell with options", NewCommand("bash", "-eu", "-c", "npm test"), "npm"},
+		{"#! line skipped", NewCommand("/usr/bin/env", "bash", "-c", "#!/usr/bin/env bash\nmake build"), "make"},
+		{"cmd", NewCommand("cmd", "/C", "go test ./..."), "go"},
+		{"empty script", NewCommand("sh", "-c", "  "), "sh"},
+		{"other interpreters", NewCommand("python3", "-c", "print(1)"), "python3"},
+		{"plain command", NewCommand("git", "status"), "git"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, timedProgram(tt.cmd))
+		})
+	}
+}
@@ -3,6 +3,7 @@ package shell
 import (
 	"fmt"
 	"io"
+	"sort"
 	"strings"
 	"time"
 )
@@ -29,10 +30,15 @@ type Command struct {
 
 	// Execution settings
 	Mode        ExecutionMode
-	WorkingDir  string
+	WorkingDir  string // Directory to run in; empty means the current directory
 	Environment []string
 	Timeout     time.Duration
 
+	// Retry settings; see Options for executor-wide defaults
+	Retries      int                // Extra attempts after a failed run
+	RetryBackoff time.Duration      // Wait before the first retry, doubled for each further one
+	RetryIf      func(*Result) bool // Decides whether a result is worth retrying; default is a non-zero exit code
+
 	// I/O settings
 	Stdin  io.Reader
 	Stdout io.Writer
@@ -40,7 +46,7 @@ type Command struct {
 
 	// Options
 	AllocateTTY   bool // Allocate pseudo-TTY for interactive commands
-	InheritEnv    bool // Inherit parent process environment
+	InheritEnv    bool // Inherit parent process environment; Environment entries override it
 	SignalForward bool // Forward signals to subprocess
 
 	// Strategy settings
@@ -57,6 +63,10 @@ type CommandOptions struct {
 	Timeout       time.Duration
 	OutputWriter  io.Writer
 	ErrorWriter   io.Writer
+
+	// GlobalEnv is the executor's Options.GlobalEnv, set by the Executor so
+	// that strategies can apply it; Command.Environment overrides it
+	GlobalEnv []string
 }
 
 // Result represents the result of command execution
@@ -82,6 +92,14 @@ type Options struct {
 
 	// Custom environment variables to add to all commands
 	GlobalEnv []string
+
+	// Retries and RetryBackoff apply to commands that don't set their own
+	Retries      int
+	RetryBackoff time.Duration
+
+	// Shell runs script strings, including the flag that takes the script
+	// (e.g. []string{"bash", "-c"}). Defaults to DefaultShell().
+	Shell []string
 }
 
 // NewCommand creates a new command with defaults
@@ -128,6 +146,20 @@ func (c *Command) WithEnv(env ...string) *Command {
 	return c
 }
 
+// WithEnvVars adds environment variables from a map, in sorted key order so
+// the resulting environment is deterministic
+func (c *Command) WithEnvVars(vars map[string]string) *Command {
+	keys := make([]string, 0, len(vars))
+	for key := range vars {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		c.Environment = append(c.Environment, key+"="+vars[key])
+	}
+	return c
+}
+
 // String returns a string representation of the command
 func (c *Command) String() string {
 	if len(c.Args) > 0 {
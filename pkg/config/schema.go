@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"reflect"
+	"strconv"
 	"strings"
 )
 
@@ -175,7 +176,7 @@ func generateFieldSchema(field reflect.StructField) map[string]interface{} {
 
 	// Parse validate tag for additional constraints
 	if validateTag := field.Tag.Get("validate"); validateTag != "" {
-		parseValidationRules(validateTag, schema)
+		parseValidationRules(validateTag, schema, field.Type)
 	}
 
 	// Add description from comment if available
@@ -188,31 +189,56 @@ func generateFieldSchema(field reflect.StructField) map[string]interface{} {
 	return schema
 }
 
-// parseValidationRules parses validation rules from struct tags and adds them to the schema.
-func parseValidationRules(validateTag string, schema map[string]interface{}) {
-	rules := strings.Split(validateTag, ",")
-	for _, rule := range rules {
-		rule = strings.TrimSpace(rule)
+// parseValidationRules parses validation rules from struct tags and adds them
+// to the schema of a field of type typ.
+func parseValidationRules(validateTag string, schema map[string]interface{}, typ reflect.Type) {
+	rules, elementRules, dive := splitDiveRules(splitValidateTag(validateTag))
+	applyValidationRules(rules, schema, typ)
+
+	// Element rules constrain the array's items
+	if items, ok := schema["items"].(map[string]interface{}); ok && dive {
+		if typ.Kind() == reflect.Ptr {
+			typ = typ.Elem()
+		}
+		applyValidationRules(elementRules, items, typ.Elem())
+	}
+}
 
+// applyValidationRules adds the constraints of trimmed validation rules to
+// schema, which describes a value of type typ.
+func applyValidationRules(rules []string, schema map[string]interface{}, typ reflect.Type) {
+	for _, rule := range rules {
 		switch {
 		case rule == "required":
 			// Required is handled at the parent level
 			continue
 
+		case rule == "email":
+			schema["format"] = "email"
+
+		case rule == "url":
+			schema["format"] = "uri"
+
 		case strings.HasPrefix(rule, "min="):
-			val := strings.TrimPrefix(rule, "min=")
+			bound, ok := parseBound(strings.TrimPrefix(rule, "min="), typ)
+			if !ok {
+				continue
+			}
 			if schema["type"] == "integer" || schema["type"] == "number" {
-				schema["minimum"] = parseNumber(val)
+				schema["minimum"] = bound
 			} else if schema["type"] == "string" {
-				schema["minLength"] = parseNumber(val)
+				schema["minLength"] = bound
 			}
 
 		case strings.HasPrefix(rule, "max="):
-			val := strings.TrimPrefix(rule, "max=")
+			bound, ok := parseBound(strings.TrimPrefix(rule, "max="), typ)
+			if !ok {
+				continue
+			}
 			if schema["type"] == "integer" || schema["type"] == "number" {
-				schema["maximum"] = parseNumber(val)
+				schema["maximum"] = bound
 			} else if schema["type"] == "string" {
-				schema["maxLength"] = parseNumber(val)
+				schema["maxLength"] = bound
 			}
 
 		case strings.HasPrefix(rule, "enum="):
@@ -220,6 +246,9 @@ func parseValidationRules(validateTag string, schema map[string]interface{}) {
 			enumValues := strings.Split(val, "|")
 			schema["enum"] = enumValues
 
+		case strings.HasPrefix(rule, "regex="):
+			schema["pattern"] = strings.TrimPrefix(rule, "regex=")
+
 		case strings.HasPrefix(rule, "pattern="):
 			val := strings.TrimPrefix(rule, "pattern=")
 			schema["pattern"] = val
@@ -227,21 +256,27 @@ func parseValidationRules(validateTag string, schema map[string]interface{}) {
 	}
 }
 
-// parseNumber attempts to parse a string as an int or float.
-func parseNumber(s string) interface{} {
-	// Try int first
-	var i int
-	if _, err := fmt.Sscanf(s, "%d", &i); err == nil {
-		return i
+// parseBound parses a min/max bound for a value of type typ: a float for
+// floating-point fields and an integer otherwise. Bounds of time.Duration
+// fields are durations such as 30s, which have no JSON Schema equivalent, so
+// ok is false for them.
+func parseBound(s string, typ reflect.Type) (bound interface{}, ok bool) {
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
 	}
-
-	// Try float
-	var f float64
-	if _, err := fmt.Sscanf(s, "%f", &f); err == nil {
-		return f
+	if typ == durationType {
+		return nil, false
 	}
 
-	return 0
+	if kind := typ.Kind(); kind == reflect.Float32 || kind == reflect.Float64 {
+		f, err := strconv.ParseFloat(s, 64)
+		return f, err == nil
+	}
+	if i, err := strconv.Atoi(s); err == nil {
+		return i, true
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	return f, err == nil
 }
 
 // getFieldName extracts the field name from json or yaml tags.
@@ -273,7 +308,7 @@ func isRequired(field reflect.StructField) bool {
 		return false
 	}
 
-	rules := strings.Split(validateTag, ",")
+	rules := splitValidateTag(validateTag)
 	for _, rule := range rules {
 		if strings.TrimSpace(rule) == "required" {
 			return true
@@ -3,6 +3,7 @@ package config
 import (
 	"reflect"
 	"testing"
+	"time"
 )
 
 // Test types for schema tests
@@ -255,12 +256,16 @@ func TestParseValidationRules(t *testing.T) {
 	// by checking generated schema from struct tags
 
 	type ValidationTestConfig struct {
-		Required   string `validate:"required"`
-		MinMax     int    `validate:"min=1,max=100"`
-		Enum       string `validate:"enum=a|b|c"`
-		MinLen     string `validate:"min=3"`
-		MaxLen     string `validate:"max=10"`
-		MultiRules int    `validate:"required,min=0,max=100"`
+		Required   string   `validate:"required"`
+		MinMax     int      `validate:"min=1,max=100"`
+		Enum       string   `validate:"enum=a|b|c"`
+		MinLen     string   `validate:"min=3"`
+		MaxLen     string   `validate:"max=10"`
+		MultiRules int      `validate:"required,min=0,max=100"`
+		Email      string   `validate:"email"`
+		Webhook    string   `validate:"url"`
+		Slug       string   `validate:"regex=^[a-z]{1,3}$"`
+		Tags       []string `validate:"max=5,dive,min=2"`
 	}
 
 	schema := NewJSONSchemaFromValue(ValidationTestConfig{})
@@ -304,6 +309,64 @@ func TestParseValidationRules(t *testing.T) {
 			t.Error("Enum field missing 'enum' constraint")
 		}
 	}
+
+	// Check element rules apply to array items
+	if tagsSchema, ok := properties["tags"].(map[string]interface{}); ok {
+		items, _ := tagsSchema["items"].(map[string]interface{})
+		if items["minLength"] != 2 {
+			t.Errorf("tags items minLength = %v, want 2", items["minLength"])
+		}
+	} else {
+		t.Error("tags field missing from schema")
+	}
+
+	// Check format and pattern rules
+	for field, want := range map[string][2]string{
+		"email":   {"format", "email"},
+		"webhook": {"format", "uri"},
+		"slug":    {"pattern", "^[a-z]{1,3}$"},
+	} {
+		fieldSchema, ok := properties[field].(map[string]interface{})
+		if !ok {
+			t.Errorf("%s field missing from schema", field)
+			continue
+		}
+		if fieldSchema[want[0]] != want[1] {
+			t.Errorf("%s field %s = %v, want %q", field, want[0], fieldSchema[want[0]], want[1])
+		}
+	}
+}
+
+func TestParseValidationRules_FloatAndDurationBounds(t *testing.T) {
+	type BoundsConfig struct {
+		Ratio   float64       `json:"ratio" validate:"min=0.5,max=1.5"`
+		Timeout time.Duration `json:"timeout" validate:"min=1s,max=30s"`
+		Count   int           `json:"count" validate:"min=1,max=10"`
+	}
+
+	generated, err := NewJSONSchemaFromValue(BoundsConfig{}).GenerateSchema()
+	if err != nil {
+		t.Fatalf("GenerateSchema failed: %v", err)
+	}
+	properties := generated["properties"].(map[string]interface{})
+
+	ratio := properties["ratio"].(map[string]interface{})
+	if ratio["minimum"] != 0.5 || ratio["maximum"] != 1.5 {
+		t.Errorf("ratio bounds = %v..%v, want 0.5..1.5", ratio["minimum"], ratio["maximum"])
+	}
+
+	timeout := properties["timeout"].(map[string]interface{})
+	if _, ok := timeout["minimum"]; ok {
+		t.Errorf("timeout minimum = %v, want no numeric bound", timeout["minimum"])
+	}
+	if _, ok := timeout["maximum"]; ok {
+		t.Errorf("timeout maximum = %v, want no numeric bound", timeout["maximum"])
+	}
+
+	count := properties["count"].(map[string]interface{})
+	if count["minimum"] != 1 || count["maximum"] != 10 {
+		t.Errorf("count bounds = %v..%v, want 1..10", count["minimum"], count["maximum"])
+	}
 }
 
 func TestGetFieldName(t *testing.T) {
@@ -1,10 +1,16 @@
 package config
 
 import (
+	"cmp"
 	"fmt"
+	"net/mail"
+	"net/url"
 	"reflect"
+	"regexp"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 )
 
 // ValidationError represents a configuration validation error with detailed context.
@@ -23,9 +29,44 @@ func (e *ValidationError) Error() string {
 	return fmt.Sprintf("validation error: %s", e.Message)
 }
 
+// NewValidationError creates a validation error for use in custom validators.
+func NewValidationError(field, rule, message string, value interface{}) *ValidationError {
+	return &ValidationError{
+		Field:   field,
+		Value:   value,
+		Rule:    rule,
+		Message: message,
+	}
+}
+
 // ValidationErrors is a collection of validation errors.
 type ValidationErrors []ValidationError
 
+// Add appends a validation error to the collection.
+//
+// Example:
+//
+//	func (c Config) Validate() error {
+//	    var errs config.ValidationErrors
+//	    if c.Min > c.Max {
+//	        errs.Add("Min", "lte_max", "min must not exceed max", c.Min)
+//	    }
+//	    return errs.ErrorOrNil()
+//	}
+func (errs *ValidationErrors) Add(field, rule, message string, value interface{}) {
+	*errs = append(*errs, *NewValidationError(field, rule, message, value))
+}
+
+// ErrorOrNil returns nil if the collection is empty, otherwise the collection itself.
+// Use this when returning from a custom Validate method so an empty
+// collection does not become a non-nil error.
+func (errs ValidationErrors) ErrorOrNil() error {
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
 // Error implements the error interface for multiple errors.
 func (errs ValidationErrors) Error() string {
 	if len(errs) == 0 {
@@ -43,6 +84,22 @@ func (errs ValidationErrors) Error() string {
 	return sb.String()
 }
 
+// StructValidator is implemented by configuration types that need checks
+// beyond struct tags, such as constraints spanning several fields.
+//
+// Validator.Validate calls ValidateConfig after applying tag rules, on the
+// top-level value and on every nested struct. Returned ValidationErrors (or a
+// single *ValidationError) are merged with the tag errors, and field names are
+// prefixed with the path of the enclosing struct. Any other error is recorded
+// with the "custom" rule.
+//
+// The method has its own name so that existing Validate methods, which
+// commonly call Validator.Validate themselves, are never run implicitly.
+// ValidateConfig must not call Validator.Validate on its own receiver.
+type StructValidator interface {
+	ValidateConfig() error
+}
+
 // Validator provides comprehensive configuration validation.
 type Validator struct {
 	// AllowUnknownFields controls whether unknown fields are allowed
@@ -50,14 +107,69 @@ type Validator struct {
 
 	// RequireDefaults controls whether default values must be provided
 	RequireDefaults bool
+
+	// GoFieldNames reports errors with Go struct field names instead of the
+	// json or yaml tag names users write in their configuration files
+	GoFieldNames bool
+	// StopOnFirst ends validation at the first error, which is then returned
+	// as a single-element ValidationErrors
+	StopOnFirst bool
+}
+
+// ValidatorOption configures a Validator created by NewValidator.
+type ValidatorOption func(*Validator)
+
+// WithGoFieldNames makes error field paths use Go struct field names
+// (e.g. "Address.City") instead of tag names (e.g. "address.city").
+func WithGoFieldNames() ValidatorOption {
+	return func(v *Validator) {
+		v.GoFieldNames = true
+	}
+}
+
+// WithStopOnFirst makes Validate return as soon as it finds an error instead
+// of collecting every error.
+func WithStopOnFirst() ValidatorOption {
+	return func(v *Validator) {
+		v.StopOnFirst = true
+	}
 }
 
 // NewValidator creates a new validator with default settings.
-func NewValidator() *Validator {
-	return &Validator{
+func NewValidator(opts ...ValidatorOption) *Validator {
+	v := &Validator{
 		AllowUnknownFields: true,
 		RequireDefaults:    false,
 	}
+	for _, opt := range opts {
+		opt(v)
+	}
+	return v
+}
+
+// fieldName returns the name a field is reported under: its json tag name,
+// else its yaml tag name, else its Go name.
+func (v *Validator) fieldName(field reflect.StructField) string {
+	if v.GoFieldNames {
+		return field.Name
+	}
+
+	for _, key := range []string{"json", "yaml"} {
+		name, _, _ := strings.Cut(field.Tag.Get(key), ",")
+		if name != "" && name != "-" {
+			return name
+		}
+	}
+	return field.Name
+}
+
+// referencedName returns the reported name of the field a rule refers to by
+// its Go name, or ref itself if parent has no such field.
+func (v *Validator) referencedName(parent reflect.Value, ref string) string {
+	if field, ok := parent.Type().FieldByName(ref); ok {
+		return v.fieldName(field)
+	}
+	return ref
 }
 
 // Validate validates a configuration value against struct tags.
@@ -65,10 +177,41 @@ func NewValidator() *Validator {
 //
 // Supported tags:
 //   - validate:"required" - Field must be non-zero
-//   - validate:"min=N" - Numeric/string length minimum
-//   - validate:"max=N" - Numeric/string length maximum
+//   - validate:"required_if=Other value" - Field must be non-zero when sibling field Other equals value
+//   - validate:"min=N" - Numeric/string length minimum (a duration such as 1s for time.Duration)
+//   - validate:"max=N" - Numeric/string length maximum (a duration such as 30s for time.Duration)
 //   - validate:"enum=a|b|c" - Value must be one of the options
-//   - validate:"pattern=regexp" - String must match pattern
+//   - validate:"enum_ci=a|b|c" - Like enum, but strings match regardless of case
+//   - validate:"regex=^[a-z0-9-]+$" - Non-empty string must match the regular expression
+//   - validate:"pattern=regexp" - Alias of regex
+//   - validate:"email" - Non-empty string must be an email address
+//   - validate:"url" - Non-empty string must be a URL with a scheme and host
+//   - validate:"eqfield=Other" - Value must equal sibling field Other
+//   - validate:"nefield=Other" - Value must differ from sibling field Other
+//   - validate:"gtfield=Other" - Value must be greater than sibling field Other
+//   - validate:"gtefield=Other" - Value must be at least sibling field Other
+//   - validate:"ltfield=Other" - Value must be less than sibling field Other
+//   - validate:"ltefield=Other" - Value must be at most sibling field Other
+//
+// Cross-field rules name a field of the same struct by its Go name and
+// compare integers, floats or strings.
+//
+// Errors name fields as they appear in configuration files, using the json
+// or yaml tag name and falling back to the Go name; nested fields are joined
+// with dots. Use WithGoFieldNames to report Go names instead.
+//
+// All errors are collected by default. With WithStopOnFirst, validation ends
+// at the first error and only that error is returned.
+//
+// On a slice or array, dive validates each element: rules before dive apply to
+// the collection itself, rules after it to every element, and struct elements
+// are validated with their own tags. For example, validate:"min=1,dive,min=3"
+// requires at least one element, each at least 3 long. Element errors are
+// reported with paths such as "Endpoints[2].Host".
+//
+// A regex or pattern rule takes the rest of the tag, commas included, so it
+// must be the last rule. Empty strings pass the regex, email and url rules;
+// combine them with required to reject empty values.
 //
 // Example:
 //
@@ -106,66 +249,197 @@ func (v *Validator) Validate(value interface{}) error {
 	// Validate each field
 	for i := 0; i < typ.NumField(); i++ {
 		field := typ.Field(i)
-		fieldValue := val.Field(i)
 
 		// Skip unexported fields
 		if !field.IsExported() {
 			continue
 		}
 
-		// Get validation tag
-		validateTag := field.Tag.Get("validate")
-		if validateTag == "" {
-			// No validation rules, but recurse into nested structs
-			if fieldValue.Kind() == reflect.Struct {
-				if err := v.Validate(fieldValue.Interface()); err != nil {
-					if verrs, ok := err.(ValidationErrors); ok {
-						// Prepend field name to nested errors
-						for j := range verrs {
-							verrs[j].Field = field.Name + "." + verrs[j].Field
-						}
-						errors = append(errors, verrs...)
-					}
-				}
+		errors = append(errors, v.validateField(val, field, val.Field(i))...)
+		if v.stop(errors) {
+			return errors[:1]
+		}
+	}
+
+	// Run struct-level custom validation
+	errors = append(errors, customValidationErrors(val)...)
+	if v.stop(errors) {
+		return errors[:1]
+	}
+
+	if len(errors) > 0 {
+		return errors
+	}
+	return nil
+}
+
+// validateField applies the validate tag of a field, then validates its
+// elements (with dive) and, for a struct, its own fields.
+func (v *Validator) validateField(parent reflect.Value, field reflect.StructField, fieldValue reflect.Value) ValidationErrors {
+	name := v.fieldName(field)
+	var errors ValidationErrors
+
+	// Parse and apply validation rules; rules after dive apply to elements
+	rules, elementRules, dive := splitDiveRules(splitValidateTag(field.Tag.Get("validate")))
+	for _, rule := range rules {
+		if err := v.validateRule(parent, name, fieldValue, rule); err != nil {
+			errors = append(errors, *err)
+			if v.stop(errors) {
+				return errors
 			}
-			continue
 		}
+	}
+
+	if dive {
+		errors = append(errors, v.validateElements(parent, name, fieldValue, elementRules)...)
+		if v.stop(errors) {
+			return errors
+		}
+	}
+
+	// Recurse into nested structs
+	if fieldValue.Kind() == reflect.Struct {
+		if err := v.Validate(fieldValue.Interface()); err != nil {
+			errors = appendNestedErrors(errors, name, err)
+		}
+	}
+
+	return errors
+}
+
+// stop reports whether validation should end because StopOnFirst is set and
+// an error was found.
+func (v *Validator) stop(errors ValidationErrors) bool {
+	return v.StopOnFirst && len(errors) > 0
+}
+
+// customValidationErrors runs the StructValidator implementation of val, if any,
+// and converts the result into ValidationErrors.
+func customValidationErrors(val reflect.Value) ValidationErrors {
+	// Make the value addressable so pointer-receiver methods are found
+	if !val.CanAddr() {
+		ptr := reflect.New(val.Type())
+		ptr.Elem().Set(val)
+		val = ptr.Elem()
+	}
+
+	sv, ok := val.Addr().Interface().(StructValidator)
+	if !ok {
+		return nil
+	}
+
+	err := sv.ValidateConfig()
+	if err == nil {
+		return nil
+	}
+
+	switch e := err.(type) {
+	case ValidationErrors:
+		return append(ValidationErrors(nil), e...)
+	case *ValidationError:
+		if e == nil {
+			return nil
+		}
+		return ValidationErrors{*e}
+	default:
+		return ValidationErrors{{
+			Rule:    "custom",
+			Message: err.Error(),
+		}}
+	}
+}
+
+// appendNestedErrors prefixes nested validation errors with the parent field name
+// and appends them to errors.
+func appendNestedErrors(errors ValidationErrors, fieldName string, err error) ValidationErrors {
+	verrs, ok := err.(ValidationErrors)
+	if !ok {
+		return errors
+	}
+	for j := range verrs {
+		if verrs[j].Field == "" {
+			verrs[j].Field = fieldName
+		} else {
+			verrs[j].Field = fieldName + "." + verrs[j].Field
+		}
+	}
+	return append(errors, verrs...)
+}
+
+// splitDiveRules trims rules and splits them at the first dive into rules for
+// the field and rules for its elements.
+func splitDiveRules(rules []string) (fieldRules, elementRules []string, dive bool) {
+	for _, rule := range rules {
+		rule = strings.TrimSpace(rule)
+		switch {
+		case dive:
+			elementRules = append(elementRules, rule)
+		case rule == "dive":
+			dive = true
+		default:
+			fieldRules = append(fieldRules, rule)
+		}
+	}
+	return fieldRules, elementRules, dive
+}
+
+// validateElements applies rules to each element of a slice or array and
+// validates struct elements, naming errors after the element's index.
+func (v *Validator) validateElements(parent reflect.Value, fieldName string, fieldValue reflect.Value, rules []string) ValidationErrors {
+	if fieldValue.Kind() != reflect.Slice && fieldValue.Kind() != reflect.Array {
+		return nil
+	}
+
+	var errors ValidationErrors
+	for i := 0; i < fieldValue.Len(); i++ {
+		elemName := fmt.Sprintf("%s[%d]", fieldName, i)
+		elem := fieldValue.Index(i)
 
-		// Parse and apply validation rules
-		rules := strings.Split(validateTag, ",")
 		for _, rule := range rules {
-			rule = strings.TrimSpace(rule)
-			if err := v.validateRule(field.Name, fieldValue, rule); err != nil {
+			if err := v.validateRule(parent, elemName, elem, rule); err != nil {
 				errors = append(errors, *err)
 			}
 		}
 
-		// Recurse into nested structs
-		if fieldValue.Kind() == reflect.Struct {
-			if err := v.Validate(fieldValue.Interface()); err != nil {
-				if verrs, ok := err.(ValidationErrors); ok {
-					// Prepend field name to nested errors
-					for j := range verrs {
-						verrs[j].Field = field.Name + "." + verrs[j].Field
-					}
-					errors = append(errors, verrs...)
-				}
+		if elem.Kind() == reflect.Ptr && !elem.IsNil() {
+			elem = elem.Elem()
+		}
+		if elem.Kind() == reflect.Struct {
+			if err := v.Validate(elem.Interface()); err != nil {
+				errors = appendNestedErrors(errors, elemName, err)
 			}
 		}
-	}
 
-	if len(errors) > 0 {
-		return errors
+		if v.stop(errors) {
+			return errors[:1]
+		}
 	}
-	return nil
+	return errors
 }
 
 // validateRule validates a single rule against a field value.
-func (v *Validator) validateRule(fieldName string, fieldValue reflect.Value, rule string) *ValidationError {
+// parent is the struct holding the field, used by cross-field rules.
+func (v *Validator) validateRule(parent reflect.Value, fieldName string, fieldValue reflect.Value, rule string) *ValidationError {
+	if name, ref, ok := strings.Cut(rule, "="); ok {
+		if _, isCrossField := crossFieldRules[name]; isCrossField {
+			return v.validateCrossField(parent, fieldName, fieldValue, name, ref, rule)
+		}
+	}
+
 	switch {
 	case rule == "required":
 		return v.validateRequired(fieldName, fieldValue)
 
+	case rule == "email":
+		return v.validateEmail(fieldName, fieldValue)
+
+	case rule == "url":
+		return v.validateURL(fieldName, fieldValue)
+
+	case strings.HasPrefix(rule, "required_if="):
+		condition := strings.TrimPrefix(rule, "required_if=")
+		return v.validateRequiredIf(parent, fieldName, fieldValue, condition, rule)
+
 	case strings.HasPrefix(rule, "min="):
 		minStr := strings.TrimPrefix(rule, "min=")
 		return v.validateMin(fieldName, fieldValue, minStr, rule)
@@ -176,7 +450,15 @@ func (v *Validator) validateRule(fieldName string, fieldValue reflect.Value, rul
 
 	case strings.HasPrefix(rule, "enum="):
 		enumStr := strings.TrimPrefix(rule, "enum=")
-		return v.validateEnum(fieldName, fieldValue, enumStr, rule)
+		return v.validateEnum(fieldName, fieldValue, enumStr, rule, false)
+
+	case strings.HasPrefix(rule, "enum_ci="):
+		enumStr := strings.TrimPrefix(rule, "enum_ci=")
+		return v.validateEnum(fieldName, fieldValue, enumStr, rule, true)
+
+	case strings.HasPrefix(rule, "regex="):
+		pattern := strings.TrimPrefix(rule, "regex=")
+		return v.validatePattern(fieldName, fieldValue, pattern, rule)
 
 	case strings.HasPrefix(rule, "pattern="):
 		pattern := strings.TrimPrefix(rule, "pattern=")
@@ -201,208 +483,403 @@ func (v *Validator) validateRequired(fieldName string, fieldValue reflect.Value)
 	return nil
 }
 
-// validateMin checks minimum value/length constraints.
-func (v *Validator) validateMin(fieldName string, fieldValue reflect.Value, minStr string, rule string) *ValidationError {
-	switch fieldValue.Kind() {
-	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		min, err := strconv.ParseInt(minStr, 10, 64)
-		if err != nil {
-			return nil // Invalid rule, skip
-		}
-		if fieldValue.Int() < min {
-			return &ValidationError{
-				Field:   fieldName,
-				Value:   fieldValue.Interface(),
-				Rule:    rule,
-				Message: fmt.Sprintf("value %d is less than minimum %d", fieldValue.Int(), min),
-			}
-		}
+// validateRequiredIf checks that a field has a non-zero value when the
+// condition "Other value" holds for its sibling field Other.
+func (v *Validator) validateRequiredIf(parent reflect.Value, fieldName string, fieldValue reflect.Value, condition string, rule string) *ValidationError {
+	ref, want, _ := strings.Cut(strings.TrimSpace(condition), " ")
+	want = strings.TrimSpace(want)
 
-	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-		min, err := strconv.ParseUint(minStr, 10, 64)
-		if err != nil {
-			return nil
-		}
-		if fieldValue.Uint() < min {
-			return &ValidationError{
-				Field:   fieldName,
-				Value:   fieldValue.Interface(),
-				Rule:    rule,
-				Message: fmt.Sprintf("value %d is less than minimum %d", fieldValue.Uint(), min),
-			}
-		}
-
-	case reflect.Float32, reflect.Float64:
-		min, err := strconv.ParseFloat(minStr, 64)
-		if err != nil {
-			return nil
-		}
-		if fieldValue.Float() < min {
-			return &ValidationError{
-				Field:   fieldName,
-				Value:   fieldValue.Interface(),
-				Rule:    rule,
-				Message: fmt.Sprintf("value %f is less than minimum %f", fieldValue.Float(), min),
-			}
+	other := parent.FieldByName(ref)
+	if !other.IsValid() {
+		return &ValidationError{
+			Field:   fieldName,
+			Value:   fieldValue.Interface(),
+			Rule:    rule,
+			Message: fmt.Sprintf("referenced field %q does not exist", ref),
 		}
+	}
 
-	case reflect.String:
-		min, err := strconv.Atoi(minStr)
-		if err != nil {
-			return nil
-		}
-		if len(fieldValue.String()) < min {
-			return &ValidationError{
-				Field:   fieldName,
-				Value:   fieldValue.Interface(),
-				Rule:    rule,
-				Message: fmt.Sprintf("string length %d is less than minimum %d", len(fieldValue.String()), min),
-			}
-		}
+	if formatValue(other) != want || !isZeroValue(fieldValue) {
+		return nil
+	}
 
-	case reflect.Slice, reflect.Array:
-		min, err := strconv.Atoi(minStr)
-		if err != nil {
-			return nil
-		}
-		if fieldValue.Len() < min {
-			return &ValidationError{
-				Field:   fieldName,
-				Value:   fieldValue.Interface(),
-				Rule:    rule,
-				Message: fmt.Sprintf("array length %d is less than minimum %d", fieldValue.Len(), min),
-			}
-		}
+	return &ValidationError{
+		Field:   fieldName,
+		Value:   fieldValue.Interface(),
+		Rule:    rule,
+		Message: fmt.Sprintf("field is required when %s is %s", v.referencedName(parent, ref), want),
 	}
+}
 
-	return nil
+// validateMin checks minimum value/length constraints.
+func (v *Validator) validateMin(fieldName string, fieldValue reflect.Value, minStr string, rule string) *ValidationError {
+	return v.validateBound(fieldName, fieldValue, minStr, rule, true)
 }
 
 // validateMax checks maximum value/length constraints.
 func (v *Validator) validateMax(fieldName string, fieldValue reflect.Value, maxStr string, rule string) *ValidationError {
-	switch fieldValue.Kind() {
-	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		max, err := strconv.ParseInt(maxStr, 10, 64)
-		if err != nil {
-			return nil
-		}
-		if fieldValue.Int() > max {
-			return &ValidationError{
-				Field:   fieldName,
-				Value:   fieldValue.Interface(),
-				Rule:    rule,
-				Message: fmt.Sprintf("value %d exceeds maximum %d", fieldValue.Int(), max),
-			}
+	return v.validateBound(fieldName, fieldValue, maxStr, rule, false)
+}
+
+// durationType is the type of time.Duration fields, whose bounds are
+// written as durations (e.g. "min=1s")
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// validateBound compares a number, duration, string length or collection
+// length with the bound param, a minimum if isMin is set and a maximum
+// otherwise. A param that cannot be parsed for the field's kind is reported
+// as an error.
+func (v *Validator) validateBound(fieldName string, fieldValue reflect.Value, param string, rule string, isMin bool) *ValidationError {
+	var (
+		order   int    // comparison of the field with the bound
+		subject string // what is compared, e.g. "value 5s" or "string length 3"
+		bound   string // the bound as shown in messages
+		err     error
+	)
+
+	switch kind := fieldValue.Kind(); {
+	case fieldValue.Type() == durationType:
+		var limit time.Duration
+		if limit, err = time.ParseDuration(param); err == nil {
+			actual := time.Duration(fieldValue.Int())
+			order, subject, bound = cmp.Compare(actual, limit), "value "+actual.String(), limit.String()
 		}
 
-	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-		max, err := strconv.ParseUint(maxStr, 10, 64)
-		if err != nil {
-			return nil
+	case isIntKind(kind):
+		var limit int64
+		if limit, err = strconv.ParseInt(param, 10, 64); err == nil {
+			order, subject, bound = cmp.Compare(fieldValue.Int(), limit), fmt.Sprintf("value %d", fieldValue.Int()), param
 		}
-		if fieldValue.Uint() > max {
-			return &ValidationError{
-				Field:   fieldName,
-				Value:   fieldValue.Interface(),
-				Rule:    rule,
-				Message: fmt.Sprintf("value %d exceeds maximum %d", fieldValue.Uint(), max),
-			}
+
+	case isUintKind(kind):
+		var limit uint64
+		if limit, err = strconv.ParseUint(param, 10, 64); err == nil {
+			order, subject, bound = cmp.Compare(fieldValue.Uint(), limit), fmt.Sprintf("value %d", fieldValue.Uint()), param
 		}
 
-	case reflect.Float32, reflect.Float64:
-		max, err := strconv.ParseFloat(maxStr, 64)
-		if err != nil {
-			return nil
+	case isFloatKind(kind):
+		var limit float64
+		if limit, err = strconv.ParseFloat(param, 64); err == nil {
+			order, subject, bound = cmp.Compare(fieldValue.Float(), limit), fmt.Sprintf("value %g", fieldValue.Float()), param
 		}
-		if fieldValue.Float() > max {
-			return &ValidationError{
-				Field:   fieldName,
-				Value:   fieldValue.Interface(),
-				Rule:    rule,
-				Message: fmt.Sprintf("value %f exceeds maximum %f", fieldValue.Float(), max),
-			}
+
+	case kind == reflect.String:
+		var limit int
+		if limit, err = strconv.Atoi(param); err == nil {
+			length := len(fieldValue.String())
+			order, subject, bound = cmp.Compare(length, limit), fmt.Sprintf("string length %d", length), param
 		}
 
-	case reflect.String:
-		max, err := strconv.Atoi(maxStr)
-		if err != nil {
-			return nil
+	case kind == reflect.Slice || kind == reflect.Array:
+		var limit int
+		if limit, err = strconv.Atoi(param); err == nil {
+			order, subject, bound = cmp.Compare(fieldValue.Len(), limit), fmt.Sprintf("array length %d", fieldValue.Len()), param
 		}
-		if len(fieldValue.String()) > max {
-			return &ValidationError{
-				Field:   fieldName,
-				Value:   fieldValue.Interface(),
-				Rule:    rule,
-				Message: fmt.Sprintf("string length %d exceeds maximum %d", len(fieldValue.String()), max),
-			}
+
+	default:
+		// Bounds do not apply to other kinds
+		return nil
+	}
+
+	if err != nil {
+		return &ValidationError{
+			Field:   fieldName,
+			Value:   fieldValue.Interface(),
+			Rule:    rule,
+			Message: fmt.Sprintf("invalid bound %q for %s field", param, fieldValue.Type()),
 		}
+	}
 
-	case reflect.Slice, reflect.Array:
-		max, err := strconv.Atoi(maxStr)
-		if err != nil {
-			return nil
+	switch {
+	case isMin && order < 0:
+		return &ValidationError{
+			Field:   fieldName,
+			Value:   fieldValue.Interface(),
+			Rule:    rule,
+			Message: fmt.Sprintf("%s is less than minimum %s", subject, bound),
 		}
-		if fieldValue.Len() > max {
-			return &ValidationError{
-				Field:   fieldName,
-				Value:   fieldValue.Interface(),
-				Rule:    rule,
-				Message: fmt.Sprintf("array length %d exceeds maximum %d", fieldValue.Len(), max),
-			}
+	case !isMin && order > 0:
+		return &ValidationError{
+			Field:   fieldName,
+			Value:   fieldValue.Interface(),
+			Rule:    rule,
+			Message: fmt.Sprintf("%s exceeds maximum %s", subject, bound),
 		}
 	}
 
 	return nil
 }
 
-// validateEnum checks if value is in the allowed set.
-func (v *Validator) validateEnum(fieldName string, fieldValue reflect.Value, enumStr string, rule string) *ValidationError {
+// validateEnum checks if value is in the allowed set. Numbers are compared
+// by value, so 1.0 decoded from JSON matches an allowed 1. With ignoreCase,
+// strings match regardless of case.
+func (v *Validator) validateEnum(fieldName string, fieldValue reflect.Value, enumStr string, rule string, ignoreCase bool) *ValidationError {
 	allowedValues := strings.Split(enumStr, "|")
+	for i, allowed := range allowedValues {
+		allowedValues[i] = strings.TrimSpace(allowed)
+	}
 
-	// Get string representation of value
-	var valueStr string
-	switch fieldValue.Kind() {
-	case reflect.String:
-		valueStr = fieldValue.String()
-	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		valueStr = strconv.FormatInt(fieldValue.Int(), 10)
-	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-		valueStr = strconv.FormatUint(fieldValue.Uint(), 10)
-	case reflect.Bool:
-		valueStr = strconv.FormatBool(fieldValue.Bool())
-	default:
-		valueStr = fmt.Sprintf("%v", fieldValue.Interface())
+	// Look through interface fields, which hold what a decoder produced
+	if fieldValue.Kind() == reflect.Interface && !fieldValue.IsNil() {
+		fieldValue = fieldValue.Elem()
 	}
 
+	// Get string representation of value
+	valueStr := formatValue(fieldValue)
+
 	// Check if value is in allowed set
 	for _, allowed := range allowedValues {
-		if valueStr == strings.TrimSpace(allowed) {
+		if enumMatches(fieldValue, valueStr, allowed, ignoreCase) {
 			return nil
 		}
 	}
 
+	allowedSet := strings.Join(allowedValues, ", ")
+	if ignoreCase {
+		allowedSet += " (case-insensitive)"
+	}
 	return &ValidationError{
 		Field:   fieldName,
 		Value:   fieldValue.Interface(),
 		Rule:    rule,
-		Message: fmt.Sprintf("value %q is not in allowed set: %s", valueStr, enumStr),
+		Message: fmt.Sprintf("value %q is not in allowed set: %s", valueStr, allowedSet),
+	}
+}
+
+// enumMatches reports whether a value matches one allowed enum value.
+func enumMatches(value reflect.Value, valueStr, allowed string, ignoreCase bool) bool {
+	if valueStr == allowed {
+		return true
+	}
+
+	switch {
+	case isIntKind(value.Kind()), isUintKind(value.Kind()), isFloatKind(value.Kind()):
+		want, err := strconv.ParseFloat(allowed, 64)
+		if err != nil {
+			return false
+		}
+		switch {
+		case isIntKind(value.Kind()):
+			return float64(value.Int()) == want
+		case isUintKind(value.Kind()):
+			return float64(value.Uint()) == want
+		default:
+			return value.Float() == want
+		}
+	case value.Kind() == reflect.String:
+		return ignoreCase && strings.EqualFold(valueStr, allowed)
+	default:
+		return false
+	}
+}
+
+// formatValue returns the string form of a value as written in validation rules.
+func formatValue(value reflect.Value) string {
+	switch value.Kind() {
+	case reflect.String:
+		return value.String()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(value.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(value.Uint(), 10)
+	case reflect.Bool:
+		return strconv.FormatBool(value.Bool())
+	default:
+		return fmt.Sprintf("%v", value.Interface())
 	}
 }
 
-// validatePattern checks if string matches the pattern.
-// Note: This is a simplified version. For production, use regexp.MatchString.
+// validatePattern checks if a non-empty string matches the pattern.
 func (v *Validator) validatePattern(fieldName string, fieldValue reflect.Value, pattern string, rule string) *ValidationError {
 	if fieldValue.Kind() != reflect.String {
 		return nil // Pattern validation only applies to strings
 	}
 
-	// For now, this is a placeholder
-	// In a real implementation, use regexp.MatchString(pattern, fieldValue.String())
-	// We're keeping it simple to avoid importing regexp here
+	// Empty values are left to the required rule
+	value := fieldValue.String()
+	if value == "" {
+		return nil
+	}
+
+	re, err := compilePattern(pattern)
+	if err != nil {
+		return &ValidationError{
+			Field:   fieldName,
+			Value:   value,
+			Rule:    rule,
+			Message: fmt.Sprintf("invalid pattern %q: %v", pattern, err),
+		}
+	}
+
+	if !re.MatchString(value) {
+		return &ValidationError{
+			Field:   fieldName,
+			Value:   value,
+			Rule:    rule,
+			Message: fmt.Sprintf("value %q does not match pattern %q", value, pattern),
+		}
+	}
+
+	return nil
+}
+
+// validateEmail checks if a non-empty string is a bare email address.
+func (v *Validator) validateEmail(fieldName string, fieldValue reflect.Value) *ValidationError {
+	if fieldValue.Kind() != reflect.String || fieldValue.String() == "" {
+		return nil
+	}
+
+	value := fieldValue.String()
+	// Display names ("Jane <jane@example.com>") are not accepted
+	addr, err := mail.ParseAddress(value)
+	if err != nil || addr.Address != value {
+		return &ValidationError{
+			Field:   fieldName,
+			Value:   value,
+			Rule:    "email",
+			Message: fmt.Sprintf("value %q is not a valid email address", value),
+		}
+	}
+
+	return nil
+}
+
+// validateURL checks if a non-empty string is an absolute URL with a host.
+func (v *Validator) validateURL(fieldName string, fieldValue reflect.Value) *ValidationError {
+	if fieldValue.Kind() != reflect.String || fieldValue.String() == "" {
+		return nil
+	}
+
+	value := fieldValue.String()
+	u, err := url.Parse(value)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return &ValidationError{
+			Field:   fieldName,
+			Value:   value,
+			Rule:    "url",
+			Message: fmt.Sprintf("value %q is not a valid URL (scheme and host are required)", value),
+		}
+	}
+
+	return nil
+}
+
+// crossFieldRules maps each cross-field rule to the check it applies to the
+// comparison result, and the wording used when the check fails
+var crossFieldRules = map[string]struct {
+	check    func(order int) bool
+	relation string
+}{
+	"eqfield":  {func(order int) bool { return order == 0 }, "equal to"},
+	"nefield":  {func(order int) bool { return order != 0 }, "different from"},
+	"gtfield":  {func(order int) bool { return order > 0 }, "greater than"},
+	"gtefield": {func(order int) bool { return order >= 0 }, "greater than or equal to"},
+	"ltfield":  {func(order int) bool { return order < 0 }, "less than"},
+	"ltefield": {func(order int) bool { return order <= 0 }, "less than or equal to"},
+}
+
+// validateCrossField compares a field with the sibling field named ref.
+func (v *Validator) validateCrossField(parent reflect.Value, fieldName string, fieldValue reflect.Value, name, ref, rule string) *ValidationError {
+	other := parent.FieldByName(ref)
+	if !other.IsValid() {
+		return &ValidationError{
+			Field:   fieldName,
+			Value:   fieldValue.Interface(),
+			Rule:    rule,
+			Message: fmt.Sprintf("referenced field %q does not exist", ref),
+		}
+	}
+
+	order, ok := compareValues(fieldValue, other)
+	if !ok {
+		return &ValidationError{
+			Field:   fieldName,
+			Value:   fieldValue.Interface(),
+			Rule:    rule,
+			Message: fmt.Sprintf("cannot compare %s (%s) with %s (%s)", fieldName, fieldValue.Kind(), v.referencedName(parent, ref), other.Kind()),
+		}
+	}
+
+	crossRule := crossFieldRules[name]
+	if !crossRule.check(order) {
+		return &ValidationError{
+			Field:   fieldName,
+			Value:   fieldValue.Interface(),
+			Rule:    rule,
+			Message: fmt.Sprintf("%s (%v) must be %s %s (%v)", fieldName, fieldValue.Interface(), crossRule.relation, v.referencedName(parent, ref), other.Interface()),
+		}
+	}
 
 	return nil
 }
 
+// compareValues returns -1, 0 or 1 as a is less than, equal to or greater
+// than b. It reports false if a and b are not both integers, floats or strings.
+func compareValues(a, b reflect.Value) (int, bool) {
+	switch {
+	case isIntKind(a.Kind()) && isIntKind(b.Kind()):
+		return cmp.Compare(a.Int(), b.Int()), true
+	case isUintKind(a.Kind()) && isUintKind(b.Kind()):
+		return cmp.Compare(a.Uint(), b.Uint()), true
+	case isFloatKind(a.Kind()) && isFloatKind(b.Kind()):
+		return cmp.Compare(a.Float(), b.Float()), true
+	case a.Kind() == reflect.String && b.Kind() == reflect.String:
+		return strings.Compare(a.String(), b.String()), true
+	default:
+		return 0, false
+	}
+}
+
+func isIntKind(k reflect.Kind) bool {
+	return k >= reflect.Int && k <= reflect.Int64
+}
+
+func isUintKind(k reflect.Kind) bool {
+	return k >= reflect.Uint && k <= reflect.Uint64
+}
+
+func isFloatKind(k reflect.Kind) bool {
+	return k == reflect.Float32 || k == reflect.Float64
+}
+
+// patternCache holds compiled validation patterns keyed by pattern string
+var patternCache sync.Map
+
+// compilePattern compiles pattern once and reuses the result afterwards
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	if cached, ok := patternCache.Load(pattern); ok {
+		return cached.(*regexp.Regexp), nil
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	patternCache.Store(pattern, re)
+	return re, nil
+}
+
+// splitValidateTag splits a validate tag into rules. A regex or pattern rule
+// keeps the rest of the tag, so the expression may contain commas.
+func splitValidateTag(tag string) []string {
+	var rules []string
+	for tag != "" {
+		rest := strings.TrimLeft(tag, " ")
+		if strings.HasPrefix(rest, "regex=") || strings.HasPrefix(rest, "pattern=") {
+			return append(rules, rest)
+		}
+
+		rule, remaining, found := strings.Cut(tag, ",")
+		rules = append(rules, rule)
+		if !found {
+			break
+		}
+		tag = remaining
+	}
+	return rules
+}
+
 // isZeroValue checks if a reflect.Value is the zero value for its type.
 func isZeroValue(v reflect.Value) bool {
 	switch v.Kind() {
@@ -431,6 +908,9 @@ func isZeroValue(v reflect.Value) bool {
 
 // ValidateWithDefaults validates a configuration and applies defaults.
 // If a field is zero and a default is available, the default is applied.
+// Defaults are merged at every level: zero fields of nested structs and of
+// pointers to structs are filled in without touching fields that are set, and
+// a nil pointer to a struct is allocated from its default.
 //
 // This is useful when loading configurations from files that may be
 // incomplete - missing fields get filled in with defaults.
@@ -485,12 +965,26 @@ func applyDefaults(target reflect.Value, defaults reflect.Value) {
 			continue
 		}
 
+		// Merge into pointers to structs field by field, allocating a nil
+		// target so it never shares the default's struct
+		if targetField.Kind() == reflect.Ptr && targetField.Type().Elem().Kind() == reflect.Struct {
+			if defaultField.IsNil() {
+				continue
+			}
+			if targetField.IsNil() {
+				targetField.Set(reflect.New(targetField.Type().Elem()))
+			}
+			applyDefaults(targetField, defaultField)
+			continue
+		}
+
 		// If target field is zero, apply default
 		if isZeroValue(targetField) && !isZeroValue(defaultField) {
 			targetField.Set(defaultField)
 		}
 
-		// Recurse into nested structs
+		// Recurse into nested structs so zero fields of a partially set
+		// struct still get their defaults
 		if targetField.Kind() == reflect.Struct && defaultField.Kind() == reflect.Struct {
 			applyDefaults(targetField, defaultField)
 		}
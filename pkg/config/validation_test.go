@@ -4,6 +4,7 @@ import (
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestValidator_Required(t *testing.T) {
@@ -211,6 +212,67 @@ func TestValidator_Enum(t *testing.T) {
 	}
 }
 
+func TestValidator_EnumCaseInsensitiveAndNumeric(t *testing.T) {
+	type Config struct {
+		Role    string      `json:"role" validate:"enum_ci=admin|user|guest"`
+		Strict  string      `json:"strict" validate:"enum=admin|user"`
+		Ratio   float64     `json:"ratio" validate:"enum=0|0.5|1"`
+		Level   int         `json:"level" validate:"enum=1.0|2.0"`
+		Decoded interface{} `json:"decoded" validate:"enum=0|1|2"`
+	}
+
+	valid := Config{Role: "admin", Strict: "user", Ratio: 0.5, Level: 1, Decoded: float64(1)}
+
+	tests := []struct {
+		name      string
+		modify    func(*Config)
+		wantField string
+	}{
+		{name: "valid", modify: func(c *Config) {}},
+		{name: "enum_ci matches other case", modify: func(c *Config) { c.Role = "Admin" }},
+		{name: "enum_ci rejects unknown value", modify: func(c *Config) { c.Role = "root" }, wantField: "role"},
+		{name: "enum stays case-sensitive", modify: func(c *Config) { c.Strict = "User" }, wantField: "strict"},
+		{name: "float field matches", modify: func(c *Config) { c.Ratio = 1.0 }},
+		{name: "float field rejects", modify: func(c *Config) { c.Ratio = 0.25 }, wantField: "ratio"},
+		{name: "int field matches float option", modify: func(c *Config) { c.Level = 2 }},
+		{name: "decoded float matches", modify: func(c *Config) { c.Decoded = 2.0 }},
+		{name: "decoded float rejects", modify: func(c *Config) { c.Decoded = 1.5 }, wantField: "decoded"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := valid
+			tt.modify(&config)
+
+			err := NewValidator().Validate(config)
+			if tt.wantField == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+
+			verrs, ok := err.(ValidationErrors)
+			if !ok || len(verrs) != 1 {
+				t.Fatalf("expected one validation error, got %v", err)
+			}
+			if verrs[0].Field != tt.wantField {
+				t.Errorf("Field = %q, want %q", verrs[0].Field, tt.wantField)
+			}
+		})
+	}
+
+	err := NewValidator().Validate(Config{Role: "root", Strict: "admin", Ratio: 1, Level: 1, Decoded: 0.0})
+	verrs, ok := err.(ValidationErrors)
+	if !ok || len(verrs) != 1 {
+		t.Fatalf("expected one validation error, got %v", err)
+	}
+	want := `value "root" is not in allowed set: admin, user, guest (case-insensitive)`
+	if verrs[0].Message != want {
+		t.Errorf("Message = %q, want %q", verrs[0].Message, want)
+	}
+}
+
 func TestValidator_MultipleErrors(t *testing.T) {
 	type Config struct {
 		Name  string `json:"name" validate:"required,min=2"`
@@ -310,10 +372,16 @@ func TestValidator_NestedStructs(t *testing.T) {
 			// If we expect an error and got one, check that field names include nesting
 			if tt.wantErr && err != nil {
 				errStr := err.Error()
-				if !strings.Contains(errStr, "Address.") {
+				if !strings.Contains(errStr, "address.") {
 					t.Errorf("Expected nested field name in error, got: %s", errStr)
 				}
 			}
+
+			// Go field names remain available as an option
+			goErr := NewValidator(WithGoFieldNames()).Validate(tt.config)
+			if tt.wantErr && (goErr == nil || !strings.Contains(goErr.Error(), "Address.")) {
+				t.Errorf("Expected Go nested field name in error, got: %v", goErr)
+			}
 		})
 	}
 }
@@ -510,16 +578,81 @@ func TestValidator_InvalidRules(t *testing.T) {
 	}
 
 	validator := NewValidator()
-	// Should not panic, invalid rules should be skipped
+	// Should not panic; unknown rules are skipped, unparseable bounds reported
 	err := validator.Validate(config)
 
-	// Invalid rules are silently skipped, so no error expected
-	if err != nil {
-		t.Logf("Got error (expected to be skipped): %v", err)
+	verrs, ok := err.(ValidationErrors)
+	if !ok {
+		t.Fatalf("expected ValidationErrors for unparseable bounds, got %v", err)
+	}
+	got := map[string]string{}
+	for _, verr := range verrs {
+		got[verr.Field] = verr.Rule
+	}
+	// An empty enum allows nothing, so it rejects the value too
+	want := map[string]string{"bad_min": "min=invalid", "bad_max": "max=not_a_number", "bad_enum": "enum="}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("errors = %v, want %v", got, want)
+	}
+}
+
+// TestValidator_DurationAndFloatBounds tests min/max on time.Duration and float fields
+func TestValidator_DurationAndFloatBounds(t *testing.T) {
+	type Config struct {
+		Timeout time.Duration `json:"timeout" validate:"min=1s,max=30s"`
+		Ratio   float64       `json:"ratio" validate:"min=0.5,max=1.0"`
+	}
+
+	tests := []struct {
+		name    string
+		config  Config
+		wantMsg string
+	}{
+		{"within bounds", Config{Timeout: 5 * time.Second, Ratio: 0.75}, ""},
+		{"at bounds", Config{Timeout: 30 * time.Second, Ratio: 0.5}, ""},
+		{"duration below minimum", Config{Timeout: 500 * time.Millisecond, Ratio: 1}, "value 500ms is less than minimum 1s"},
+		{"duration above maximum", Config{Timeout: time.Minute, Ratio: 1}, "value 1m0s exceeds maximum 30s"},
+		{"float below minimum", Config{Timeout: time.Second, Ratio: 0.25}, "value 0.25 is less than minimum 0.5"},
+		{"float above maximum", Config{Timeout: time.Second, Ratio: 1.5}, "value 1.5 exceeds maximum 1.0"},
 	}
+
+	validator := NewValidator()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validator.Validate(tt.config)
+			if tt.wantMsg == "" {
+				if err != nil {
+					t.Fatalf("Validate() unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantMsg) {
+				t.Errorf("Validate() error = %v, want message containing %q", err, tt.wantMsg)
+			}
+		})
+	}
+
+	t.Run("mismatched bound kinds are reported", func(t *testing.T) {
+		type Mixed struct {
+			Timeout time.Duration `json:"timeout" validate:"min=5"`
+			Ratio   float64       `json:"ratio" validate:"max=1s"`
+		}
+
+		verrs, ok := validator.Validate(Mixed{Timeout: time.Second, Ratio: 0.1}).(ValidationErrors)
+		if !ok || len(verrs) != 2 {
+			t.Fatalf("expected 2 errors, got %v", verrs)
+		}
+		if !strings.Contains(verrs[0].Message, `invalid bound "5" for time.Duration field`) {
+			t.Errorf("unexpected message: %s", verrs[0].Message)
+		}
+		if !strings.Contains(verrs[1].Message, `invalid bound "1s" for float64 field`) {
+			t.Errorf("unexpected message: %s", verrs[1].Message)
+		}
+	})
 }
 
-// TestValidator_Pattern tests pattern validation (currently a placeholder)
+// TestValidator_Pattern tests pattern validation
 func TestValidator_Pattern(t *testing.T) {
 	type Config struct {
 		Email     string `json:"email" validate:"pattern=^[a-z]+@[a-z]+\\.[a-z]+$"`
@@ -532,9 +665,14 @@ func TestValidator_Pattern(t *testing.T) {
 		wantErr bool
 	}{
 		{
-			name:    "pattern on string (currently noop)",
+			name:    "pattern on string",
 			config:  Config{Email: "test@example.com", NotString: 123},
-			wantErr: false, // Pattern validation is currently a noop
+			wantErr: false,
+		},
+		{
+			name:    "pattern mismatch",
+			config:  Config{Email: "not-an-email", NotString: 123},
+			wantErr: true,
 		},
 		{
 			name:    "pattern on non-string (skipped)",
@@ -555,6 +693,580 @@ func TestValidator_Pattern(t *testing.T) {
 	}
 }
 
+// TestValidator_Regex tests regex validation and its interaction with required
+func TestValidator_Regex(t *testing.T) {
+	type Config struct {
+		Slug     string `json:"slug" validate:"regex=^[a-z0-9-]+$"`
+		Required string `json:"required" validate:"required,regex=^v[0-9]+$"`
+		Repeat   string `json:"repeat" validate:"max=10,regex=^a{1,3}$"`
+		Invalid  string `json:"invalid" validate:"regex=[a-"`
+	}
+
+	tests := []struct {
+		name       string
+		config     Config
+		wantFields []string
+		wantRules  []string
+	}{
+		{
+			name:   "all match",
+			config: Config{Slug: "my-project", Required: "v2", Repeat: "aa"},
+		},
+		{
+			name:       "slug mismatch",
+			config:     Config{Slug: "My Project", Required: "v2"},
+			wantFields: []string{"slug"},
+			wantRules:  []string{"regex=^[a-z0-9-]+$"},
+		},
+		{
+			name:   "empty string passes without required",
+			config: Config{Required: "v1"},
+		},
+		{
+			name:       "empty string fails with required",
+			config:     Config{Slug: "ok"},
+			wantFields: []string{"required"},
+			wantRules:  []string{"required"},
+		},
+		{
+			name:       "pattern containing a comma",
+			config:     Config{Required: "v1", Repeat: "aaaa"},
+			wantFields: []string{"repeat"},
+			wantRules:  []string{"regex=^a{1,3}$"},
+		},
+		{
+			name:       "invalid pattern",
+			config:     Config{Required: "v1", Invalid: "x"},
+			wantFields: []string{"invalid"},
+			wantRules:  []string{"regex=[a-"},
+		},
+	}
+
+	validator := NewValidator()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validator.Validate(tt.config)
+			if len(tt.wantFields) == 0 {
+				if err != nil {
+					t.Fatalf("Validate() unexpected error: %v", err)
+				}
+				return
+			}
+
+			verrs, ok := err.(ValidationErrors)
+			if !ok {
+				t.Fatalf("Validate() error = %v, want ValidationErrors", err)
+			}
+			if len(verrs) != len(tt.wantFields) {
+				t.Fatalf("got %d errors, want %d: %v", len(verrs), len(tt.wantFields), verrs)
+			}
+			for i, verr := range verrs {
+				if verr.Field != tt.wantFields[i] {
+					t.Errorf("error %d field = %q, want %q", i, verr.Field, tt.wantFields[i])
+				}
+				if verr.Rule != tt.wantRules[i] {
+					t.Errorf("error %d rule = %q, want %q", i, verr.Rule, tt.wantRules[i])
+				}
+			}
+		})
+	}
+
+	t.Run("message names the field and pattern", func(t *testing.T) {
+		err := validator.Validate(Config{Slug: "Bad", Required: "v1"})
+		if err == nil {
+			t.Fatal("expected an error")
+		}
+		msg := err.Error()
+		if !strings.Contains(msg, `"slug"`) || !strings.Contains(msg, `"^[a-z0-9-]+$"`) {
+			t.Errorf("error %q should name the field and the pattern", msg)
+		}
+	})
+}
+
+// TestValidator_EmailAndURL tests the email and url format rules
+func TestValidator_EmailAndURL(t *testing.T) {
+	type Config struct {
+		Email   string `json:"email" validate:"email"`
+		Webhook string `json:"webhook" validate:"url"`
+		Count   int    `json:"count" validate:"email,url"`
+	}
+
+	tests := []struct {
+		name      string
+		config    Config
+		wantRules []string
+	}{
+		{"valid values", Config{Email: "dev@example.com", Webhook: "https://hooks.example.com/glide"}, nil},
+		{"other schemes", Config{Email: "a.b+tag@sub.example.org", Webhook: "ftp://x"}, nil},
+		{"empty values pass", Config{}, nil},
+		{"missing domain", Config{Email: "foo@"}, []string{"email"}},
+		{"missing at sign", Config{Email: "no-at-sign"}, []string{"email"}},
+		{"display name", Config{Email: "Dev <dev@example.com>"}, []string{"email"}},
+		{"not a url", Config{Webhook: "not a url"}, []string{"url"}},
+		{"missing host", Config{Webhook: "https://"}, []string{"url"}},
+		{"missing scheme", Config{Webhook: "example.com/hook"}, []string{"url"}},
+		{"both invalid", Config{Email: "foo@", Webhook: "nope"}, []string{"email", "url"}},
+	}
+
+	validator := NewValidator()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validator.Validate(tt.config)
+			if len(tt.wantRules) == 0 {
+				if err != nil {
+					t.Fatalf("Validate() unexpected error: %v", err)
+				}
+				return
+			}
+
+			verrs, ok := err.(ValidationErrors)
+			if !ok {
+				t.Fatalf("Validate() error = %v, want ValidationErrors", err)
+			}
+			if len(verrs) != len(tt.wantRules) {
+				t.Fatalf("got %d errors, want %d: %v", len(verrs), len(tt.wantRules), verrs)
+			}
+			for i, verr := range verrs {
+				if verr.Rule != tt.wantRules[i] {
+					t.Errorf("error %d rule = %q, want %q", i, verr.Rule, tt.wantRules[i])
+				}
+			}
+		})
+	}
+}
+
+// TestValidator_CrossField tests rules that compare sibling fields
+func TestValidator_CrossField(t *testing.T) {
+	type Ports struct {
+		PortStart int `json:"port_start"`
+		PortEnd   int `json:"port_end" validate:"gtfield=PortStart"`
+	}
+
+	type Config struct {
+		Password        string  `json:"password"`
+		PasswordConfirm string  `json:"password_confirm" validate:"eqfield=Password"`
+		Min             float64 `json:"min"`
+		Max             float64 `json:"max" validate:"gtefield=Min"`
+		Ports           Ports   `json:"ports"`
+	}
+
+	valid := Config{
+		Password:        "secret",
+		PasswordConfirm: "secret",
+		Min:             1.5,
+		Max:             1.5,
+		Ports:           Ports{PortStart: 8000, PortEnd: 8010},
+	}
+
+	tests := []struct {
+		name       string
+		mutate     func(c *Config)
+		wantFields []string
+		wantRules  []string
+	}{
+		{"valid", func(c *Config) {}, nil, nil},
+		{
+			name:       "mismatched confirmation",
+			mutate:     func(c *Config) { c.PasswordConfirm = "other" },
+			wantFields: []string{"password_confirm"},
+			wantRules:  []string{"eqfield=Password"},
+		},
+		{
+			name:       "float below sibling",
+			mutate:     func(c *Config) { c.Max = 1.0 },
+			wantFields: []string{"max"},
+			wantRules:  []string{"gtefield=Min"},
+		},
+		{
+			name:       "nested sibling comparison",
+			mutate:     func(c *Config) { c.Ports.PortEnd = 8000 },
+			wantFields: []string{"ports.port_end"},
+			wantRules:  []string{"gtfield=PortStart"},
+		},
+	}
+
+	validator := NewValidator()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := valid
+			tt.mutate(&config)
+
+			err := validator.Validate(config)
+			if len(tt.wantFields) == 0 {
+				if err != nil {
+					t.Fatalf("Validate() unexpected error: %v", err)
+				}
+				return
+			}
+
+			verrs, ok := err.(ValidationErrors)
+			if !ok {
+				t.Fatalf("Validate() error = %v, want ValidationErrors", err)
+			}
+			if len(verrs) != len(tt.wantFields) {
+				t.Fatalf("got %d errors, want %d: %v", len(verrs), len(tt.wantFields), verrs)
+			}
+			for i, verr := range verrs {
+				if verr.Field != tt.wantFields[i] {
+					t.Errorf("error %d field = %q, want %q", i, verr.Field, tt.wantFields[i])
+				}
+				if verr.Rule != tt.wantRules[i] {
+					t.Errorf("error %d rule = %q, want %q", i, verr.Rule, tt.wantRules[i])
+				}
+			}
+		})
+	}
+
+	t.Run("message names both fields", func(t *testing.T) {
+		err := validator.Validate(Ports{PortStart: 9000, PortEnd: 80})
+		if err == nil {
+			t.Fatal("expected an error")
+		}
+		want := "port_end (80) must be greater than port_start (9000)"
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q should contain %q", err.Error(), want)
+		}
+	})
+
+	t.Run("all comparison rules", func(t *testing.T) {
+		type Rules struct {
+			Base int
+			Eq   int `validate:"eqfield=Base"`
+			Ne   int `validate:"nefield=Base"`
+			Gt   int `validate:"gtfield=Base"`
+			Gte  int `validate:"gtefield=Base"`
+			Lt   int `validate:"ltfield=Base"`
+			Lte  int `validate:"ltefield=Base"`
+		}
+
+		if err := validator.Validate(Rules{Base: 5, Eq: 5, Ne: 4, Gt: 6, Gte: 5, Lt: 4, Lte: 5}); err != nil {
+			t.Errorf("Validate() unexpected error: %v", err)
+		}
+
+		err := validator.Validate(Rules{Base: 5, Eq: 4, Ne: 5, Gt: 5, Gte: 4, Lt: 5, Lte: 6})
+		verrs, ok := err.(ValidationErrors)
+		if !ok || len(verrs) != 6 {
+			t.Errorf("expected 6 errors, got %v", err)
+		}
+	})
+
+	t.Run("invalid references", func(t *testing.T) {
+		type Broken struct {
+			Name    string
+			Missing int `validate:"gtfield=Nope"`
+			Mixed   int `validate:"eqfield=Name"`
+		}
+
+		err := validator.Validate(Broken{})
+		verrs, ok := err.(ValidationErrors)
+		if !ok || len(verrs) != 2 {
+			t.Fatalf("expected 2 errors, got %v", err)
+		}
+		if !strings.Contains(verrs[0].Message, `"Nope" does not exist`) {
+			t.Errorf("unexpected message for missing field: %s", verrs[0].Message)
+		}
+		if !strings.Contains(verrs[1].Message, "cannot compare") {
+			t.Errorf("unexpected message for mixed kinds: %s", verrs[1].Message)
+		}
+	})
+}
+
+// TestValidator_Dive tests element validation of slices
+func TestValidator_Dive(t *testing.T) {
+	type Endpoint struct {
+		Host string `json:"host" validate:"required"`
+		Port int    `json:"port" validate:"min=1,max=65535"`
+	}
+
+	type Config struct {
+		Endpoints []Endpoint  `json:"endpoints" validate:"required,dive"`
+		Optional  []*Endpoint `json:"optional" validate:"dive"`
+		Tags      []string    `json:"tags" validate:"dive,min=1"`
+		Regions   []string    `json:"regions" validate:"min=1,dive,enum=eu|us"`
+		Fixed     [2]string   `json:"fixed" validate:"dive,regex=^[a-z]*$"`
+	}
+
+	valid := func() Config {
+		return Config{
+			Endpoints: []Endpoint{{Host: "a", Port: 80}, {Host: "b", Port: 443}},
+			Tags:      []string{"x", "y"},
+			Regions:   []string{"eu"},
+		}
+	}
+
+	tests := []struct {
+		name       string
+		mutate     func(c *Config)
+		wantFields []string
+		wantRules  []string
+	}{
+		{"valid", func(c *Config) {}, nil, nil},
+		{
+			name:       "struct element errors carry their index",
+			mutate:     func(c *Config) { c.Endpoints = append(c.Endpoints, Endpoint{Port: 0}) },
+			wantFields: []string{"endpoints[2].host", "endpoints[2].port"},
+			wantRules:  []string{"required", "min=1"},
+		},
+		{
+			name:       "scalar element rules",
+			mutate:     func(c *Config) { c.Tags = []string{"ok", ""} },
+			wantFields: []string{"tags[1]"},
+			wantRules:  []string{"min=1"},
+		},
+		{
+			name:       "collection and element rules",
+			mutate:     func(c *Config) { c.Regions = []string{"ap"} },
+			wantFields: []string{"regions[0]"},
+			wantRules:  []string{"enum=eu|us"},
+		},
+		{
+			name:       "empty slice only fails rules on the slice",
+			mutate:     func(c *Config) { c.Regions = []string{}; c.Tags = []string{} },
+			wantFields: []string{"regions"},
+			wantRules:  []string{"min=1"},
+		},
+		{
+			name:       "nil slice with required",
+			mutate:     func(c *Config) { c.Endpoints = nil },
+			wantFields: []string{"endpoints"},
+			wantRules:  []string{"required"},
+		},
+		{
+			name:       "pointer elements",
+			mutate:     func(c *Config) { c.Optional = []*Endpoint{nil, {Host: "c"}} },
+			wantFields: []string{"optional[1].port"},
+			wantRules:  []string{"min=1"},
+		},
+		{
+			name:       "arrays",
+			mutate:     func(c *Config) { c.Fixed = [2]string{"ok", "NO"} },
+			wantFields: []string{"fixed[1]"},
+			wantRules:  []string{"regex=^[a-z]*$"},
+		},
+	}
+
+	validator := NewValidator()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := valid()
+			tt.mutate(&config)
+
+			err := validator.Validate(config)
+			if len(tt.wantFields) == 0 {
+				if err != nil {
+					t.Fatalf("Validate() unexpected error: %v", err)
+				}
+				return
+			}
+
+			verrs, ok := err.(ValidationErrors)
+			if !ok {
+				t.Fatalf("Validate() error = %v, want ValidationErrors", err)
+			}
+			if len(verrs) != len(tt.wantFields) {
+				t.Fatalf("got %d errors, want %d: %v", len(verrs), len(tt.wantFields), verrs)
+			}
+			for i, verr := range verrs {
+				if verr.Field != tt.wantFields[i] {
+					t.Errorf("error %d field = %q, want %q", i, verr.Field, tt.wantFields[i])
+				}
+				if verr.Rule != tt.wantRules[i] {
+					t.Errorf("error %d rule = %q, want %q", i, verr.Rule, tt.wantRules[i])
+				}
+			}
+		})
+	}
+}
+
+// TestValidator_RequiredIf tests conditionally required fields
+func TestValidator_RequiredIf(t *testing.T) {
+	type Config struct {
+		TLSEnabled  bool   `json:"tls_enabled"`
+		TLSCertPath string `json:"tls_cert_path" validate:"required_if=TLSEnabled true,max=20"`
+		Mode        string `json:"mode"`
+		Replicas    int    `json:"replicas" validate:"required_if=Mode cluster"`
+	}
+
+	tests := []struct {
+		name      string
+		config    Config
+		wantRules []string
+	}{
+		{"condition not met", Config{}, nil},
+		{"condition met and present", Config{TLSEnabled: true, TLSCertPath: "/etc/cert.pem"}, nil},
+		{"condition met and missing", Config{TLSEnabled: true}, []string{"required_if=TLSEnabled true"}},
+		{"composes with other rules", Config{TLSEnabled: true, TLSCertPath: "/etc/ssl/private/glide-cert.pem"}, []string{"max=20"}},
+		{"string condition met", Config{Mode: "cluster"}, []string{"required_if=Mode cluster"}},
+		{"string condition not met", Config{Mode: "single"}, nil},
+	}
+
+	validator := NewValidator()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validator.Validate(tt.config)
+			if len(tt.wantRules) == 0 {
+				if err != nil {
+					t.Fatalf("Validate() unexpected error: %v", err)
+				}
+				return
+			}
+
+			verrs, ok := err.(ValidationErrors)
+			if !ok {
+				t.Fatalf("Validate() error = %v, want ValidationErrors", err)
+			}
+			if len(verrs) != len(tt.wantRules) {
+				t.Fatalf("got %d errors, want %d: %v", len(verrs), len(tt.wantRules), verrs)
+			}
+			for i, verr := range verrs {
+				if verr.Rule != tt.wantRules[i] {
+					t.Errorf("error %d rule = %q, want %q", i, verr.Rule, tt.wantRules[i])
+				}
+			}
+		})
+	}
+
+	t.Run("message explains the condition", func(t *testing.T) {
+		err := validator.Validate(Config{Mode: "cluster"})
+		if err == nil || !strings.Contains(err.Error(), "required when mode is cluster") {
+			t.Errorf("error %v should explain the condition", err)
+		}
+	})
+}
+
+// TestValidator_FieldNames tests how fields are named in errors
+func TestValidator_FieldNames(t *testing.T) {
+	type Item struct {
+		Label string `yaml:"item_label" validate:"required"`
+	}
+
+	type Config struct {
+		JSONName  string `json:"json_name,omitempty" yaml:"yaml_name" validate:"required"`
+		YAMLName  string `yaml:"yaml_only" validate:"required"`
+		Skipped   string `json:"-" validate:"required"`
+		Untagged  string `validate:"required"`
+		ItemsList []Item `json:"items" validate:"dive"`
+	}
+
+	config := Config{ItemsList: []Item{{}}}
+
+	tests := []struct {
+		name      string
+		validator *Validator
+		want      []string
+	}{
+		{
+			name:      "tag names",
+			validator: NewValidator(),
+			want:      []string{"json_name", "yaml_only", "Skipped", "Untagged", "items[0].item_label"},
+		},
+		{
+			name:      "Go names",
+			validator: NewValidator(WithGoFieldNames()),
+			want:      []string{"JSONName", "YAMLName", "Skipped", "Untagged", "ItemsList[0].Label"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verrs, ok := tt.validator.Validate(config).(ValidationErrors)
+			if !ok {
+				t.Fatal("expected ValidationErrors")
+			}
+
+			var got []string
+			for _, verr := range verrs {
+				got = append(got, verr.Field)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("fields = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidator_StopOnFirst(t *testing.T) {
+	type Item struct {
+		Name string `validate:"required"`
+	}
+
+	type Nested struct {
+		Host string `validate:"required"`
+		Port int    `validate:"min=1"`
+	}
+
+	type Config struct {
+		Name   string   `validate:"required,min=3"`
+		Tags   []string `validate:"dive,required"`
+		Items  []Item   `validate:"dive"`
+		Nested Nested
+	}
+
+	tests := []struct {
+		name      string
+		config    Config
+		wantField string
+		wantAll   int
+	}{
+		{
+			name:      "first rule of a field",
+			config:    Config{Tags: []string{""}, Items: []Item{{}}},
+			wantField: "Name",
+			wantAll:   6,
+		},
+		{
+			name:      "dive element",
+			config:    Config{Name: "app", Tags: []string{"", ""}, Nested: Nested{Host: "db", Port: 5432}},
+			wantField: "Tags[0]",
+			wantAll:   2,
+		},
+		{
+			name:      "struct element",
+			config:    Config{Name: "app", Items: []Item{{}, {}}, Nested: Nested{Host: "db", Port: 5432}},
+			wantField: "Items[0].Name",
+			wantAll:   2,
+		},
+		{
+			name:      "nested struct",
+			config:    Config{Name: "app"},
+			wantField: "Nested.Host",
+			wantAll:   2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			all, ok := NewValidator().Validate(tt.config).(ValidationErrors)
+			if !ok {
+				t.Fatal("expected ValidationErrors")
+			}
+			if len(all) != tt.wantAll {
+				t.Errorf("default validator returned %d errors, want %d: %v", len(all), tt.wantAll, all)
+			}
+
+			first, ok := NewValidator(WithStopOnFirst()).Validate(tt.config).(ValidationErrors)
+			if !ok {
+				t.Fatal("expected ValidationErrors")
+			}
+			if len(first) != 1 {
+				t.Fatalf("StopOnFirst returned %d errors, want 1: %v", len(first), first)
+			}
+			if first[0].Field != tt.wantField {
+				t.Errorf("Field = %q, want %q", first[0].Field, tt.wantField)
+			}
+		})
+	}
+
+	if err := NewValidator(WithStopOnFirst()).Validate(Config{Name: "app", Nested: Nested{Host: "db", Port: 1}}); err != nil {
+		t.Errorf("unexpected error for valid config: %v", err)
+	}
+}
+
 // TestIsZeroValue tests the isZeroValue helper function indirectly
 func TestIsZeroValue_ThroughValidation(t *testing.T) {
 	type Config struct {
@@ -595,6 +1307,67 @@ func TestIsZeroValue_ThroughValidation(t *testing.T) {
 	}
 }
 
+func TestValidateWithDefaults_Nested(t *testing.T) {
+	type Address struct {
+		Street string `validate:"required"`
+		Zip    string `validate:"required"`
+	}
+
+	type Config struct {
+		Name    string `validate:"required"`
+		Address Address
+		Billing *Address
+	}
+
+	defaults := Config{
+		Name:    "default-name",
+		Address: Address{Street: "Main St", Zip: "12345"},
+		Billing: &Address{Street: "Billing St", Zip: "99999"},
+	}
+
+	t.Run("partial nested struct", func(t *testing.T) {
+		config := Config{Name: "app", Address: Address{Street: "Elm St"}}
+		if err := ValidateWithDefaults(&config, defaults); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if config.Address.Street != "Elm St" {
+			t.Errorf("Address.Street = %q, want preserved value %q", config.Address.Street, "Elm St")
+		}
+		if config.Address.Zip != "12345" {
+			t.Errorf("Address.Zip = %q, want default %q", config.Address.Zip, "12345")
+		}
+	})
+
+	t.Run("partial pointer to struct", func(t *testing.T) {
+		config := Config{Name: "app", Address: Address{Street: "Elm St"}, Billing: &Address{Zip: "11111"}}
+		if err := ValidateWithDefaults(&config, defaults); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if config.Billing.Street != "Billing St" {
+			t.Errorf("Billing.Street = %q, want default %q", config.Billing.Street, "Billing St")
+		}
+		if config.Billing.Zip != "11111" {
+			t.Errorf("Billing.Zip = %q, want preserved value %q", config.Billing.Zip, "11111")
+		}
+	})
+
+	t.Run("nil pointer to struct", func(t *testing.T) {
+		config := Config{}
+		if err := ValidateWithDefaults(&config, defaults); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if config.Billing == nil {
+			t.Fatal("Billing should be allocated from the default")
+		}
+		if config.Billing == defaults.Billing {
+			t.Error("Billing should not share the default's struct")
+		}
+		if *config.Billing != *defaults.Billing {
+			t.Errorf("Billing = %+v, want %+v", *config.Billing, *defaults.Billing)
+		}
+	})
+}
+
 // TestApplyDefaults tests the applyDefaults function with various types
 // NOTE: ValidateWithDefaults only applies defaults when validation fails
 func TestApplyDefaults_AllTypes(t *testing.T) {
@@ -738,3 +1511,143 @@ func TestValidationError_SingleError(t *testing.T) {
 		t.Errorf("Error should contain message, got: %s", errStr)
 	}
 }
+
+// rangeConfig uses a custom ValidateConfig method for a cross-field constraint
+type rangeConfig struct {
+	Name string `validate:"required"`
+	Min  int    `validate:"min=0"`
+	Max  int
+}
+
+func (c rangeConfig) ValidateConfig() error {
+	var errs ValidationErrors
+	if c.Min > c.Max {
+		errs.Add("Min", "lte_max", "min must not exceed max", c.Min)
+	}
+	return errs.ErrorOrNil()
+}
+
+// poolConfig uses a pointer receiver and returns a single error
+type poolConfig struct {
+	Size int `validate:"max=10"`
+}
+
+func (c *poolConfig) ValidateConfig() error {
+	if c.Size%2 != 0 {
+		return NewValidationError("Size", "even", "size must be even", c.Size)
+	}
+	return nil
+}
+
+// serverConfig nests custom-validated structs
+type serverConfig struct {
+	Host  string `validate:"required"`
+	Range rangeConfig
+	Pool  poolConfig
+}
+
+func TestValidationErrors_Add(t *testing.T) {
+	var errs ValidationErrors
+	if errs.ErrorOrNil() != nil {
+		t.Fatal("empty ValidationErrors.ErrorOrNil() should be nil")
+	}
+
+	errs.Add("Port", "range", "port out of range", 70000)
+	errs.Add("Host", "required", "host is required", "")
+
+	if len(errs) != 2 {
+		t.Fatalf("expected 2 errors, got %d", len(errs))
+	}
+	if errs[0].Field != "Port" || errs[0].Rule != "range" || errs[0].Value != 70000 {
+		t.Errorf("unexpected first error: %+v", errs[0])
+	}
+	if errs.ErrorOrNil() == nil {
+		t.Error("non-empty ValidationErrors.ErrorOrNil() should not be nil")
+	}
+}
+
+// selfValidatingConfig follows the common pattern of a Validate method that
+// runs the tag validator on its own receiver
+type selfValidatingConfig struct {
+	Name string `validate:"required"`
+}
+
+func (c *selfValidatingConfig) Validate() error {
+	return NewValidator().Validate(c)
+}
+
+func TestValidator_CustomValidate(t *testing.T) {
+	validator := NewValidator()
+
+	t.Run("Validate methods are not called implicitly", func(t *testing.T) {
+		cfg := &selfValidatingConfig{}
+		err := cfg.Validate()
+		verrs, ok := err.(ValidationErrors)
+		if !ok || len(verrs) != 1 || verrs[0].Field != "Name" {
+			t.Errorf("Validate() = %v, want a single Name error", err)
+		}
+	})
+
+	t.Run("tag and custom errors are combined", func(t *testing.T) {
+		err := validator.Validate(rangeConfig{Min: 5, Max: 1})
+		verrs, ok := err.(ValidationErrors)
+		if !ok {
+			t.Fatalf("expected ValidationErrors, got %T", err)
+		}
+
+		got := map[string]string{}
+		for _, e := range verrs {
+			got[e.Field] = e.Rule
+		}
+		want := map[string]string{"Name": "required", "Min": "lte_max"}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("errors = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("valid config passes", func(t *testing.T) {
+		if err := validator.Validate(rangeConfig{Name: "ok", Min: 1, Max: 2}); err != nil {
+			t.Errorf("expected no error, got %v", err)
+		}
+	})
+
+	t.Run("nested custom errors are prefixed", func(t *testing.T) {
+		err := validator.Validate(&serverConfig{
+			Range: rangeConfig{Name: "r", Min: 3, Max: 1},
+			Pool:  poolConfig{Size: 11},
+		})
+		verrs, ok := err.(ValidationErrors)
+		if !ok {
+			t.Fatalf("expected ValidationErrors, got %T", err)
+		}
+
+		got := map[string]string{}
+		for _, e := range verrs {
+			got[e.Field] = e.Rule
+		}
+		want := map[string]string{
+			"Host":      "required",
+			"Range.Min": "lte_max",
+			"Pool.Size": "max=10",
+		}
+		// Pool.Size has both a tag error and a custom error
+		if len(verrs) != 4 {
+			t.Errorf("expected 4 errors, got %d: %v", len(verrs), verrs)
+		}
+		for field, rule := range want {
+			if got[field] == "" {
+				t.Errorf("missing error for %s (%s)", field, rule)
+			}
+		}
+
+		var sawEven bool
+		for _, e := range verrs {
+			if e.Field == "Pool.Size" && e.Rule == "even" {
+				sawEven = true
+			}
+		}
+		if !sawEven {
+			t.Error("expected custom pointer-receiver error for Pool.Size")
+		}
+	})
+}
@@ -1,6 +1,8 @@
 package errors
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -15,6 +17,23 @@ type Handler struct {
 	Verbose     bool
 	NoColor     bool
 	ShowContext bool
+	JSON        bool // Emit errors as a JSON object for machine consumers
+}
+
+// JSONError is the shape of an error emitted in JSON mode:
+// {"error": {"type": ..., "message": ..., ...}}
+type JSONError struct {
+	Error JSONErrorDetail `json:"error"`
+}
+
+// JSONErrorDetail describes an error in JSON mode
+type JSONErrorDetail struct {
+	Type        ErrorType         `json:"type"`
+	Message     string            `json:"message"`
+	Cause       string            `json:"cause,omitempty"`
+	Suggestions []string          `json:"suggestions,omitempty"`
+	Context     map[string]string `json:"context,omitempty"`
+	ExitCode    int               `json:"exit_code"`
 }
 
 // DefaultHandler creates a handler with default settings
@@ -33,12 +52,16 @@ func (h *Handler) Handle(err error) int {
 		return 0
 	}
 
+	if h.JSON {
+		return h.displayJSON(err)
+	}
+
 	// Check if it's a GlideError
 	glideErr, ok := err.(*GlideError)
 	if !ok {
 		// Handle as generic error
 		h.displayGenericError(err)
-		return 1
+		return exitCodeOf(err)
 	}
 
 	// Display the error
@@ -61,6 +84,53 @@ func (h *Handler) Handle(err error) int {
 	return 1
 }
 
+// displayJSON writes err as a JSONError and returns its exit code
+func (h *Handler) displayJSON(err error) int {
+	detail := JSONErrorDetail{
+		Type:     TypeUnknown,
+		Message:  err.Error(),
+		ExitCode: exitCodeOf(err),
+	}
+
+	if glideErr, ok := err.(*GlideError); ok {
+		detail.Type = glideErr.Type
+		detail.Message = glideErr.Message
+		detail.Suggestions = glideErr.Suggestions
+		detail.Context = glideErr.Context
+		if glideErr.Err != nil {
+			detail.Cause = glideErr.Err.Error()
+		}
+		if glideErr.Code > 0 {
+			detail.ExitCode = glideErr.Code
+		}
+	}
+
+	data, marshalErr := json.MarshalIndent(JSONError{Error: detail}, "", "  ")
+	if marshalErr != nil {
+		h.displayGenericError(err)
+		return detail.ExitCode
+	}
+	fmt.Fprintln(h.Writer, string(data))
+
+	return detail.ExitCode
+}
+
+// exitCoder is implemented by errors that carry the exit code of a failed
+// command, such as shell.ExitError
+type exitCoder interface {
+	Code() int
+}
+
+// exitCodeOf returns the exit code carried by err or any error it wraps,
+// defaulting to 1
+func exitCodeOf(err error) int {
+	var coder exitCoder
+	if errors.As(err, &coder) && coder.Code() > 0 {
+		return coder.Code()
+	}
+	return 1
+}
+
 // displayError shows the main error message
 func (h *Handler) displayError(err *GlideError) {
 	icon := h.getErrorIcon(err.Type)
@@ -236,6 +306,13 @@ func PrintVerbose(err error) int {
 	return handler.Handle(err)
 }
 
+// PrintJSON handles an error with the default handler, emitting it as JSON
+func PrintJSON(err error) int {
+	handler := DefaultHandler()
+	handler.JSON = true
+	return handler.Handle(err)
+}
+
 // Exit handles an error and exits with the appropriate code
 func Exit(err error) {
 	os.Exit(Print(err))
@@ -2,11 +2,13 @@ package errors
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestDefaultHandler(t *testing.T) {
@@ -131,6 +133,37 @@ func TestHandler_HandleNoContextWhenNotVerbose(t *testing.T) {
 	assert.NotContains(t, output, "Context:")
 }
 
+func TestHandler_HandleJSON(t *testing.T) {
+	buf := &bytes.Buffer{}
+	handler := &Handler{Writer: buf, JSON: true}
+
+	err := NewUserError("no wait condition given", "Specify --tcp")
+	exitCode := handler.Handle(err)
+
+	assert.Equal(t, 64, exitCode)
+
+	var decoded JSONError
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	assert.Equal(t, "no wait condition given", decoded.Error.Message)
+	assert.Equal(t, []string{"Specify --tcp"}, decoded.Error.Suggestions)
+	assert.Equal(t, 64, decoded.Error.ExitCode)
+	assert.NotContains(t, buf.String(), "✗")
+}
+
+func TestHandler_HandleJSONGenericError(t *testing.T) {
+	buf := &bytes.Buffer{}
+	handler := &Handler{Writer: buf, JSON: true}
+
+	exitCode := handler.Handle(fmt.Errorf("something went wrong"))
+
+	assert.Equal(t, 1, exitCode)
+
+	var decoded map[string]map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	assert.Equal(t, "something went wrong", decoded["error"]["message"])
+	assert.Equal(t, "unknown", decoded["error"]["type"])
+}
+
 func TestHandler_GetErrorIcon(t *testing.T) {
 	handler := DefaultHandler()
 
@@ -370,6 +403,27 @@ func TestHandler_ExitCodes(t *testing.T) {
 	}
 }
 
+// commandExitError mimics errors carrying a command's exit code
+type commandExitError struct{ code int }
+
+func (e commandExitError) Error() string { return fmt.Sprintf("exit code %d", e.code) }
+func (e commandExitError) Code() int     { return e.code }
+
+func TestHandler_GenericErrorExitCodes(t *testing.T) {
+	err := fmt.Errorf("up failed: %w", commandExitError{code: 3})
+
+	handler := &Handler{Writer: &bytes.Buffer{}, NoColor: true}
+	assert.Equal(t, 3, handler.Handle(err))
+
+	buf := &bytes.Buffer{}
+	handler = &Handler{Writer: buf, JSON: true}
+	assert.Equal(t, 3, handler.Handle(err))
+	assert.Contains(t, buf.String(), `"exit_code": 3`)
+
+	handler = &Handler{Writer: &bytes.Buffer{}, NoColor: true}
+	assert.Equal(t, 1, handler.Handle(commandExitError{code: 0}), "zero codes fall back to 1")
+}
+
 func TestHandler_DisplayGenericError(t *testing.T) {
 	buf := &bytes.Buffer{}
 	handler := &Handler{
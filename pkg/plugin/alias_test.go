@@ -270,3 +270,42 @@ func TestGlobalRegistry_WithAliases(t *testing.T) {
 	assert.True(t, ok)
 	assert.Equal(t, "testing", canonical)
 }
+
+func TestRegistry_Unregister(t *testing.T) {
+	registry := NewRegistry()
+
+	err := registry.RegisterPlugin(&MockAliasPlugin{name: "database", aliases: []string{"db", "d"}})
+	assert.NoError(t, err)
+
+	// Unregistering removes the plugin and every alias
+	err = registry.Unregister("database")
+	assert.NoError(t, err)
+	assert.False(t, registry.Has("database"))
+	assert.False(t, registry.IsAlias("db"))
+	assert.False(t, registry.IsAlias("d"))
+
+	// A fresh instance can take the name and aliases again
+	err = registry.RegisterPlugin(&MockAliasPlugin{name: "database", aliases: []string{"db"}})
+	assert.NoError(t, err)
+
+	// Unregistering by alias removes the plugin it points to
+	err = registry.Unregister("db")
+	assert.NoError(t, err)
+	assert.False(t, registry.Has("database"))
+
+	// Unknown names are an error
+	err = registry.Unregister("database")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "not registered")
+}
+
+func TestUnregister_Global(t *testing.T) {
+	err := Register(&MockAliasPlugin{name: "unregister-global-test", aliases: []string{"ugt"}})
+	assert.NoError(t, err)
+
+	err = Unregister("unregister-global-test")
+	assert.NoError(t, err)
+
+	_, exists := Get("ugt")
+	assert.False(t, exists)
+}
@@ -0,0 +1,84 @@
+package plugin
+
+import (
+	"github.com/glide-cli/glide/v3/pkg/plugin/sdk"
+)
+
+// PluginCommand is a command definition together with the plugin that owns it
+type PluginCommand struct {
+	// Plugin is the name of the plugin providing the command
+	Plugin string
+
+	// Path is the full command path below the root, e.g. "docker compose ps"
+	Path string
+
+	// Definition is the command as provided by the plugin
+	Definition *sdk.PluginCommandDefinition
+}
+
+// AllCommands returns every command provided by enabled plugins implementing
+// sdk.CommandProvider, with subcommands flattened and addressed by their full
+// path. Plugins are visited in name order and commands in the order each
+// plugin provides them, parents before their subcommands.
+func (r *Registry) AllCommands() []PluginCommand {
+	var commands []PluginCommand
+
+	for _, p := range r.Enabled() {
+		name := p.Name()
+		provider, ok := p.(sdk.CommandProvider)
+		if !ok {
+			continue
+		}
+
+		for _, def := range provider.ProvideCommands() {
+			commands = appendPluginCommands(commands, name, "", def)
+		}
+	}
+
+	return commands
+}
+
+// appendPluginCommands appends def and its subcommands under parentPath
+func appendPluginCommands(commands []PluginCommand, pluginName, parentPath string, def *sdk.PluginCommandDefinition) []PluginCommand {
+	if def == nil {
+		return commands
+	}
+
+	path := def.Name
+	if parentPath != "" {
+		path = parentPath + " " + def.Name
+	}
+
+	commands = append(commands, PluginCommand{
+		Plugin:     pluginName,
+		Path:       path,
+		Definition: def,
+	})
+
+	for _, sub := range def.Subcommands {
+		commands = appendPluginCommands(commands, pluginName, path, sub)
+	}
+
+	return commands
+}
+
+// AllCommands returns every plugin-provided command from the global registry
+func AllCommands() []PluginCommand {
+	return globalRegistry.AllCommands()
+}
+
+// validateCommandDefinitions validates the definitions of a plugin that
+// implements sdk.CommandProvider. Other plugins are always valid.
+func validateCommandDefinitions(p Plugin) error {
+	provider, ok := p.(sdk.CommandProvider)
+	if !ok {
+		return nil
+	}
+
+	for _, def := range provider.ProvideCommands() {
+		if err := def.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
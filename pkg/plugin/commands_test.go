@@ -0,0 +1,113 @@
+package plugin_test
+
+import (
+	"testing"
+
+	"github.com/glide-cli/glide/v3/pkg/plugin"
+	"github.com/glide-cli/glide/v3/pkg/plugin/plugintest"
+	"github.com/glide-cli/glide/v3/pkg/plugin/sdk"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// commandPlugin is a mock plugin that also provides command definitions
+type commandPlugin struct {
+	*plugintest.MockPlugin
+	commands []*sdk.PluginCommandDefinition
+}
+
+func (p *commandPlugin) ProvideCommands() []*sdk.PluginCommandDefinition {
+	return p.commands
+}
+
+func TestRegistryAllCommands(t *testing.T) {
+	t.Run("empty registry", func(t *testing.T) {
+		reg := plugin.NewRegistry()
+		assert.Empty(t, reg.AllCommands())
+	})
+
+	t.Run("flattens subcommands with full paths", func(t *testing.T) {
+		reg := plugin.NewRegistry()
+
+		docker := &commandPlugin{
+			MockPlugin: plugintest.NewMockPlugin("docker"),
+			commands: []*sdk.PluginCommandDefinition{
+				{
+					Name: "compose",
+					Subcommands: []*sdk.PluginCommandDefinition{
+						{Name: "up"},
+						{Name: "ps"},
+					},
+				},
+				{Name: "shell"},
+			},
+		}
+		node := &commandPlugin{
+			MockPlugin: plugintest.NewMockPlugin("node"),
+			commands: []*sdk.PluginCommandDefinition{
+				{Name: "npm"},
+			},
+		}
+
+		require.NoError(t, reg.RegisterPlugin(node))
+		require.NoError(t, reg.RegisterPlugin(docker))
+		// Plugins without commands are skipped
+		require.NoError(t, reg.RegisterPlugin(plugintest.NewMockPlugin("plain")))
+
+		commands := reg.AllCommands()
+		require.Len(t, commands, 5)
+
+		var got [][2]string
+		for _, c := range commands {
+			got = append(got, [2]string{c.Plugin, c.Path})
+		}
+		assert.Equal(t, [][2]string{
+			{"docker", "compose"},
+			{"docker", "compose up"},
+			{"docker", "compose ps"},
+			{"docker", "shell"},
+			{"node", "npm"},
+		}, got)
+
+		assert.Same(t, docker.commands[0].Subcommands[1], commands[2].Definition)
+
+		reg.SetDisabled([]string{"node"})
+		for _, c := range reg.AllCommands() {
+			assert.NotEqual(t, "node", c.Plugin, "disabled plugins are left out")
+		}
+	})
+}
+
+func TestLoadAllValidatesCommandDefinitions(t *testing.T) {
+	reg := plugin.NewRegistry()
+
+	bad := &commandPlugin{
+		MockPlugin: plugintest.NewMockPlugin("docker"),
+		commands: []*sdk.PluginCommandDefinition{
+			{
+				Name: "compose",
+				Use:  "compose",
+				Subcommands: []*sdk.PluginCommandDefinition{
+					{Name: "up", Use: "up", Flags: []sdk.FlagDefinition{{Name: "wait", Type: "complex128"}}},
+				},
+			},
+		},
+	}
+	good := &commandPlugin{
+		MockPlugin: plugintest.NewMockPlugin("node"),
+		commands:   []*sdk.PluginCommandDefinition{{Name: "npm", Use: "npm"}},
+	}
+	require.NoError(t, reg.RegisterPlugin(bad))
+	require.NoError(t, reg.RegisterPlugin(good))
+
+	result, err := reg.LoadAll(&cobra.Command{Use: "test"})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"node"}, result.Loaded)
+	require.Len(t, result.Failed, 1)
+	assert.Equal(t, "docker", result.Failed[0].Name)
+	assert.ErrorIs(t, result.Failed[0].Error, sdk.ErrInvalidCommandDefinition)
+	assert.Contains(t, result.Failed[0].Error.Error(), `command "compose up": Flags[0].Type "complex128"`)
+	assert.False(t, bad.Registered, "invalid plugin must not register commands")
+}
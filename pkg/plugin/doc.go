@@ -27,6 +27,15 @@
 //   - .glide/plugins/       (project plugins)
 //   - Parent directory .glide/plugins/ (inherited)
 //
+// Go plugins (.so files built with -buildmode=plugin) in ~/.glide/plugins/
+// are instead loaded in-process by LoadSharedPlugins. Each must export
+// NewPlugin as a func() Plugin and be built with the same Go and glide
+// versions as the glide binary.
+//
+// Executables listed under plugins.external in the config are run as
+// ExternalPlugin subprocesses that speak JSON-RPC on stdin and stdout. They
+// are usually written with RunExternal and do not share glide's build.
+//
 // # Quick Start
 //
 // Build a plugin using SDK v2:
@@ -0,0 +1,541 @@
+package plugin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/rpc"
+	"net/rpc/jsonrpc"
+	"os"
+	"os/exec"
+	"strconv"
+	"sync"
+	"time"
+
+	glideErrors "github.com/glide-cli/glide/v3/pkg/errors"
+	"github.com/glide-cli/glide/v3/pkg/logging"
+	"github.com/glide-cli/glide/v3/pkg/plugin/sdk"
+	"github.com/spf13/cobra"
+)
+
+// ExternalProtocolVersion is the version of the protocol spoken between glide
+// and external plugin executables
+const ExternalProtocolVersion = 1
+
+// ExternalPluginEnv is set to ExternalProtocolVersion in the environment of an
+// external plugin executable started to serve RPC. RunExternal checks it to
+// decide between serving and running a command.
+const ExternalPluginEnv = "GLIDE_EXTERNAL_PLUGIN"
+
+// DefaultHandshakeTimeout bounds how long an external plugin may take to
+// answer the handshake
+const DefaultHandshakeTimeout = 5 * time.Second
+
+// externalStopTimeout is how long a stopping external plugin may take to exit
+// after its stdin closes before it is killed
+const externalStopTimeout = 2 * time.Second
+
+// externalService is the RPC service name external plugins serve
+const externalService = "Plugin"
+
+// HandshakeRequest opens the conversation with an external plugin
+type HandshakeRequest struct {
+	ProtocolVersion int
+}
+
+// HandshakeResponse describes an external plugin and the commands it provides
+type HandshakeResponse struct {
+	ProtocolVersion int
+	Name            string
+	Version         string
+	Metadata        PluginMetadata
+	Commands        []CommandInfo
+}
+
+// ExternalPlugin is a Plugin that runs as a separate executable. Start
+// launches the executable and exchanges a handshake with it over JSON-RPC on
+// its stdin and stdout; Configure is then proxied to the process. Each
+// command the plugin provides becomes a cobra command that runs the
+// executable again with the command name and arguments, attached to the
+// terminal.
+//
+// The process is stopped by Shutdown. If glide exits without calling it, the
+// process sees its stdin close and exits on its own.
+//
+// Executables are usually written with RunExternal.
+type ExternalPlugin struct {
+	// Path is the plugin executable
+	Path string
+
+	// Args are passed to the executable before anything else
+	Args []string
+
+	// HandshakeTimeout bounds the handshake; zero means DefaultHandshakeTimeout
+	HandshakeTimeout time.Duration
+
+	mu     sync.Mutex
+	client *rpc.Client
+	proc   *os.Process
+	exited chan struct{} // closed once the process has exited
+	info   HandshakeResponse
+}
+
+// NewExternalPlugin creates an external plugin for the executable at path.
+// Call Start before registering it.
+func NewExternalPlugin(path string, args ...string) *ExternalPlugin {
+	return &ExternalPlugin{
+		Path: path,
+		Args: args,
+	}
+}
+
+// Start launches the executable and performs the handshake. The process is
+// stopped again if the handshake fails or times out.
+func (p *ExternalPlugin) Start(ctx context.Context) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.client != nil {
+		return fmt.Errorf("external plugin %s is already started", p.Path)
+	}
+
+	// Own pipes, so waiting for the process never closes the ends RPC uses
+	stdinR, stdinW, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+	stdoutR, stdoutW, err := os.Pipe()
+	if err != nil {
+		stdinR.Close()
+		stdinW.Close()
+		return err
+	}
+
+	cmd := exec.Command(p.Path, p.Args...)
+	cmd.Env = append(os.Environ(), ExternalPluginEnv+"="+strconv.Itoa(ExternalProtocolVersion))
+	cmd.Stdin = stdinR
+	cmd.Stdout = stdoutW
+	cmd.Stderr = os.Stderr
+
+	err = cmd.Start()
+	stdinR.Close()
+	stdoutW.Close()
+	if err != nil {
+		stdinW.Close()
+		stdoutR.Close()
+		return fmt.Errorf("failed to start external plugin %s: %w", p.Path, err)
+	}
+
+	p.proc = cmd.Process
+	p.exited = make(chan struct{})
+	go func() {
+		_ = cmd.Wait()
+		close(p.exited)
+	}()
+	p.client = jsonrpc.NewClient(&pipeConn{Reader: stdoutR, Writer: stdinW, closers: []io.Closer{stdoutR, stdinW}})
+
+	info, err := p.handshake(ctx)
+	if err != nil {
+		// A plugin that cannot handshake gets no grace period
+		stopNow, cancel := context.WithCancel(context.Background())
+		cancel()
+		p.stopLocked(stopNow)
+		return fmt.Errorf("handshake with external plugin %s failed: %w", p.Path, err)
+	}
+	p.info = info
+
+	logging.Debug("External plugin started", "path", p.Path, "name", info.Name, "pid", p.proc.Pid)
+	return nil
+}
+
+// handshake exchanges protocol versions and fetches the plugin's description
+func (p *ExternalPlugin) handshake(ctx context.Context) (HandshakeResponse, error) {
+	timeout := p.HandshakeTimeout
+	if timeout <= 0 {
+		timeout = DefaultHandshakeTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var resp HandshakeResponse
+	call := p.client.Go(externalService+".Handshake", HandshakeRequest{ProtocolVersion: ExternalProtocolVersion}, &resp, nil)
+
+	select {
+	case <-call.Done:
+		if call.Error != nil {
+			return resp, call.Error
+		}
+	case <-p.exited:
+		return resp, errors.New("process exited during handshake")
+	case <-ctx.Done():
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return resp, fmt.Errorf("no response within %s", timeout)
+		}
+		return resp, ctx.Err()
+	}
+
+	if resp.ProtocolVersion != ExternalProtocolVersion {
+		return resp, fmt.Errorf("protocol version %d is not supported, want %d", resp.ProtocolVersion, ExternalProtocolVersion)
+	}
+	if resp.Name == "" {
+		return resp, errors.New("plugin did not report a name")
+	}
+	return resp, nil
+}
+
+// Name returns the name the plugin reported in the handshake
+func (p *ExternalPlugin) Name() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.info.Name
+}
+
+// Version returns the version the plugin reported in the handshake
+func (p *ExternalPlugin) Version() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.info.Version
+}
+
+// Metadata returns the metadata the plugin reported in the handshake
+func (p *ExternalPlugin) Metadata() PluginMetadata {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	meta := p.info.Metadata
+	if meta.Name == "" {
+		meta.Name = p.info.Name
+	}
+	if meta.Version == "" {
+		meta.Version = p.info.Version
+	}
+	return meta
+}
+
+// Configure asks the plugin process to configure itself. A plugin that fails
+// to configure is not loaded, so its process is stopped right away.
+func (p *ExternalPlugin) Configure() error {
+	p.mu.Lock()
+	client := p.client
+	p.mu.Unlock()
+
+	if client == nil {
+		return fmt.Errorf("external plugin %s is not running", p.Path)
+	}
+
+	if err := client.Call(externalService+".Configure", struct{}{}, &struct{}{}); err != nil {
+		p.mu.Lock()
+		p.stopLocked(context.Background())
+		p.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// Register adds a command for every command the plugin reported. Commands
+// that already exist are left alone.
+func (p *ExternalPlugin) Register(root *cobra.Command) error {
+	p.mu.Lock()
+	info := p.info
+	p.mu.Unlock()
+
+	existing := make(map[string]bool)
+	for _, cmd := range root.Commands() {
+		existing[cmd.Name()] = true
+	}
+
+	for _, command := range info.Commands {
+		if existing[command.Name] {
+			logging.Warn("External plugin command conflicts with an existing command, skipping",
+				"plugin", info.Name, "command", command.Name)
+			continue
+		}
+		root.AddCommand(p.command(info.Name, command))
+	}
+	return nil
+}
+
+// command synthesizes the cobra command that runs one plugin command
+func (p *ExternalPlugin) command(pluginName string, command CommandInfo) *cobra.Command {
+	category := command.Category
+	if category == "" {
+		category = "plugin"
+	}
+
+	return &cobra.Command{
+		Use:     command.Name,
+		Short:   command.Description,
+		Aliases: command.Aliases,
+		Annotations: map[string]string{
+			"category": category,
+			"plugin":   pluginName,
+		},
+		// The plugin parses its own flags
+		DisableFlagParsing: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+
+			argv := append(append(append([]string(nil), p.Args...), command.Name), args...)
+			run := exec.CommandContext(ctx, p.Path, argv...)
+			run.Stdin = cmd.InOrStdin()
+			run.Stdout = cmd.OutOrStdout()
+			run.Stderr = cmd.ErrOrStderr()
+
+			if err := run.Run(); err != nil {
+				var exitErr *exec.ExitError
+				if errors.As(err, &exitErr) {
+					return glideErrors.NewCommandError(command.Name, exitErr.ExitCode())
+				}
+				return fmt.Errorf("failed to run external plugin %s: %w", p.Path, err)
+			}
+			return nil
+		},
+	}
+}
+
+// running reports whether the plugin process has been started and not
+// stopped
+func (p *ExternalPlugin) running() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.client != nil
+}
+
+// Shutdown stops the plugin process. It closes the process's stdin and kills
+// the process if it has not exited once ctx is done or a short grace period
+// has passed.
+func (p *ExternalPlugin) Shutdown(ctx context.Context) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.stopLocked(ctx)
+	return nil
+}
+
+// stopLocked stops the plugin process; p.mu must be held
+func (p *ExternalPlugin) stopLocked(ctx context.Context) {
+	if p.client == nil {
+		return
+	}
+
+	// Closing stdin tells a well-behaved plugin to exit
+	_ = p.client.Close()
+	p.client = nil
+
+	grace := time.NewTimer(externalStopTimeout)
+	defer grace.Stop()
+
+	select {
+	case <-p.exited:
+		return
+	case <-ctx.Done():
+	case <-grace.C:
+	}
+
+	logging.Debug("Killing external plugin", "path", p.Path, "pid", p.proc.Pid)
+	_ = p.proc.Kill()
+	<-p.exited
+}
+
+// pipeConn joins the two pipes of a plugin process into one connection
+type pipeConn struct {
+	io.Reader
+	io.Writer
+	closers []io.Closer
+}
+
+func (c *pipeConn) Close() error {
+	var errs []error
+	for _, closer := range c.closers {
+		errs = append(errs, closer.Close())
+	}
+	return errors.Join(errs...)
+}
+
+// externalServer is the RPC service of an external plugin executable
+type externalServer struct {
+	plugin   Plugin
+	commands []CommandInfo
+}
+
+// Handshake describes the plugin
+func (s *externalServer) Handshake(req HandshakeRequest, resp *HandshakeResponse) error {
+	if req.ProtocolVersion != ExternalProtocolVersion {
+		return fmt.Errorf("protocol version %d is not supported, want %d", req.ProtocolVersion, ExternalProtocolVersion)
+	}
+
+	// An external plugin is built separately from the host, so it always
+	// reports the API version it was built against
+	meta := s.plugin.Metadata()
+	if meta.APIVersion == "" {
+		meta.APIVersion = sdk.APIVersion
+	}
+
+	*resp = HandshakeResponse{
+		ProtocolVersion: ExternalProtocolVersion,
+		Name:            s.plugin.Name(),
+		Version:         s.plugin.Version(),
+		Metadata:        meta,
+		Commands:        s.commands,
+	}
+	return nil
+}
+
+// Configure configures the plugin
+func (s *externalServer) Configure(_ struct{}, _ *struct{}) error {
+	return s.plugin.Configure()
+}
+
+// ServeExternal serves p to glide over r and w until r is closed. The
+// commands offered are the top-level commands p.Register adds.
+func ServeExternal(p Plugin, r io.Reader, w io.Writer) error {
+	root := &cobra.Command{Use: p.Name()}
+	if err := p.Register(root); err != nil {
+		return fmt.Errorf("failed to list commands: %w", err)
+	}
+
+	var commands []CommandInfo
+	for _, cmd := range root.Commands() {
+		commands = append(commands, CommandInfo{
+			Name:        cmd.Name(),
+			Category:    cmd.Annotations["category"],
+			Description: cmd.Short,
+			Aliases:     cmd.Aliases,
+		})
+	}
+
+	server := rpc.NewServer()
+	if err := server.RegisterName(externalService, &externalServer{plugin: p, commands: commands}); err != nil {
+		return err
+	}
+	server.ServeCodec(jsonrpc.NewServerCodec(&pipeConn{Reader: r, Writer: w}))
+	return nil
+}
+
+// RunExternal is the main function of an external plugin executable. When
+// glide starts the executable to talk to it, RunExternal serves p on stdin
+// and stdout; stdout must not be written to otherwise. When glide runs one
+// of the plugin's commands, args hold the command name and its arguments and
+// RunExternal configures p and executes the command.
+//
+// Example:
+//
+//	func main() {
+//	    if err := plugin.RunExternal(&MyPlugin{}, os.Args[1:]); err != nil {
+//	        os.Exit(1)
+//	    }
+//	}
+func RunExternal(p Plugin, args []string) error {
+	if os.Getenv(ExternalPluginEnv) != "" {
+		return ServeExternal(p, os.Stdin, os.Stdout)
+	}
+
+	if err := p.Configure(); err != nil {
+		return err
+	}
+
+	root := &cobra.Command{
+		Use:           p.Name(),
+		Version:       p.Version(),
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+	if err := p.Register(root); err != nil {
+		return err
+	}
+	root.SetArgs(args)
+	return root.Execute()
+}
+
+// LoadExternalPlugins starts the external plugin executables at paths and
+// registers them. A plugin that fails to start is reported in the result and
+// does not stop the others from loading.
+func (r *Registry) LoadExternalPlugins(ctx context.Context, paths []string) *PluginLoadResult {
+	result := &PluginLoadResult{
+		Loaded:   make([]string, 0),
+		Failed:   make([]PluginError, 0),
+		Warnings: make([]string, 0),
+	}
+
+	for _, path := range paths {
+		p := NewExternalPlugin(path)
+		if err := r.AddExternalPlugin(ctx, p); err != nil {
+			logging.Warn("External plugin failed to load", "path", path, "error", err)
+			result.Failed = append(result.Failed, PluginError{
+				Name:    path,
+				Error:   err,
+				IsFatal: false,
+			})
+			continue
+		}
+
+		result.Loaded = append(result.Loaded, p.Name())
+	}
+
+	return result
+}
+
+// AddExternalPlugin starts p, unless it is already running, and registers
+// it. The registry stops the process again if the plugin is disabled, once
+// LoadAll skips it, or in ShutdownAll.
+func (r *Registry) AddExternalPlugin(ctx context.Context, p *ExternalPlugin) error {
+	if !p.running() {
+		if err := p.Start(ctx); err != nil {
+			return err
+		}
+	}
+	if err := r.RegisterPlugin(p); err != nil {
+		_ = p.Shutdown(ctx)
+		return err
+	}
+
+	// The name is only known after the handshake; a disabled plugin stays
+	// registered so it can be listed, but its process is not kept around
+	if r.IsDisabled(p.Name()) {
+		logging.Debug("Stopping disabled external plugin", "name", p.Name())
+		return p.Shutdown(ctx)
+	}
+
+	r.mu.Lock()
+	r.external = append(r.external, p.Name())
+	r.mu.Unlock()
+	return nil
+}
+
+// stopIdleExternal stops the processes of external plugins that LoadAll has
+// not started, which ShutdownAll would not reach
+func (r *Registry) stopIdleExternal(ctx context.Context) {
+	r.mu.Lock()
+	started := make(map[string]bool, len(r.started))
+	for _, name := range r.started {
+		started[name] = true
+	}
+	var running, idle []string
+	for _, name := range r.external {
+		if started[name] {
+			running = append(running, name)
+		} else {
+			idle = append(idle, name)
+		}
+	}
+	r.external = running
+	r.mu.Unlock()
+
+	for _, name := range idle {
+		if p, ok := r.Get(name); ok {
+			if external, ok := p.(*ExternalPlugin); ok {
+				logging.Debug("Stopping unused external plugin", "name", name)
+				_ = external.Shutdown(ctx)
+			}
+		}
+	}
+}
+
+// LoadExternalPlugins starts external plugins and registers them with the
+// global registry
+func LoadExternalPlugins(ctx context.Context, paths []string) *PluginLoadResult {
+	return globalRegistry.LoadExternalPlugins(ctx, paths)
+}
@@ -0,0 +1,238 @@
+package plugin_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	glideErrors "github.com/glide-cli/glide/v3/pkg/errors"
+	"github.com/glide-cli/glide/v3/pkg/plugin"
+	"github.com/glide-cli/glide/v3/pkg/plugin/plugintest"
+	"github.com/glide-cli/glide/v3/pkg/plugin/sdk"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// externalHelperEnv selects the behavior of the test binary when it runs as
+// an external plugin
+const externalHelperEnv = "GLIDE_TEST_EXTERNAL_PLUGIN"
+
+// TestExternalPluginHelper is not a real test: external plugin tests start
+// the test binary running only this function, which then acts as a plugin
+// executable.
+func TestExternalPluginHelper(t *testing.T) {
+	mode := os.Getenv(externalHelperEnv)
+	if mode == "" {
+		return
+	}
+
+	// Arguments after "--" are the plugin's own
+	var args []string
+	for i, arg := range os.Args {
+		if arg == "--" {
+			args = os.Args[i+1:]
+			break
+		}
+	}
+
+	switch mode {
+	case "silent":
+		// Never answers the handshake
+		time.Sleep(time.Minute)
+		os.Exit(0)
+	}
+
+	p := plugintest.NewMockPlugin("external-example")
+	p.VersionValue = "2.1.0"
+	p.MetadataValue.Description = "An external plugin"
+	if mode == "misconfigured" {
+		p.ConfigError = errors.New("missing api key")
+	}
+	p.RegisterFunc = func(root *cobra.Command) error {
+		root.AddCommand(&cobra.Command{
+			Use:     "greet",
+			Short:   "Say hello",
+			Aliases: []string{"hi"},
+			RunE: func(cmd *cobra.Command, args []string) error {
+				fmt.Fprintf(cmd.OutOrStdout(), "hello %s\n", strings.Join(args, " "))
+				return nil
+			},
+		})
+		root.AddCommand(&cobra.Command{
+			Use: "fail",
+			Run: func(cmd *cobra.Command, args []string) {
+				os.Exit(3)
+			},
+		})
+		return nil
+	}
+
+	if err := plugin.RunExternal(p, args); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
+
+// newHelperPlugin returns an external plugin backed by the test binary
+func newHelperPlugin(t *testing.T, mode string) *plugin.ExternalPlugin {
+	t.Setenv(externalHelperEnv, mode)
+	return plugin.NewExternalPlugin(os.Args[0], "-test.run=^TestExternalPluginHelper$", "--")
+}
+
+func TestExternalPlugin(t *testing.T) {
+	t.Run("proxies the plugin interface", func(t *testing.T) {
+		p := newHelperPlugin(t, "ok")
+		require.NoError(t, p.Start(context.Background()))
+		defer p.Shutdown(context.Background())
+
+		assert.Equal(t, "external-example", p.Name())
+		assert.Equal(t, "2.1.0", p.Version())
+		assert.Equal(t, "An external plugin", p.Metadata().Description)
+		assert.Equal(t, "external-example", p.Metadata().Name)
+		assert.Equal(t, sdk.APIVersion, p.Metadata().APIVersion)
+		require.NoError(t, p.Configure())
+	})
+
+	t.Run("commands run the executable", func(t *testing.T) {
+		p := newHelperPlugin(t, "ok")
+		require.NoError(t, p.Start(context.Background()))
+		defer p.Shutdown(context.Background())
+
+		root := &cobra.Command{Use: "glide", SilenceErrors: true, SilenceUsage: true}
+		root.AddCommand(&cobra.Command{Use: "fail", Short: "core command"})
+		require.NoError(t, p.Register(root))
+
+		greet, _, err := root.Find([]string{"hi"})
+		require.NoError(t, err)
+		assert.Equal(t, "Say hello", greet.Short)
+		assert.Equal(t, "external-example", greet.Annotations["plugin"])
+
+		// Existing commands win
+		fail, _, err := root.Find([]string{"fail"})
+		require.NoError(t, err)
+		assert.Equal(t, "core command", fail.Short)
+
+		var out bytes.Buffer
+		root.SetOut(&out)
+		root.SetArgs([]string{"greet", "big", "world"})
+		require.NoError(t, root.Execute())
+		assert.Contains(t, out.String(), "hello big world")
+	})
+
+	t.Run("exit codes propagate", func(t *testing.T) {
+		p := newHelperPlugin(t, "ok")
+		require.NoError(t, p.Start(context.Background()))
+		defer p.Shutdown(context.Background())
+
+		root := &cobra.Command{Use: "glide", SilenceErrors: true, SilenceUsage: true}
+		require.NoError(t, p.Register(root))
+
+		root.SetArgs([]string{"fail"})
+		err := root.Execute()
+		var glideErr *glideErrors.GlideError
+		require.ErrorAs(t, err, &glideErr)
+		assert.Equal(t, 3, glideErr.Code)
+	})
+
+	t.Run("handshake times out", func(t *testing.T) {
+		p := newHelperPlugin(t, "silent")
+		p.HandshakeTimeout = 200 * time.Millisecond
+
+		start := time.Now()
+		err := p.Start(context.Background())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "no response within")
+		assert.Less(t, time.Since(start), 2*time.Second)
+	})
+
+	t.Run("configure failure stops the process", func(t *testing.T) {
+		p := newHelperPlugin(t, "misconfigured")
+		require.NoError(t, p.Start(context.Background()))
+
+		err := p.Configure()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "missing api key")
+
+		err = p.Configure()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "not running")
+	})
+
+	t.Run("missing executable", func(t *testing.T) {
+		p := plugin.NewExternalPlugin("/nonexistent/glide-plugin")
+		err := p.Start(context.Background())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to start")
+	})
+}
+
+func TestLoadExternalPlugins(t *testing.T) {
+	t.Setenv(externalHelperEnv, "ok")
+
+	reg := plugin.NewRegistry()
+	result := reg.LoadExternalPlugins(context.Background(), []string{"/nonexistent/glide-plugin"})
+	assert.Empty(t, result.Loaded)
+	require.Len(t, result.Failed, 1)
+	assert.Equal(t, "/nonexistent/glide-plugin", result.Failed[0].Name)
+
+	p := plugin.NewExternalPlugin(os.Args[0], "-test.run=^TestExternalPluginHelper$", "--")
+	require.NoError(t, p.Start(context.Background()))
+	require.NoError(t, reg.RegisterPlugin(p))
+
+	root := &cobra.Command{Use: "glide"}
+	loaded, err := reg.LoadAll(root)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"external-example"}, loaded.Loaded)
+
+	_, _, err = root.Find([]string{"greet"})
+	require.NoError(t, err)
+
+	require.NoError(t, reg.ShutdownAll(context.Background()))
+}
+
+func TestRegistryExternalPluginProcesses(t *testing.T) {
+	// stopped reports whether the plugin's process is no longer running
+	stopped := func(p *plugin.ExternalPlugin) bool {
+		err := p.Configure()
+		return err != nil && strings.Contains(err.Error(), "not running")
+	}
+
+	t.Run("disabled plugins are stopped once named", func(t *testing.T) {
+		reg := plugin.NewRegistry()
+		reg.SetDisabled([]string{"external-example"})
+
+		p := newHelperPlugin(t, "ok")
+		require.NoError(t, reg.AddExternalPlugin(context.Background(), p))
+		assert.True(t, reg.Has("external-example"))
+		assert.True(t, stopped(p))
+	})
+
+	t.Run("plugins LoadAll skips are stopped", func(t *testing.T) {
+		reg := plugin.NewRegistry()
+		p := newHelperPlugin(t, "ok")
+		require.NoError(t, reg.AddExternalPlugin(context.Background(), p))
+		defer p.Shutdown(context.Background())
+
+		reg.SetDisabled([]string{"external-example"})
+		_, err := reg.LoadAll(&cobra.Command{Use: "glide"})
+		require.NoError(t, err)
+		assert.True(t, stopped(p))
+	})
+
+	t.Run("ShutdownAll stops plugins LoadAll never reached", func(t *testing.T) {
+		reg := plugin.NewRegistry()
+		p := newHelperPlugin(t, "ok")
+		require.NoError(t, reg.AddExternalPlugin(context.Background(), p))
+		defer p.Shutdown(context.Background())
+
+		require.NoError(t, reg.ShutdownAll(context.Background()))
+		assert.True(t, stopped(p))
+	})
+}
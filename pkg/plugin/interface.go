@@ -1,6 +1,9 @@
 package plugin
 
 import (
+	"context"
+
+	"github.com/glide-cli/glide/v3/pkg/logging"
 	"github.com/glide-cli/glide/v3/pkg/plugin/sdk"
 	"github.com/spf13/cobra"
 )
@@ -93,6 +96,134 @@ type PluginConfigurable interface {
 	Configure() error
 }
 
+// Validatable is an optional interface for plugins that can check their own
+// prerequisites before being loaded.
+//
+// This is distinct from configuration schema validation: Validate asserts that
+// the plugin is able to function in the current environment (a required
+// binary is installed, a minimum configuration is present, etc.).
+//
+// Registry.LoadAll calls Validate after Configure and before Register. Whether
+// a failure prevents the plugin from loading is controlled by the registry's
+// ValidationMode.
+//
+// Example:
+//
+//	func (p *MyPlugin) Validate() error {
+//	    if _, err := exec.LookPath("kubectl"); err != nil {
+//	        return fmt.Errorf("kubectl not found in PATH")
+//	    }
+//	    return nil
+//	}
+type Validatable interface {
+	// Validate returns an error if the plugin cannot function
+	Validate() error
+}
+
+// LoggerAware is an optional interface for plugins that want their scoped
+// logger before Configure runs.
+//
+// Registry.LoadAll derives the logger from the registry's root logger and tags
+// every record with the plugin's name. Plugin commands can also reach it at
+// run time through sdk.Logger(cmd.Context()).
+//
+// Example:
+//
+//	func (p *MyPlugin) SetLogger(logger *logging.Logger) {
+//	    p.logger = logger
+//	}
+type LoggerAware interface {
+	// SetLogger hands the plugin its scoped logger
+	SetLogger(logger *logging.Logger)
+}
+
+// Initializer is an optional interface for plugins that need to set up
+// resources, such as clients or background checks, while they load.
+//
+// Registry.LoadAll calls Init after Validate and before Register. The context
+// carries the plugin's scoped logger. An error prevents the plugin from
+// loading.
+//
+// Example:
+//
+//	func (p *MyPlugin) Init(ctx context.Context) error {
+//	    client, err := api.Dial(ctx, p.endpoint)
+//	    if err != nil {
+//	        return err
+//	    }
+//	    p.client = client
+//	    return nil
+//	}
+type Initializer interface {
+	// Init sets up the plugin's resources
+	Init(ctx context.Context) error
+}
+
+// Shutdowner is an optional interface for plugins that hold resources which
+// must be released when the CLI exits.
+//
+// Registry.ShutdownAll calls Shutdown, in reverse load order, on every plugin
+// LoadAll got past the Init step, whether or not it implements Initializer.
+//
+// Example:
+//
+//	func (p *MyPlugin) Shutdown(ctx context.Context) error {
+//	    return p.client.Close()
+//	}
+type Shutdowner interface {
+	// Shutdown releases the plugin's resources
+	Shutdown(ctx context.Context) error
+}
+
+// HealthChecker is an optional interface for plugins that can report whether
+// their prerequisites are met, for diagnostics such as a doctor command.
+//
+// Unlike Validate, which gates loading, HealthCheck only reports: it returns
+// one result per prerequisite, each with a message telling the user what is
+// wrong or how to fix it. Registry.HealthCheckAll collects the results of all
+// enabled plugins.
+//
+// Example:
+//
+//	func (p *MyPlugin) HealthCheck(ctx context.Context) []HealthResult {
+//	    if _, err := exec.LookPath("docker"); err != nil {
+//	        return []HealthResult{{Name: "docker binary", Message: "docker not found in PATH"}}
+//	    }
+//	    return []HealthResult{{Name: "docker binary", OK: true}}
+//	}
+type HealthChecker interface {
+	// HealthCheck checks the plugin's prerequisites
+	HealthCheck(ctx context.Context) []HealthResult
+}
+
+// HealthResult is the outcome of a single plugin health check
+type HealthResult struct {
+	// Name identifies what was checked (e.g., "docker daemon")
+	Name string
+
+	// OK reports whether the check passed
+	OK bool
+
+	// Message explains the outcome, or how to fix a failure
+	Message string
+}
+
+// PluginHealth holds the health check results of one plugin
+type PluginHealth struct {
+	Plugin  string
+	Results []HealthResult
+}
+
+// Healthy reports whether all of the plugin's checks passed
+func (h PluginHealth) Healthy() bool {
+	for _, result := range h.Results {
+		if !result.OK {
+			return false
+		}
+	}
+	return true
+}
+
 // Plugin defines the complete interface for Glide extensions.
 //
 // This is a composite interface that combines all plugin sub-interfaces for
@@ -124,6 +255,8 @@ type PluginMetadata struct {
 	BuildTags    []string               // Required build tags
 	ConfigKeys   []string               // Configuration keys used
 	Dependencies []sdk.PluginDependency // Plugin dependencies
+	Priority     int                    // Higher priorities load first; ties load alphabetically
+	APIVersion   string                 // Plugin API version built against (see sdk.APIVersion); empty skips the check
 }
 
 // CommandInfo describes a plugin command
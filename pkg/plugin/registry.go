@@ -1,10 +1,15 @@
 package plugin
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"sort"
 	"strings"
+	"sync"
 
 	"github.com/glide-cli/glide/v3/pkg/logging"
+	"github.com/glide-cli/glide/v3/pkg/plugin/sdk"
 	"github.com/glide-cli/glide/v3/pkg/registry"
 	"github.com/spf13/cobra"
 )
@@ -62,6 +67,29 @@ func (r *PluginLoadResult) ErrorMessage() string {
 	return builder.String()
 }
 
+// ValidationMode controls how LoadAll reacts when a plugin's Validate check fails
+type ValidationMode int
+
+const (
+	// ValidationModeError refuses to load a plugin whose Validate check fails
+	ValidationModeError ValidationMode = iota
+
+	// ValidationModeWarn records a warning and loads the plugin anyway
+	ValidationModeWarn
+)
+
+// String returns the string representation of the validation mode
+func (m ValidationMode) String() string {
+	switch m {
+	case ValidationModeError:
+		return "error"
+	case ValidationModeWarn:
+		return "warn"
+	default:
+		return "unknown"
+	}
+}
+
 // Registry manages plugin registration and lifecycle.
 //
 // The Registry provides a centralized location for plugin management, including
@@ -71,6 +99,14 @@ func (r *PluginLoadResult) ErrorMessage() string {
 // Plugins should register their typed configs using config.Register() in init().
 type Registry struct {
 	*registry.Registry[Plugin]
+
+	mu             sync.RWMutex
+	validationMode ValidationMode
+	loadOrder      []string
+	disabled       []string
+	logger         *logging.Logger
+	started        []string // plugins that passed Init, in load order
+	external       []string // external plugins whose process is running
 }
 
 // global registry instance
@@ -102,11 +138,159 @@ func (r *Registry) RegisterPlugin(p Plugin) error {
 	// Get plugin metadata to register aliases
 	meta := p.Metadata()
 
+	// Refuse plugins built against an incompatible plugin API
+	if err := sdk.CheckAPIVersion(meta.APIVersion); err != nil {
+		return fmt.Errorf("plugin %s: %w", name, err)
+	}
+
 	// Use the generic registry's Register method with aliases
 	return r.Registry.Register(name, p, meta.Aliases...)
 }
 
-// LoadAll registers all plugin commands
+// Unregister removes a plugin and all of its aliases from the registry, so a
+// fresh instance can be registered under the same name. The plugin may be
+// given by name or alias. Commands already added by LoadAll are not removed.
+func (r *Registry) Unregister(name string) error {
+	if !r.Registry.Remove(name) {
+		return fmt.Errorf("plugin %q is not registered", name)
+	}
+	return nil
+}
+
+// SetValidationMode sets how plugin self-check failures are handled during LoadAll
+func (r *Registry) SetValidationMode(mode ValidationMode) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.validationMode = mode
+}
+
+// ValidationMode returns how plugin self-check failures are handled during LoadAll
+func (r *Registry) ValidationMode() ValidationMode {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.validationMode
+}
+
+// SetLoadOrder sets the order in which LoadAll loads plugins. Listed plugins
+// load first, in the given order; the rest follow by priority, then
+// alphabetically. Since a later plugin cannot replace a command an earlier
+// one added, the order decides which plugin wins a conflict. Unknown names
+// are ignored.
+func (r *Registry) SetLoadOrder(names []string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.loadOrder = append([]string(nil), names...)
+}
+
+// SetDisabled sets the plugins LoadAll skips, by name or alias. Disabled
+// plugins register no commands and are left out of Enabled. LoadAll reports
+// names that match no registered plugin as failures.
+func (r *Registry) SetDisabled(names []string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.disabled = append([]string(nil), names...)
+}
+
+// IsDisabled reports whether the named plugin has been disabled
+func (r *Registry) IsDisabled(name string) bool {
+	if canonical, ok := r.ResolveAlias(name); ok {
+		name = canonical
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, disabled := range r.disabled {
+		if canonical, ok := r.ResolveAlias(disabled); ok {
+			disabled = canonical
+		}
+		if disabled == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Enabled returns the registered plugins that have not been disabled
+func (r *Registry) Enabled() []Plugin {
+	var plugins []Plugin
+	for _, name := range r.ListNames() {
+		if r.IsDisabled(name) {
+			continue
+		}
+		if p, ok := r.Get(name); ok {
+			plugins = append(plugins, p)
+		}
+	}
+	return plugins
+}
+
+// SetLogger sets the root logger that plugin loggers are derived from. By
+// default the logging package's default logger is used.
+func (r *Registry) SetLogger(logger *logging.Logger) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.logger = logger
+}
+
+// PluginLogger returns the logger for the named plugin, derived from the
+// registry's root logger and tagged with the plugin's name
+func (r *Registry) PluginLogger(name string) *logging.Logger {
+	r.mu.RLock()
+	base := r.logger
+	r.mu.RUnlock()
+
+	return sdk.NamedLogger(base, name)
+}
+
+// LoadOrder returns the names of the registered plugins in the order LoadAll
+// loads them: plugins named in SetLoadOrder first, then the rest by
+// descending metadata priority and alphabetically within a priority
+func (r *Registry) LoadOrder() []string {
+	r.mu.RLock()
+	configured := r.loadOrder
+	r.mu.RUnlock()
+
+	names := make([]string, 0, r.Count())
+	seen := make(map[string]bool)
+
+	for _, name := range configured {
+		if !r.Has(name) {
+			logging.Debug("Ignoring unknown plugin in load order", "name", name)
+			continue
+		}
+		// Resolve aliases to the canonical plugin name
+		canonical := name
+		if resolved, ok := r.ResolveAlias(name); ok {
+			canonical = resolved
+		}
+		if seen[canonical] {
+			continue
+		}
+		seen[canonical] = true
+		names = append(names, canonical)
+	}
+
+	// The rest load by descending priority; ListNames is sorted, so the
+	// stable sort breaks ties alphabetically
+	var rest []string
+	priorities := make(map[string]int)
+	for _, name := range r.ListNames() {
+		if seen[name] {
+			continue
+		}
+		if p, ok := r.Get(name); ok {
+			priorities[name] = p.Metadata().Priority
+		}
+		rest = append(rest, name)
+	}
+	sort.SliceStable(rest, func(i, j int) bool {
+		return priorities[rest[i]] > priorities[rest[j]]
+	})
+
+	return append(names, rest...)
+}
+
+// LoadAll registers all plugin commands, loading plugins in LoadOrder
 func (r *Registry) LoadAll(root *cobra.Command) (*PluginLoadResult, error) {
 	logging.Debug("Loading all plugins")
 
@@ -119,11 +303,43 @@ func (r *Registry) LoadAll(root *cobra.Command) (*PluginLoadResult, error) {
 	// Track if we encountered any fatal errors
 	var fatalError error
 
-	r.ForEach(func(name string, plugin Plugin) {
+	validationMode := r.ValidationMode()
+
+	// A disabled name that matches no plugin is most likely a typo
+	r.mu.RLock()
+	disabled := r.disabled
+	r.mu.RUnlock()
+	for _, name := range disabled {
+		if !r.Has(name) {
+			result.Failed = append(result.Failed, PluginError{
+				Name:    name,
+				Error:   fmt.Errorf("cannot disable unknown plugin %q", name),
+				IsFatal: false,
+			})
+		}
+	}
+
+	for _, name := range r.LoadOrder() {
+		plugin, ok := r.Get(name)
+		if !ok {
+			continue
+		}
+
+		if r.IsDisabled(name) {
+			logging.Debug("Skipping disabled plugin", "name", name)
+			continue
+		}
+
 		logging.Debug("Loading plugin", "name", name)
 		// If we already have a fatal error, skip remaining plugins
 		if fatalError != nil {
-			return
+			break
+		}
+
+		// Hand the plugin its scoped logger before it does any work
+		logger := r.PluginLogger(name)
+		if aware, ok := plugin.(LoggerAware); ok {
+			aware.SetLogger(logger)
 		}
 
 		// NOTE: Plugin configuration is now handled via pkg/config type-safe registry.
@@ -137,10 +353,64 @@ func (r *Registry) LoadAll(root *cobra.Command) (*PluginLoadResult, error) {
 				Error:   fmt.Errorf("failed to configure: %w", err),
 				IsFatal: false,
 			})
-			return
+			continue
+		}
+
+		// Let the plugin assert that its prerequisites are met
+		if validatable, ok := plugin.(Validatable); ok {
+			if err := validatable.Validate(); err != nil {
+				if validationMode == ValidationModeWarn {
+					logging.Warn("Plugin self-check failed, loading anyway", "name", name, "error", err)
+					result.Warnings = append(result.Warnings, fmt.Sprintf("%s: %v", name, err))
+				} else {
+					logging.Warn("Plugin self-check failed", "name", name, "error", err)
+					result.Failed = append(result.Failed, PluginError{
+						Name:    name,
+						Error:   fmt.Errorf("failed validation: %w", err),
+						IsFatal: false,
+					})
+					continue
+				}
+			}
+		}
+
+		// Reject malformed command definitions before anything is added to the tree
+		if err := validateCommandDefinitions(plugin); err != nil {
+			logging.Warn("Plugin command definitions are invalid", "name", name, "error", err)
+			result.Failed = append(result.Failed, PluginError{
+				Name:    name,
+				Error:   fmt.Errorf("failed to validate commands: %w", err),
+				IsFatal: false,
+			})
+			continue
+		}
+
+		// Let the plugin set up its resources; from here on it is shut down
+		// by ShutdownAll even if registration fails
+		if initializer, ok := plugin.(Initializer); ok {
+			ctx := root.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+			if err := initializer.Init(sdk.WithLogger(ctx, logger)); err != nil {
+				logging.Warn("Plugin initialization failed", "name", name, "error", err)
+				result.Failed = append(result.Failed, PluginError{
+					Name:    name,
+					Error:   fmt.Errorf("failed to initialize: %w", err),
+					IsFatal: false,
+				})
+				continue
+			}
 		}
+		r.mu.Lock()
+		r.started = append(r.started, name)
+		r.mu.Unlock()
 
 		// Register plugin commands
+		existing := make(map[*cobra.Command]bool)
+		for _, cmd := range root.Commands() {
+			existing[cmd] = true
+		}
 		if err := plugin.Register(root); err != nil {
 			// Command registration errors are typically non-fatal
 			// Log and continue with other plugins
@@ -150,13 +420,23 @@ func (r *Registry) LoadAll(root *cobra.Command) (*PluginLoadResult, error) {
 				Error:   fmt.Errorf("failed to register commands: %w", err),
 				IsFatal: false,
 			})
-			return
+			continue
+		}
+
+		// Let the plugin's commands find its logger through sdk.Logger
+		for _, cmd := range root.Commands() {
+			if !existing[cmd] {
+				scopeCommandLogger(cmd, logger)
+			}
 		}
 
 		// Successfully loaded
 		logging.Info("Plugin loaded successfully", "name", name)
 		result.Loaded = append(result.Loaded, name)
-	})
+	}
+
+	// Processes of external plugins that did not load serve no purpose
+	r.stopIdleExternal(context.Background())
 
 	// Return fatal error if encountered
 	if fatalError != nil {
@@ -168,6 +448,110 @@ func (r *Registry) LoadAll(root *cobra.Command) (*PluginLoadResult, error) {
 	return result, nil
 }
 
+// ShutdownAll calls Shutdown on every plugin LoadAll started, in reverse load
+// order. Every plugin is shut down even if an earlier one fails; the failures
+// are returned together.
+func (r *Registry) ShutdownAll(ctx context.Context) error {
+	r.mu.Lock()
+	started := r.started
+	r.started = nil
+	r.mu.Unlock()
+
+	var errs []error
+	for i := len(started) - 1; i >= 0; i-- {
+		name := started[i]
+		p, ok := r.Get(name)
+		if !ok {
+			continue
+		}
+		shutdowner, ok := p.(Shutdowner)
+		if !ok {
+			continue
+		}
+
+		logging.Debug("Shutting down plugin", "name", name)
+		if err := shutdowner.Shutdown(sdk.WithLogger(ctx, r.PluginLogger(name))); err != nil {
+			logging.Warn("Plugin shutdown failed", "name", name, "error", err)
+			errs = append(errs, fmt.Errorf("plugin %s: %w", name, err))
+		}
+	}
+
+	// External plugins LoadAll never reached still have a process
+	r.stopIdleExternal(ctx)
+
+	return errors.Join(errs...)
+}
+
+// HealthCheckAll runs the health checks of every enabled plugin that
+// implements HealthChecker, in name order. Plugins without health checks are
+// left out.
+func (r *Registry) HealthCheckAll(ctx context.Context) []PluginHealth {
+	var report []PluginHealth
+	for _, p := range r.Enabled() {
+		checker, ok := p.(HealthChecker)
+		if !ok {
+			continue
+		}
+
+		name := p.Name()
+		logging.Debug("Checking plugin health", "name", name)
+		report = append(report, PluginHealth{
+			Plugin:  name,
+			Results: checker.HealthCheck(sdk.WithLogger(ctx, r.PluginLogger(name))),
+		})
+	}
+	return report
+}
+
+// scopeCommandLogger makes logger available through sdk.Logger to cmd and
+// its subcommands. The logger is attached to the execution context when the
+// command runs, in a PersistentPreRunE, so contexts passed to ExecuteContext
+// still reach plugin commands.
+func scopeCommandLogger(cmd *cobra.Command, logger *logging.Logger) {
+	hook, plainHook := cmd.PersistentPreRunE, cmd.PersistentPreRun
+	cmd.PersistentPreRun = nil
+	cmd.PersistentPreRunE = func(c *cobra.Command, args []string) error {
+		ctx := c.Context()
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		c.SetContext(sdk.WithLogger(ctx, logger))
+
+		switch {
+		case hook != nil:
+			return hook(c, args)
+		case plainHook != nil:
+			plainHook(c, args)
+			return nil
+		default:
+			return runParentPreRun(cmd, c, args)
+		}
+	}
+
+	for _, sub := range cmd.Commands() {
+		scopeCommandLogger(sub, logger)
+	}
+}
+
+// runParentPreRun runs the persistent pre-run hook of the nearest ancestor
+// of cmd that has one, which cobra would have run had cmd no hook of its own
+func runParentPreRun(cmd, executing *cobra.Command, args []string) error {
+	if cobra.EnableTraverseRunHooks {
+		// Cobra runs every ancestor's hook itself
+		return nil
+	}
+	for p := cmd.Parent(); p != nil; p = p.Parent() {
+		if p.PersistentPreRunE != nil {
+			return p.PersistentPreRunE(executing, args)
+		}
+		if p.PersistentPreRun != nil {
+			p.PersistentPreRun(executing, args)
+			return nil
+		}
+	}
+	return nil
+}
+
 // Global registry functions
 
 // GetGlobalRegistry returns the global plugin registry
@@ -185,7 +569,48 @@ func Get(name string) (Plugin, bool) {
 	return globalRegistry.Get(name)
 }
 
+// Unregister removes a plugin and its aliases from the global registry
+func Unregister(name string) error {
+	return globalRegistry.Unregister(name)
+}
+
+// SetValidationMode sets the validation mode of the global registry
+func SetValidationMode(mode ValidationMode) {
+	globalRegistry.SetValidationMode(mode)
+}
+
+// SetLoadOrder sets the plugin load order of the global registry
+func SetLoadOrder(names []string) {
+	globalRegistry.SetLoadOrder(names)
+}
+
+// SetDisabled sets the plugins the global registry skips
+func SetDisabled(names []string) {
+	globalRegistry.SetDisabled(names)
+}
+
+// Enabled returns the plugins of the global registry that have not been disabled
+func Enabled() []Plugin {
+	return globalRegistry.Enabled()
+}
+
+// SetLogger sets the root logger plugin loggers are derived from in the global registry
+func SetLogger(logger *logging.Logger) {
+	globalRegistry.SetLogger(logger)
+}
+
 // LoadAll loads all plugins from the global registry
 func LoadAll(root *cobra.Command) (*PluginLoadResult, error) {
 	return globalRegistry.LoadAll(root)
 }
+
+// ShutdownAll shuts down the plugins started by the global registry
+func ShutdownAll(ctx context.Context) error {
+	return globalRegistry.ShutdownAll(ctx)
+}
+
+// HealthCheckAll runs the health checks of the enabled plugins of the global
+// registry
+func HealthCheckAll(ctx context.Context) []PluginHealth {
+	return globalRegistry.HealthCheckAll(ctx)
+}
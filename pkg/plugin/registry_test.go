@@ -1,11 +1,17 @@
 package plugin_test
 
 import (
+	"bytes"
+	"context"
 	"errors"
+	"log/slog"
+	"strings"
 	"testing"
 
+	"github.com/glide-cli/glide/v3/pkg/logging"
 	"github.com/glide-cli/glide/v3/pkg/plugin"
 	"github.com/glide-cli/glide/v3/pkg/plugin/plugintest"
+	"github.com/glide-cli/glide/v3/pkg/plugin/sdk"
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -65,6 +71,21 @@ func TestRegistry(t *testing.T) {
 		assert.Contains(t, err.Error(), "already registered")
 	})
 
+	t.Run("register plugin with incompatible API version", func(t *testing.T) {
+		reg := plugin.NewRegistry()
+		p := plugintest.NewMockPlugin("stale-plugin")
+		p.MetadataValue.APIVersion = "0.3.0"
+
+		err := reg.RegisterPlugin(p)
+		require.Error(t, err)
+		assert.ErrorIs(t, err, sdk.ErrIncompatibleAPIVersion)
+		assert.Contains(t, err.Error(), "stale-plugin")
+		assert.Empty(t, reg.List())
+
+		p.MetadataValue.APIVersion = sdk.APIVersion
+		require.NoError(t, reg.RegisterPlugin(p))
+	})
+
 	t.Run("get plugin by name", func(t *testing.T) {
 		reg := plugin.NewRegistry()
 		p := plugintest.NewMockPlugin("test-plugin")
@@ -426,3 +447,443 @@ func TestPluginLoadResult(t *testing.T) {
 		assert.Contains(t, msg, "Successfully loaded 2 plugins: plugin1, plugin2")
 	})
 }
+
+// validatingPlugin is a mock plugin that implements plugin.Validatable
+type validatingPlugin struct {
+	*plugintest.MockPlugin
+	validateErr error
+}
+
+func (p *validatingPlugin) Validate() error {
+	return p.validateErr
+}
+
+func TestRegistryValidation(t *testing.T) {
+	t.Run("default mode is error", func(t *testing.T) {
+		reg := plugin.NewRegistry()
+		assert.Equal(t, plugin.ValidationModeError, reg.ValidationMode())
+	})
+
+	t.Run("passing self-check loads plugin", func(t *testing.T) {
+		reg := plugin.NewRegistry()
+		p := &validatingPlugin{MockPlugin: plugintest.NewMockPlugin("valid")}
+		require.NoError(t, reg.RegisterPlugin(p))
+
+		result, err := reg.LoadAll(&cobra.Command{Use: "test"})
+		require.NoError(t, err)
+		assert.Contains(t, result.Loaded, "valid")
+		assert.True(t, p.Registered)
+	})
+
+	t.Run("failing self-check refuses to load in error mode", func(t *testing.T) {
+		reg := plugin.NewRegistry()
+		p := &validatingPlugin{
+			MockPlugin:  plugintest.NewMockPlugin("broken"),
+			validateErr: errors.New("docker binary not found"),
+		}
+		require.NoError(t, reg.RegisterPlugin(p))
+
+		result, err := reg.LoadAll(&cobra.Command{Use: "test"})
+		require.NoError(t, err)
+		assert.NotContains(t, result.Loaded, "broken")
+		assert.False(t, p.Registered)
+		require.Len(t, result.Failed, 1)
+		assert.Equal(t, "broken", result.Failed[0].Name)
+		assert.False(t, result.Failed[0].IsFatal)
+		assert.Contains(t, result.Failed[0].Error.Error(), "docker binary not found")
+	})
+
+	t.Run("failing self-check loads with warning in warn mode", func(t *testing.T) {
+		reg := plugin.NewRegistry()
+		reg.SetValidationMode(plugin.ValidationModeWarn)
+		p := &validatingPlugin{
+			MockPlugin:  plugintest.NewMockPlugin("degraded"),
+			validateErr: errors.New("docker binary not found"),
+		}
+		require.NoError(t, reg.RegisterPlugin(p))
+
+		result, err := reg.LoadAll(&cobra.Command{Use: "test"})
+		require.NoError(t, err)
+		assert.Contains(t, result.Loaded, "degraded")
+		assert.True(t, p.Registered)
+		assert.False(t, result.HasErrors())
+		require.Len(t, result.Warnings, 1)
+		assert.Contains(t, result.Warnings[0], "degraded: docker binary not found")
+	})
+
+	t.Run("self-check runs after configure", func(t *testing.T) {
+		reg := plugin.NewRegistry()
+		p := &validatingPlugin{MockPlugin: plugintest.NewMockPlugin("ordered")}
+		p.ConfigureFunc = func() error {
+			p.validateErr = errors.New("set during configure")
+			return nil
+		}
+		require.NoError(t, reg.RegisterPlugin(p))
+
+		result, err := reg.LoadAll(&cobra.Command{Use: "test"})
+		require.NoError(t, err)
+		require.Len(t, result.Failed, 1)
+		assert.Contains(t, result.Failed[0].Error.Error(), "set during configure")
+	})
+}
+
+func TestRegistryLoadOrder(t *testing.T) {
+	newPlugins := func(t *testing.T) *plugin.Registry {
+		reg := plugin.NewRegistry()
+		for _, name := range []string{"node", "docker", "k8s", "golang"} {
+			p := plugintest.NewMockPlugin(name)
+			p.MetadataValue.Aliases = []string{name[:1] + "x"}
+			require.NoError(t, reg.RegisterPlugin(p))
+		}
+		return reg
+	}
+
+	t.Run("alphabetical by default", func(t *testing.T) {
+		reg := newPlugins(t)
+		assert.Equal(t, []string{"docker", "golang", "k8s", "node"}, reg.LoadOrder())
+	})
+
+	t.Run("configured plugins first", func(t *testing.T) {
+		reg := newPlugins(t)
+		reg.SetLoadOrder([]string{"node", "missing", "kx", "node"})
+		assert.Equal(t, []string{"node", "k8s", "docker", "golang"}, reg.LoadOrder())
+	})
+
+	t.Run("LoadAll honors order", func(t *testing.T) {
+		reg := newPlugins(t)
+		reg.SetLoadOrder([]string{"k8s", "docker"})
+
+		result, err := reg.LoadAll(&cobra.Command{Use: "test"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"k8s", "docker", "golang", "node"}, result.Loaded)
+	})
+
+	t.Run("priority before name", func(t *testing.T) {
+		reg := newPlugins(t)
+		for name, priority := range map[string]int{"node": 10, "k8s": 10, "golang": -1} {
+			p, ok := reg.Get(name)
+			require.True(t, ok)
+			p.(*plugintest.MockPlugin).MetadataValue.Priority = priority
+		}
+		assert.Equal(t, []string{"k8s", "node", "docker", "golang"}, reg.LoadOrder())
+
+		// Configured plugins still come first
+		reg.SetLoadOrder([]string{"golang"})
+		assert.Equal(t, []string{"golang", "k8s", "node", "docker"}, reg.LoadOrder())
+	})
+
+	t.Run("stable across LoadAll calls", func(t *testing.T) {
+		reg := plugin.NewRegistry()
+		priorities := map[string]int{"zeta": 2, "mu": 1, "omega": 1}
+		var registered []string
+		for _, name := range []string{"zeta", "alpha", "mu", "beta", "omega", "gamma"} {
+			p := plugintest.NewMockPlugin(name)
+			p.MetadataValue.Priority = priorities[name]
+			p.RegisterFunc = func(root *cobra.Command) error {
+				registered = append(registered, name)
+				return nil
+			}
+			require.NoError(t, reg.RegisterPlugin(p))
+		}
+
+		want := []string{"zeta", "mu", "omega", "alpha", "beta", "gamma"}
+		for i := 0; i < 20; i++ {
+			registered = nil
+			result, err := reg.LoadAll(&cobra.Command{Use: "test"})
+			require.NoError(t, err)
+			assert.Equal(t, want, result.Loaded)
+			assert.Equal(t, want, registered)
+		}
+	})
+
+	t.Run("first plugin in order wins a command conflict", func(t *testing.T) {
+		for _, order := range [][]string{{"docker", "k8s"}, {"k8s", "docker"}} {
+			reg := plugin.NewRegistry()
+			for _, name := range []string{"docker", "k8s"} {
+				p := plugintest.NewMockPlugin(name)
+				p.RegisterFunc = func(root *cobra.Command) error {
+					// Mimic plugins that skip commands already present
+					for _, c := range root.Commands() {
+						if c.Name() == "logs" {
+							return nil
+						}
+					}
+					root.AddCommand(&cobra.Command{Use: "logs", Short: name})
+					return nil
+				}
+				require.NoError(t, reg.RegisterPlugin(p))
+			}
+			reg.SetLoadOrder(order)
+
+			root := &cobra.Command{Use: "test"}
+			_, err := reg.LoadAll(root)
+			require.NoError(t, err)
+
+			logs, _, err := root.Find([]string{"logs"})
+			require.NoError(t, err)
+			assert.Equal(t, order[0], logs.Short)
+		}
+	})
+}
+
+func TestRegistryDisabled(t *testing.T) {
+	newPlugins := func(t *testing.T) *plugin.Registry {
+		reg := plugin.NewRegistry()
+		for _, name := range []string{"docker", "k8s", "node"} {
+			p := plugintest.NewMockPlugin(name)
+			p.MetadataValue.Aliases = []string{name[:1] + "x"}
+			p.RegisterFunc = func(root *cobra.Command) error {
+				root.AddCommand(&cobra.Command{Use: name})
+				return nil
+			}
+			require.NoError(t, reg.RegisterPlugin(p))
+		}
+		return reg
+	}
+
+	t.Run("everything loads by default", func(t *testing.T) {
+		reg := newPlugins(t)
+		result, err := reg.LoadAll(&cobra.Command{Use: "test"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"docker", "k8s", "node"}, result.Loaded)
+		assert.Len(t, reg.Enabled(), 3)
+	})
+
+	t.Run("disabled plugins are skipped", func(t *testing.T) {
+		reg := newPlugins(t)
+		reg.SetDisabled([]string{"docker", "kx"})
+
+		assert.True(t, reg.IsDisabled("docker"))
+		assert.True(t, reg.IsDisabled("dx"))
+		assert.True(t, reg.IsDisabled("k8s"))
+		assert.False(t, reg.IsDisabled("node"))
+
+		enabled := reg.Enabled()
+		require.Len(t, enabled, 1)
+		assert.Equal(t, "node", enabled[0].Name())
+
+		root := &cobra.Command{Use: "test"}
+		result, err := reg.LoadAll(root)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"node"}, result.Loaded)
+		assert.Empty(t, result.Failed)
+
+		p, _ := reg.Get("docker")
+		assert.False(t, p.(*plugintest.MockPlugin).Configured)
+		_, _, err = root.Find([]string{"docker"})
+		assert.Error(t, err)
+	})
+
+	t.Run("unknown names are reported", func(t *testing.T) {
+		reg := newPlugins(t)
+		reg.SetDisabled([]string{"dokcer"})
+
+		result, err := reg.LoadAll(&cobra.Command{Use: "test"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"docker", "k8s", "node"}, result.Loaded)
+		require.Len(t, result.Failed, 1)
+		assert.Equal(t, "dokcer", result.Failed[0].Name)
+		assert.Contains(t, result.Failed[0].Error.Error(), "unknown plugin")
+	})
+}
+
+// loggingPlugin is a mock plugin that logs from Configure and from its command
+type loggingPlugin struct {
+	*plugintest.MockPlugin
+	logger *logging.Logger
+}
+
+func (p *loggingPlugin) SetLogger(logger *logging.Logger) {
+	p.logger = logger
+}
+
+func (p *loggingPlugin) Configure() error {
+	p.logger.Info("configuring")
+	return nil
+}
+
+func TestRegistryPluginLoggers(t *testing.T) {
+	var buf bytes.Buffer
+	reg := plugin.NewRegistry()
+	reg.SetLogger(logging.New(&logging.Config{
+		Level:  slog.LevelDebug,
+		Format: logging.FormatText,
+		Output: &buf,
+	}))
+
+	docker := &loggingPlugin{MockPlugin: plugintest.NewMockPlugin("docker")}
+	docker.RegisterFunc = func(root *cobra.Command) error {
+		parent := &cobra.Command{Use: "docker"}
+		parent.AddCommand(&cobra.Command{
+			Use: "ps",
+			Run: func(cmd *cobra.Command, args []string) {
+				sdk.Logger(cmd.Context()).Info("listing containers")
+			},
+		})
+		root.AddCommand(parent)
+		return nil
+	}
+	require.NoError(t, reg.RegisterPlugin(docker))
+
+	rootHookRan := false
+	root := &cobra.Command{
+		Use:               "glide",
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error { rootHookRan = true; return nil },
+	}
+	root.SetContext(context.Background())
+	result, err := reg.LoadAll(root)
+	require.NoError(t, err)
+	require.Equal(t, []string{"docker"}, result.Loaded)
+
+	type ctxKey struct{}
+	execCtx, cancel := context.WithCancel(context.WithValue(context.Background(), ctxKey{}, "exec"))
+	defer cancel()
+	root.SetArgs([]string{"docker", "ps"})
+	require.NoError(t, root.ExecuteContext(execCtx))
+	assert.True(t, rootHookRan, "the root's persistent hook still runs for plugin commands")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2)
+	assert.Contains(t, lines[0], "msg=configuring")
+	assert.Contains(t, lines[1], "msg=\"listing containers\"")
+	for _, line := range lines {
+		assert.Contains(t, line, sdk.PluginLogKey+"=docker")
+	}
+
+	ps, _, err := root.Find([]string{"docker", "ps"})
+	require.NoError(t, err)
+	assert.Equal(t, "exec", ps.Context().Value(ctxKey{}), "the execution context reaches plugin commands")
+}
+
+// lifecyclePlugin is a mock plugin that records its Init and Shutdown calls
+type lifecyclePlugin struct {
+	*plugintest.MockPlugin
+	events      *[]string
+	initErr     error
+	shutdownErr error
+}
+
+func (p *lifecyclePlugin) Init(ctx context.Context) error {
+	sdk.Logger(ctx).Info("init")
+	*p.events = append(*p.events, "init:"+p.Name())
+	return p.initErr
+}
+
+func (p *lifecyclePlugin) Shutdown(ctx context.Context) error {
+	*p.events = append(*p.events, "shutdown:"+p.Name())
+	return p.shutdownErr
+}
+
+func TestRegistryLifecycle(t *testing.T) {
+	t.Run("init on load and shutdown in reverse order", func(t *testing.T) {
+		var events []string
+		reg := plugin.NewRegistry()
+		for _, name := range []string{"alpha", "beta", "gamma"} {
+			require.NoError(t, reg.RegisterPlugin(&lifecyclePlugin{MockPlugin: plugintest.NewMockPlugin(name), events: &events}))
+		}
+		// Plugins without lifecycle hooks are skipped
+		require.NoError(t, reg.RegisterPlugin(plugintest.NewMockPlugin("plain")))
+
+		var buf bytes.Buffer
+		reg.SetLogger(logging.New(&logging.Config{Level: slog.LevelInfo, Format: logging.FormatText, Output: &buf}))
+
+		result, err := reg.LoadAll(&cobra.Command{Use: "test"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"alpha", "beta", "gamma", "plain"}, result.Loaded)
+		assert.Equal(t, []string{"init:alpha", "init:beta", "init:gamma"}, events)
+		assert.Contains(t, buf.String(), "plugin=beta")
+
+		events = nil
+		require.NoError(t, reg.ShutdownAll(context.Background()))
+		assert.Equal(t, []string{"shutdown:gamma", "shutdown:beta", "shutdown:alpha"}, events)
+
+		// Plugins are only shut down once
+		events = nil
+		require.NoError(t, reg.ShutdownAll(context.Background()))
+		assert.Empty(t, events)
+	})
+
+	t.Run("init failure prevents loading", func(t *testing.T) {
+		var events []string
+		reg := plugin.NewRegistry()
+		failing := &lifecyclePlugin{MockPlugin: plugintest.NewMockPlugin("broken"), events: &events, initErr: errors.New("no daemon")}
+		require.NoError(t, reg.RegisterPlugin(failing))
+		require.NoError(t, reg.RegisterPlugin(&lifecyclePlugin{MockPlugin: plugintest.NewMockPlugin("ok"), events: &events}))
+
+		result, err := reg.LoadAll(&cobra.Command{Use: "test"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"ok"}, result.Loaded)
+		require.Len(t, result.Failed, 1)
+		assert.Contains(t, result.Failed[0].Error.Error(), "failed to initialize")
+		assert.False(t, failing.Registered)
+
+		events = nil
+		require.NoError(t, reg.ShutdownAll(context.Background()))
+		assert.Equal(t, []string{"shutdown:ok"}, events)
+	})
+
+	t.Run("shutdown errors are joined", func(t *testing.T) {
+		var events []string
+		reg := plugin.NewRegistry()
+		for _, name := range []string{"alpha", "beta"} {
+			require.NoError(t, reg.RegisterPlugin(&lifecyclePlugin{
+				MockPlugin:  plugintest.NewMockPlugin(name),
+				events:      &events,
+				shutdownErr: errors.New(name + " stuck"),
+			}))
+		}
+
+		_, err := reg.LoadAll(&cobra.Command{Use: "test"})
+		require.NoError(t, err)
+
+		err = reg.ShutdownAll(context.Background())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "plugin alpha: alpha stuck")
+		assert.Contains(t, err.Error(), "plugin beta: beta stuck")
+		assert.Equal(t, []string{"init:alpha", "init:beta", "shutdown:beta", "shutdown:alpha"}, events)
+	})
+}
+
+// healthPlugin reports fixed health check results
+type healthPlugin struct {
+	*plugintest.MockPlugin
+	results []plugin.HealthResult
+	gotCtx  context.Context
+}
+
+func (p *healthPlugin) HealthCheck(ctx context.Context) []plugin.HealthResult {
+	p.gotCtx = ctx
+	return p.results
+}
+
+func TestRegistryHealthCheckAll(t *testing.T) {
+	reg := plugin.NewRegistry()
+	docker := &healthPlugin{MockPlugin: plugintest.NewMockPlugin("docker"), results: []plugin.HealthResult{
+		{Name: "docker binary", OK: true},
+		{Name: "docker daemon", Message: "Cannot connect to the Docker daemon; is it running?"},
+	}}
+	node := &healthPlugin{MockPlugin: plugintest.NewMockPlugin("node"), results: []plugin.HealthResult{
+		{Name: "node binary", OK: true},
+	}}
+	disabled := &healthPlugin{MockPlugin: plugintest.NewMockPlugin("kube"), results: []plugin.HealthResult{
+		{Name: "kubectl binary"},
+	}}
+	require.NoError(t, reg.RegisterPlugin(node))
+	require.NoError(t, reg.RegisterPlugin(docker))
+	require.NoError(t, reg.RegisterPlugin(disabled))
+	// Plugins without health checks are left out
+	require.NoError(t, reg.RegisterPlugin(plugintest.NewMockPlugin("plain")))
+	reg.SetDisabled([]string{"kube"})
+
+	report := reg.HealthCheckAll(context.Background())
+
+	require.Len(t, report, 2)
+	assert.Equal(t, "docker", report[0].Plugin)
+	assert.Equal(t, docker.results, report[0].Results)
+	assert.False(t, report[0].Healthy())
+	assert.Equal(t, "node", report[1].Plugin)
+	assert.True(t, report[1].Healthy())
+
+	assert.Nil(t, disabled.gotCtx, "disabled plugins are not checked")
+	assert.NotSame(t, logging.Default(), sdk.Logger(docker.gotCtx), "checks receive the plugin's scoped logger")
+}
@@ -0,0 +1,33 @@
+package sdk
+
+import (
+	"fmt"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+// APIVersion is the plugin API version this host implements. Plugins declare
+// the version they were built against in their metadata; a plugin is only
+// loaded if its major version matches.
+const APIVersion = "1.0.0"
+
+// CheckAPIVersion checks that a plugin built against the declared API version
+// can run on this host. An empty declaration predates API versioning and is
+// accepted.
+func CheckAPIVersion(declared string) error {
+	if declared == "" {
+		return nil
+	}
+
+	version, err := semver.NewVersion(declared)
+	if err != nil {
+		return fmt.Errorf("%w: invalid API version %q: %v", ErrIncompatibleAPIVersion, declared, err)
+	}
+
+	host := semver.MustParse(APIVersion)
+	if version.Major() != host.Major() {
+		return fmt.Errorf("%w: built for API version %s, host supports %d.x (%s)",
+			ErrIncompatibleAPIVersion, declared, host.Major(), APIVersion)
+	}
+	return nil
+}
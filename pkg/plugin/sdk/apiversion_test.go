@@ -0,0 +1,33 @@
+package sdk
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCheckAPIVersion(t *testing.T) {
+	tests := []struct {
+		declared string
+		wantErr  bool
+	}{
+		{declared: "", wantErr: false},
+		{declared: APIVersion, wantErr: false},
+		{declared: "1", wantErr: false},
+		{declared: "1.7.2", wantErr: false},
+		{declared: "0.9.0", wantErr: true},
+		{declared: "2.0.0", wantErr: true},
+		{declared: "not-a-version", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.declared, func(t *testing.T) {
+			err := CheckAPIVersion(tt.declared)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CheckAPIVersion(%q) error = %v, wantErr %v", tt.declared, err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrIncompatibleAPIVersion) {
+				t.Errorf("error %v should wrap ErrIncompatibleAPIVersion", err)
+			}
+		})
+	}
+}
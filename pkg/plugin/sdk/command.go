@@ -1,7 +1,15 @@
 package sdk
 
 import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
 	"github.com/spf13/cobra"
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
 )
 
 // PluginCommandDefinition defines a command that a plugin provides
@@ -61,7 +69,8 @@ type FlagDefinition struct {
 	// Usage is the help text for this flag
 	Usage string
 
-	// Type is the flag data type (string, bool, int, etc.)
+	// Type is the flag data type: string, bool, int, float64 (or float),
+	// duration, []string or stringToString (repeatable KEY=VALUE pairs)
 	Type string
 
 	// Default is the default value for this flag
@@ -75,6 +84,119 @@ type FlagDefinition struct {
 
 	// Deprecated provides a deprecation message
 	Deprecated string
+
+	// EnvVar names an environment variable that supplies the flag's value
+	// when it is not set on the command line (optional). A flag given on the
+	// command line wins over the variable, which wins over Default.
+	EnvVar string
+
+	// Completion suggests values for the flag during shell completion
+	// (optional), e.g. ExtensionCompletion("docker", "services") for --service
+	Completion CompletionFunc
+}
+
+// supportedFlagTypes are the FlagDefinition types understood by ToCobraCommand.
+// An empty type is treated as "string".
+var supportedFlagTypes = map[string]bool{
+	"":               true,
+	"string":         true,
+	"bool":           true,
+	"int":            true,
+	"float64":        true,
+	"float":          true,
+	"duration":       true,
+	"[]string":       true,
+	"stringToString": true,
+}
+
+// Validate checks the definition and its subcommands for mistakes that would
+// otherwise only surface at runtime: a missing Name or Use, unknown flag
+// types, duplicate flag names or shorthands, and subcommand names or aliases
+// that collide with a sibling's. Errors wrap
+// ErrInvalidCommandDefinition and name the command path and offending field.
+func (d *PluginCommandDefinition) Validate() error {
+	return d.validate("")
+}
+
+// validate validates d, prefixing error messages with its command path
+func (d *PluginCommandDefinition) validate(parentPath string) error {
+	if d == nil {
+		return fmt.Errorf("%w: %s: nil command", ErrInvalidCommandDefinition, describeCommandPath(parentPath))
+	}
+
+	path := strings.TrimSpace(parentPath + " " + d.Name)
+	invalid := func(format string, args ...interface{}) error {
+		return fmt.Errorf("%w: %s: %s", ErrInvalidCommandDefinition, describeCommandPath(path), fmt.Sprintf(format, args...))
+	}
+
+	if strings.TrimSpace(d.Name) == "" {
+		return invalid("Name cannot be empty")
+	}
+	if strings.TrimSpace(d.Use) == "" {
+		return invalid("Use cannot be empty")
+	}
+
+	flagNames := make(map[string]bool, len(d.Flags))
+	shorthands := make(map[string]string, len(d.Flags))
+	for i, flag := range d.Flags {
+		if flag.Name == "" {
+			return invalid("Flags[%d].Name cannot be empty", i)
+		}
+		if flagNames[flag.Name] {
+			return invalid("Flags[%d].Name %q is defined more than once", i, flag.Name)
+		}
+		flagNames[flag.Name] = true
+
+		if !supportedFlagTypes[flag.Type] {
+			return invalid("Flags[%d].Type %q is not supported for flag %q", i, flag.Type, flag.Name)
+		}
+
+		if flag.Shorthand != "" {
+			if len(flag.Shorthand) != 1 {
+				return invalid("Flags[%d].Shorthand %q for flag %q must be a single character", i, flag.Shorthand, flag.Name)
+			}
+			if other, ok := shorthands[flag.Shorthand]; ok {
+				return invalid("Flags[%d].Shorthand %q for flag %q is already used by flag %q", i, flag.Shorthand, flag.Name, other)
+			}
+			shorthands[flag.Shorthand] = flag.Name
+		}
+	}
+
+	// subcommandNames maps each sibling name and alias to the subcommand using it
+	subcommandNames := make(map[string]string, len(d.Subcommands))
+	for i, sub := range d.Subcommands {
+		if sub == nil {
+			return invalid("Subcommands[%d] is nil", i)
+		}
+		if owner, ok := subcommandNames[sub.Name]; ok && sub.Name != "" {
+			if owner == sub.Name {
+				return invalid("Subcommands[%d].Name %q is defined more than once", i, sub.Name)
+			}
+			return invalid("Subcommands[%d].Name %q is already an alias of subcommand %q", i, sub.Name, owner)
+		}
+		subcommandNames[sub.Name] = sub.Name
+
+		for j, alias := range sub.Aliases {
+			if owner, ok := subcommandNames[alias]; ok && owner != sub.Name {
+				return invalid("Subcommands[%d].Aliases[%d] %q for subcommand %q is already used by subcommand %q", i, j, alias, sub.Name, owner)
+			}
+			subcommandNames[alias] = sub.Name
+		}
+
+		if err := sub.validate(path); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// describeCommandPath formats a command path for error messages
+func describeCommandPath(path string) string {
+	if path == "" {
+		return "top-level command"
+	}
+	return fmt.Sprintf("command %q", path)
 }
 
 // CommandProvider is the interface plugins implement to provide commands
@@ -106,8 +228,32 @@ func (d *PluginCommandDefinition) ToCobraCommand() *cobra.Command {
 	}
 
 	// Add flags
+	var envFlags []FlagDefinition
 	for _, flag := range d.Flags {
 		addFlagToCommand(cmd, flag)
+		if flag.Completion != nil {
+			// Registration only fails for unknown or repeated flag names,
+			// which Validate reports
+			_ = cmd.RegisterFlagCompletionFunc(flag.Name, flag.Completion)
+		}
+		if flag.EnvVar != "" {
+			envFlags = append(envFlags, flag)
+		}
+	}
+
+	// Seed env-bound flags before PreRunE so required flags and the
+	// plugin's own hooks see the resolved values
+	if len(envFlags) > 0 {
+		preRunE := d.PreRunE
+		cmd.PreRunE = func(cmd *cobra.Command, args []string) error {
+			if err := applyEnvFlags(cmd, envFlags); err != nil {
+				return err
+			}
+			if preRunE != nil {
+				return preRunE(cmd, args)
+			}
+			return nil
+		}
 	}
 
 	// Add subcommands
@@ -145,6 +291,22 @@ func addFlagToCommand(cmd *cobra.Command, flag FlagDefinition) {
 			cmd.Flags().Int(flag.Name, defaultVal, flag.Usage)
 		}
 
+	case "float64", "float":
+		defaultVal := floatDefault(flag.Default)
+		if flag.Shorthand != "" {
+			cmd.Flags().Float64P(flag.Name, flag.Shorthand, defaultVal, flag.Usage)
+		} else {
+			cmd.Flags().Float64(flag.Name, defaultVal, flag.Usage)
+		}
+
+	case "duration":
+		defaultVal := durationDefault(flag.Default)
+		if flag.Shorthand != "" {
+			cmd.Flags().DurationP(flag.Name, flag.Shorthand, defaultVal, flag.Usage)
+		} else {
+			cmd.Flags().Duration(flag.Name, defaultVal, flag.Usage)
+		}
+
 	case "[]string":
 		defaultVal, _ := flag.Default.([]string)
 		if flag.Shorthand != "" {
@@ -153,6 +315,14 @@ func addFlagToCommand(cmd *cobra.Command, flag FlagDefinition) {
 			cmd.Flags().StringSlice(flag.Name, defaultVal, flag.Usage)
 		}
 
+	case "stringToString":
+		defaultVal := stringMapDefault(flag.Default)
+		if flag.Shorthand != "" {
+			cmd.Flags().StringToStringP(flag.Name, flag.Shorthand, defaultVal, flag.Usage)
+		} else {
+			cmd.Flags().StringToString(flag.Name, defaultVal, flag.Usage)
+		}
+
 	default:
 		// Default to string type
 		defaultVal, _ := flag.Default.(string)
@@ -163,6 +333,13 @@ func addFlagToCommand(cmd *cobra.Command, flag FlagDefinition) {
 		}
 	}
 
+	// Mention the environment variable in help
+	if flag.EnvVar != "" {
+		if f := cmd.Flags().Lookup(flag.Name); f != nil {
+			f.Usage = strings.TrimSpace(fmt.Sprintf("%s [$%s]", f.Usage, flag.EnvVar))
+		}
+	}
+
 	// Mark as required if needed
 	if flag.Required {
 		cmd.MarkFlagRequired(flag.Name)
@@ -179,6 +356,85 @@ func addFlagToCommand(cmd *cobra.Command, flag FlagDefinition) {
 	}
 }
 
+// applyEnvFlags sets each flag not given on the command line from its
+// environment variable, using the flag's own parsing
+func applyEnvFlags(cmd *cobra.Command, flags []FlagDefinition) error {
+	for _, flag := range flags {
+		if cmd.Flags().Changed(flag.Name) {
+			continue
+		}
+		value, ok := os.LookupEnv(flag.EnvVar)
+		if !ok {
+			continue
+		}
+		if err := cmd.Flags().Set(flag.Name, value); err != nil {
+			return fmt.Errorf("invalid value %q for flag --%s from $%s: %w", value, flag.Name, flag.EnvVar, err)
+		}
+	}
+	return nil
+}
+
+// floatDefault converts a float flag's default to float64. Integer defaults
+// are accepted, since YAML and JSON decode a default such as 1 as an integer.
+func floatDefault(value interface{}) float64 {
+	switch v := value.(type) {
+	case float64:
+		return v
+	case float32:
+		return float64(v)
+	case int:
+		return float64(v)
+	case int32:
+		return float64(v)
+	case int64:
+		return float64(v)
+	case uint:
+		return float64(v)
+	case uint32:
+		return float64(v)
+	case uint64:
+		return float64(v)
+	default:
+		return 0
+	}
+}
+
+// durationDefault converts a duration flag's default to time.Duration. Strings
+// such as "30s" are parsed; anything else that is not a time.Duration yields
+// zero.
+func durationDefault(value interface{}) time.Duration {
+	switch v := value.(type) {
+	case time.Duration:
+		return v
+	case string:
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return 0
+		}
+		return d
+	default:
+		return 0
+	}
+}
+
+// stringMapDefault converts a stringToString flag's default to a map. A
+// map[string]interface{}, as YAML and JSON decode it, is accepted with its
+// values formatted as strings; anything else yields nil.
+func stringMapDefault(value interface{}) map[string]string {
+	switch v := value.(type) {
+	case map[string]string:
+		return v
+	case map[string]interface{}:
+		result := make(map[string]string, len(v))
+		for key, val := range v {
+			result[key] = fmt.Sprint(val)
+		}
+		return result
+	default:
+		return nil
+	}
+}
+
 // CommandRegistry manages registered commands from plugins
 type CommandRegistry struct {
 	commands map[string]*PluginCommandDefinition
@@ -201,6 +457,10 @@ func (r *CommandRegistry) Register(cmd *PluginCommandDefinition) error {
 		return ErrInvalidCommandName
 	}
 
+	if err := cmd.Validate(); err != nil {
+		return err
+	}
+
 	r.commands[cmd.Name] = cmd
 	return nil
 }
@@ -221,10 +481,51 @@ func (r *CommandRegistry) All() map[string]*PluginCommandDefinition {
 	return result
 }
 
-// AddToCobraCommand adds all registered commands to a cobra command
+// AddToCobraCommand adds all registered commands to a cobra command.
+//
+// Each command is placed in a help group named after its Category, so
+// --help lists the commands under category headings. Groups are added in
+// category order with uncategorized commands in a final DefaultCommandCategory
+// group; groups already on rootCmd are reused.
 func (r *CommandRegistry) AddToCobraCommand(rootCmd *cobra.Command) {
+	defs := make([]*PluginCommandDefinition, 0, len(r.commands))
 	for _, cmdDef := range r.commands {
+		defs = append(defs, cmdDef)
+	}
+	sort.Slice(defs, func(i, j int) bool {
+		ci, cj := commandCategory(defs[i]), commandCategory(defs[j])
+		if ci != cj {
+			// Uncategorized commands come last
+			if ci == DefaultCommandCategory || cj == DefaultCommandCategory {
+				return cj == DefaultCommandCategory
+			}
+			return ci < cj
+		}
+		return defs[i].Name < defs[j].Name
+	})
+
+	for _, cmdDef := range defs {
+		category := commandCategory(cmdDef)
+		if !rootCmd.ContainsGroup(category) {
+			rootCmd.AddGroup(&cobra.Group{
+				ID:    category,
+				Title: cases.Title(language.English).String(category) + " Commands:",
+			})
+		}
+
 		cobraCmd := cmdDef.ToCobraCommand()
+		cobraCmd.GroupID = category
 		rootCmd.AddCommand(cobraCmd)
 	}
 }
+
+// DefaultCommandCategory is the help group of commands without a Category
+const DefaultCommandCategory = "other"
+
+// commandCategory returns the help group for d
+func commandCategory(d *PluginCommandDefinition) string {
+	if d.Category == "" {
+		return DefaultCommandCategory
+	}
+	return d.Category
+}
@@ -0,0 +1,540 @@
+package sdk
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+func validCommand() *PluginCommandDefinition {
+	return &PluginCommandDefinition{
+		Name: "compose",
+		Use:  "compose [command]",
+		Flags: []FlagDefinition{
+			{Name: "file", Shorthand: "f", Type: "[]string"},
+			{Name: "detach", Shorthand: "d", Type: "bool"},
+			{Name: "project-name"},
+		},
+		Subcommands: []*PluginCommandDefinition{
+			{Name: "up", Use: "up [service...]"},
+			{Name: "ps", Use: "ps"},
+		},
+	}
+}
+
+func TestPluginCommandDefinition_Validate(t *testing.T) {
+	if err := validCommand().Validate(); err != nil {
+		t.Fatalf("Validate() error = %v, want nil", err)
+	}
+}
+
+func TestPluginCommandDefinition_ValidateFailures(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(d *PluginCommandDefinition)
+		want   string
+	}{
+		{
+			name:   "empty name",
+			modify: func(d *PluginCommandDefinition) { d.Name = "" },
+			want:   "Name cannot be empty",
+		},
+		{
+			name:   "empty use",
+			modify: func(d *PluginCommandDefinition) { d.Use = " " },
+			want:   `command "compose": Use cannot be empty`,
+		},
+		{
+			name:   "empty flag name",
+			modify: func(d *PluginCommandDefinition) { d.Flags[1].Name = "" },
+			want:   "Flags[1].Name cannot be empty",
+		},
+		{
+			name:   "unknown flag type",
+			modify: func(d *PluginCommandDefinition) { d.Flags[0].Type = "complex128" },
+			want:   `Flags[0].Type "complex128" is not supported for flag "file"`,
+		},
+		{
+			name: "duplicate flag name",
+			modify: func(d *PluginCommandDefinition) {
+				d.Flags = append(d.Flags, FlagDefinition{Name: "file"})
+			},
+			want: `Flags[3].Name "file" is defined more than once`,
+		},
+		{
+			name: "duplicate shorthand",
+			modify: func(d *PluginCommandDefinition) {
+				d.Flags[2].Shorthand = "f"
+			},
+			want: `Flags[2].Shorthand "f" for flag "project-name" is already used by flag "file"`,
+		},
+		{
+			name:   "long shorthand",
+			modify: func(d *PluginCommandDefinition) { d.Flags[0].Shorthand = "ff" },
+			want:   "must be a single character",
+		},
+		{
+			name:   "nil subcommand",
+			modify: func(d *PluginCommandDefinition) { d.Subcommands[1] = nil },
+			want:   `command "compose": Subcommands[1] is nil`,
+		},
+		{
+			name: "duplicate subcommand",
+			modify: func(d *PluginCommandDefinition) {
+				d.Subcommands = append(d.Subcommands, &PluginCommandDefinition{Name: "up", Use: "up"})
+			},
+			want: `Subcommands[2].Name "up" is defined more than once`,
+		},
+		{
+			name: "subcommand name used as sibling alias",
+			modify: func(d *PluginCommandDefinition) {
+				d.Subcommands[0].Aliases = []string{"start"}
+				d.Subcommands = append(d.Subcommands, &PluginCommandDefinition{Name: "start", Use: "start"})
+			},
+			want: `Subcommands[2].Name "start" is already an alias of subcommand "up"`,
+		},
+		{
+			name: "subcommand alias used by sibling",
+			modify: func(d *PluginCommandDefinition) {
+				d.Subcommands[1].Aliases = []string{"list", "up"}
+			},
+			want: `Subcommands[1].Aliases[1] "up" for subcommand "ps" is already used by subcommand "up"`,
+		},
+		{
+			name: "invalid nested subcommand",
+			modify: func(d *PluginCommandDefinition) {
+				d.Subcommands[0].Flags = []FlagDefinition{{Name: "timeout", Type: "complex128"}}
+			},
+			want: `command "compose up": Flags[0].Type "complex128"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			def := validCommand()
+			tt.modify(def)
+
+			err := def.Validate()
+			if err == nil {
+				t.Fatal("Validate() error = nil, want error")
+			}
+			if !errors.Is(err, ErrInvalidCommandDefinition) {
+				t.Errorf("Validate() error = %v, want ErrInvalidCommandDefinition", err)
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Validate() error = %q, want it to contain %q", err.Error(), tt.want)
+			}
+		})
+	}
+}
+
+func TestCommandRegistry_RegisterValidates(t *testing.T) {
+	reg := NewCommandRegistry()
+
+	if err := reg.Register(validCommand()); err != nil {
+		t.Fatalf("Register() error = %v, want nil", err)
+	}
+
+	invalid := validCommand()
+	invalid.Name = "other"
+	invalid.Flags[0].Type = "complex128"
+	if err := reg.Register(invalid); !errors.Is(err, ErrInvalidCommandDefinition) {
+		t.Errorf("Register() error = %v, want ErrInvalidCommandDefinition", err)
+	}
+	if _, ok := reg.Get("other"); ok {
+		t.Error("invalid command should not be registered")
+	}
+}
+
+func TestCommandRegistry_RegisterRejectsDuplicateFlags(t *testing.T) {
+	reg := NewCommandRegistry()
+
+	def := validCommand()
+	def.Flags = append(def.Flags,
+		FlagDefinition{Name: "force", Type: "bool"},
+		FlagDefinition{Name: "force", Type: "bool", Usage: "Force it again"},
+	)
+
+	err := reg.Register(def)
+	if !errors.Is(err, ErrInvalidCommandDefinition) {
+		t.Fatalf("Register() error = %v, want ErrInvalidCommandDefinition", err)
+	}
+	if !strings.Contains(err.Error(), `"force"`) {
+		t.Errorf("Register() error = %q, want it to name the flag %q", err.Error(), "force")
+	}
+	if _, ok := reg.Get(def.Name); ok {
+		t.Error("command with duplicate flags should not be registered")
+	}
+}
+
+func TestToCobraCommand_FloatFlags(t *testing.T) {
+	def := &PluginCommandDefinition{
+		Name: "run",
+		Use:  "run",
+		Flags: []FlagDefinition{
+			{Name: "cpu-limit", Shorthand: "c", Type: "float64", Default: 1.5},
+			{Name: "memory-ratio", Type: "float", Default: 1},
+			{Name: "scale", Type: "float", Default: int64(3)},
+			{Name: "weight", Type: "float64"},
+		},
+	}
+	if err := def.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	cmd := def.ToCobraCommand()
+
+	defaults := map[string]string{
+		"cpu-limit":    "1.5",
+		"memory-ratio": "1",
+		"scale":        "3",
+		"weight":       "0",
+	}
+	for name, want := range defaults {
+		flag := cmd.Flags().Lookup(name)
+		if flag == nil {
+			t.Fatalf("flag %q not registered", name)
+		}
+		if flag.Value.Type() != "float64" {
+			t.Errorf("flag %q type = %q, want float64", name, flag.Value.Type())
+		}
+		if flag.DefValue != want {
+			t.Errorf("flag %q default = %q, want %q", name, flag.DefValue, want)
+		}
+	}
+
+	if err := cmd.ParseFlags([]string{"-c", "0.25"}); err != nil {
+		t.Fatalf("ParseFlags() error = %v", err)
+	}
+	got, err := cmd.Flags().GetFloat64("cpu-limit")
+	if err != nil {
+		t.Fatalf("GetFloat64() error = %v", err)
+	}
+	if got != 0.25 {
+		t.Errorf("cpu-limit = %v, want 0.25", got)
+	}
+}
+
+func TestToCobraCommand_DurationFlags(t *testing.T) {
+	var timeout time.Duration
+	def := &PluginCommandDefinition{
+		Name: "deploy",
+		Use:  "deploy",
+		Flags: []FlagDefinition{
+			{Name: "timeout", Shorthand: "t", Type: "duration", Default: "30s"},
+			{Name: "interval", Type: "duration", Default: 5 * time.Second},
+			{Name: "grace", Type: "duration", Default: 10},
+			{Name: "retry", Type: "duration", Default: "soon"},
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var err error
+			timeout, err = cmd.Flags().GetDuration("timeout")
+			return err
+		},
+	}
+	if err := def.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	cmd := def.ToCobraCommand()
+
+	defaults := map[string]string{
+		"timeout":  "30s",
+		"interval": "5s",
+		"grace":    "0s",
+		"retry":    "0s",
+	}
+	for name, want := range defaults {
+		flag := cmd.Flags().Lookup(name)
+		if flag == nil {
+			t.Fatalf("flag %q not registered", name)
+		}
+		if flag.DefValue != want {
+			t.Errorf("flag %q default = %q, want %q", name, flag.DefValue, want)
+		}
+	}
+
+	cmd.SetArgs([]string{"--timeout", "2m"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if timeout != 2*time.Minute {
+		t.Errorf("timeout = %v, want 2m", timeout)
+	}
+}
+
+// envCommandProvider provides a command with a stringToString flag
+type envCommandProvider struct {
+	env map[string]string
+}
+
+func (p *envCommandProvider) ProvideCommands() []*PluginCommandDefinition {
+	return []*PluginCommandDefinition{
+		{
+			Name: "exec",
+			Use:  "exec",
+			Flags: []FlagDefinition{
+				{Name: "env", Shorthand: "e", Type: "stringToString", Usage: "Set environment variables"},
+				{Name: "labels", Type: "stringToString", Default: map[string]string{"team": "web"}},
+				{Name: "decoded", Type: "stringToString", Default: map[string]interface{}{"replicas": 2}},
+			},
+			RunE: func(cmd *cobra.Command, args []string) error {
+				var err error
+				p.env, err = cmd.Flags().GetStringToString("env")
+				return err
+			},
+		},
+	}
+}
+
+func TestToCobraCommand_StringToStringFlags(t *testing.T) {
+	provider := &envCommandProvider{}
+
+	reg := NewCommandRegistry()
+	for _, def := range provider.ProvideCommands() {
+		if err := reg.Register(def); err != nil {
+			t.Fatalf("Register() error = %v", err)
+		}
+	}
+
+	root := &cobra.Command{Use: "glide"}
+	reg.AddToCobraCommand(root)
+
+	execCmd, _, err := root.Find([]string{"exec"})
+	if err != nil {
+		t.Fatalf("Find() error = %v", err)
+	}
+
+	defaults := map[string]string{
+		"env":     "[]",
+		"labels":  "[team=web]",
+		"decoded": "[replicas=2]",
+	}
+	for name, want := range defaults {
+		flag := execCmd.Flags().Lookup(name)
+		if flag == nil {
+			t.Fatalf("flag %q not registered", name)
+		}
+		if flag.Value.Type() != "stringToString" {
+			t.Errorf("flag %q type = %q, want stringToString", name, flag.Value.Type())
+		}
+		if flag.DefValue != want {
+			t.Errorf("flag %q default = %q, want %q", name, flag.DefValue, want)
+		}
+	}
+
+	if usage := execCmd.Flags().FlagUsages(); !strings.Contains(usage, "-e, --env stringToString") {
+		t.Errorf("help does not show the env flag:\n%s", usage)
+	}
+
+	root.SetArgs([]string{"exec", "--env", "KEY=VAL", "-e", "OTHER=VAL"})
+	if err := root.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	want := map[string]string{"KEY": "VAL", "OTHER": "VAL"}
+	if len(provider.env) != len(want) || provider.env["KEY"] != "VAL" || provider.env["OTHER"] != "VAL" {
+		t.Errorf("env = %v, want %v", provider.env, want)
+	}
+}
+
+func TestToCobraCommand_EnvVarFlags(t *testing.T) {
+	type values struct {
+		region  string
+		verbose bool
+		workers int
+	}
+
+	newCommand := func(got *values) *cobra.Command {
+		def := &PluginCommandDefinition{
+			Name: "sync",
+			Use:  "sync",
+			Flags: []FlagDefinition{
+				{Name: "region", Type: "string", Default: "us-east-1", EnvVar: "GLIDE_TEST_REGION"},
+				{Name: "verbose", Type: "bool", EnvVar: "GLIDE_TEST_VERBOSE"},
+				{Name: "workers", Type: "int", Default: 4, EnvVar: "GLIDE_TEST_WORKERS"},
+			},
+			RunE: func(cmd *cobra.Command, args []string) error {
+				got.region, _ = cmd.Flags().GetString("region")
+				got.verbose, _ = cmd.Flags().GetBool("verbose")
+				got.workers, _ = cmd.Flags().GetInt("workers")
+				return nil
+			},
+		}
+		cmd := def.ToCobraCommand()
+		cmd.SilenceErrors = true
+		cmd.SilenceUsage = true
+		return cmd
+	}
+
+	tests := []struct {
+		name string
+		env  map[string]string
+		args []string
+		want values
+	}{
+		{
+			name: "defaults without env",
+			want: values{region: "us-east-1", verbose: false, workers: 4},
+		},
+		{
+			name: "env overrides defaults",
+			env:  map[string]string{"GLIDE_TEST_REGION": "eu-west-1", "GLIDE_TEST_VERBOSE": "true", "GLIDE_TEST_WORKERS": "8"},
+			want: values{region: "eu-west-1", verbose: true, workers: 8},
+		},
+		{
+			name: "flags override env",
+			env:  map[string]string{"GLIDE_TEST_REGION": "eu-west-1", "GLIDE_TEST_VERBOSE": "true", "GLIDE_TEST_WORKERS": "8"},
+			args: []string{"--region", "ap-south-1", "--verbose=false", "--workers", "2"},
+			want: values{region: "ap-south-1", verbose: false, workers: 2},
+		},
+		{
+			name: "flags without env",
+			args: []string{"--region", "ap-south-1", "--verbose", "--workers", "2"},
+			want: values{region: "ap-south-1", verbose: true, workers: 2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+
+			var got values
+			cmd := newCommand(&got)
+			cmd.SetArgs(tt.args)
+			if err := cmd.Execute(); err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("values = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("invalid env value", func(t *testing.T) {
+		t.Setenv("GLIDE_TEST_WORKERS", "many")
+
+		var got values
+		cmd := newCommand(&got)
+		cmd.SetArgs(nil)
+		err := cmd.Execute()
+		if err == nil {
+			t.Fatal("Execute() error = nil, want invalid value error")
+		}
+		if !strings.Contains(err.Error(), "$GLIDE_TEST_WORKERS") {
+			t.Errorf("error %q does not name the environment variable", err)
+		}
+	})
+
+	t.Run("satisfies required flags", func(t *testing.T) {
+		t.Setenv("GLIDE_TEST_TOKEN", "secret")
+
+		var token string
+		def := &PluginCommandDefinition{
+			Name:  "login",
+			Use:   "login",
+			Flags: []FlagDefinition{{Name: "token", Required: true, EnvVar: "GLIDE_TEST_TOKEN"}},
+			RunE: func(cmd *cobra.Command, args []string) error {
+				token, _ = cmd.Flags().GetString("token")
+				return nil
+			},
+		}
+		cmd := def.ToCobraCommand()
+		cmd.SetArgs(nil)
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+		if token != "secret" {
+			t.Errorf("token = %q, want secret", token)
+		}
+		if usage := cmd.Flags().FlagUsages(); !strings.Contains(usage, "[$GLIDE_TEST_TOKEN]") {
+			t.Errorf("help does not mention the environment variable:\n%s", usage)
+		}
+	})
+}
+
+func TestCommandRegistry_AddToCobraCommandGroupsByCategory(t *testing.T) {
+	reg := NewCommandRegistry()
+	defs := []*PluginCommandDefinition{
+		{Name: "logs", Use: "logs", Short: "Show logs"},
+		{Name: "up", Use: "up", Short: "Start services", Category: "docker"},
+		{Name: "deploy", Use: "deploy", Short: "Deploy", Category: "release"},
+		{Name: "build", Use: "build", Short: "Build images", Category: "docker"},
+	}
+	for _, def := range defs {
+		// Commands without a RunE are listed as help topics instead
+		def.RunE = func(cmd *cobra.Command, args []string) error { return nil }
+		if err := reg.Register(def); err != nil {
+			t.Fatalf("Register(%q) error = %v", def.Name, err)
+		}
+	}
+
+	root := &cobra.Command{Use: "glide"}
+	root.AddGroup(&cobra.Group{ID: "release", Title: "Shipping:"})
+	reg.AddToCobraCommand(root)
+
+	var ids, titles []string
+	for _, group := range root.Groups() {
+		ids = append(ids, group.ID)
+		titles = append(titles, group.Title)
+	}
+	wantIDs := []string{"release", "docker", DefaultCommandCategory}
+	if strings.Join(ids, ",") != strings.Join(wantIDs, ",") {
+		t.Errorf("group IDs = %v, want %v", ids, wantIDs)
+	}
+	wantTitles := []string{"Shipping:", "Docker Commands:", "Other Commands:"}
+	if strings.Join(titles, ",") != strings.Join(wantTitles, ",") {
+		t.Errorf("group titles = %v, want %v", titles, wantTitles)
+	}
+
+	wantGroups := map[string]string{
+		"logs":   DefaultCommandCategory,
+		"up":     "docker",
+		"build":  "docker",
+		"deploy": "release",
+	}
+	for name, want := range wantGroups {
+		cmd, _, err := root.Find([]string{name})
+		if err != nil {
+			t.Fatalf("Find(%q) error = %v", name, err)
+		}
+		if cmd.GroupID != want {
+			t.Errorf("%s GroupID = %q, want %q", name, cmd.GroupID, want)
+		}
+	}
+
+	var out strings.Builder
+	root.SetOut(&out)
+	root.SetArgs([]string{"--help"})
+	if err := root.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	help := out.String()
+	positions := []int{
+		strings.Index(help, "Shipping:"),
+		strings.Index(help, "Docker Commands:"),
+		strings.Index(help, "  build"),
+		strings.Index(help, "  up"),
+		strings.Index(help, "Other Commands:"),
+		strings.Index(help, "  logs"),
+	}
+	for i, pos := range positions {
+		if pos < 0 || (i > 0 && pos < positions[i-1]) {
+			t.Fatalf("help does not list commands by category:\n%s", help)
+		}
+	}
+
+	// Adding more commands reuses the existing groups
+	more := NewCommandRegistry()
+	if err := more.Register(&PluginCommandDefinition{Name: "push", Use: "push", Category: "docker"}); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	more.AddToCobraCommand(root)
+	if len(root.Groups()) != len(wantIDs) {
+		t.Errorf("groups = %d after second registry, want %d", len(root.Groups()), len(wantIDs))
+	}
+}
@@ -1,6 +1,20 @@
 package sdk
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/glide-cli/glide/v3/pkg/branding"
 	"github.com/spf13/cobra"
 )
 
@@ -92,6 +106,38 @@ func StaticCompletion(options []string) CompletionFunc {
 	}
 }
 
+// StaticCompletionWithDescriptions creates a completion function that returns
+// options in order, each with its entry from descriptions shown next to it by
+// shells that support descriptions. Options without a description are bare.
+func StaticCompletionWithDescriptions(options []string, descriptions map[string]string) CompletionFunc {
+	entries := make([]string, len(options))
+	for i, option := range options {
+		entries[i] = describedEntry(option, descriptions[option])
+	}
+	return StaticCompletion(entries)
+}
+
+// DescribedCompletion creates a completion function that returns the values
+// of a value→description map, sorted, with their descriptions
+func DescribedCompletion(values map[string]string) CompletionFunc {
+	options := make([]string, 0, len(values))
+	for value := range values {
+		options = append(options, value)
+	}
+	sort.Strings(options)
+	return StaticCompletionWithDescriptions(options, values)
+}
+
+// describedEntry formats a completion entry in cobra's "value\tdescription"
+// form. Descriptions are kept to one line, as shells expect.
+func describedEntry(value, description string) string {
+	description = strings.Join(strings.Fields(description), " ")
+	if description == "" {
+		return value
+	}
+	return value + "\t" + description
+}
+
 // DynamicCompletion creates a completion function from a provider function
 func DynamicCompletion(provider func() []string) CompletionFunc {
 	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
@@ -130,3 +176,343 @@ func ConditionalCompletion(completions map[int]CompletionFunc, defaultCompletion
 		return nil, NoFileCompletion()
 	}
 }
+
+// TimeoutCompletion bounds a completion function to timeout, so a slow source
+// such as a busy Docker daemon cannot hang the shell. fn runs with the
+// command's context limited to the timeout, which stops context-aware work
+// such as ExecCompletion's subprocess when it expires. If fn has not returned
+// in time, there are no suggestions and file completion is disabled; its late
+// result is discarded.
+func TimeoutCompletion(timeout time.Duration, fn CompletionFunc) CompletionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		type result struct {
+			values    []string
+			directive cobra.ShellCompDirective
+		}
+
+		parent := context.Background()
+		if cmd != nil && cmd.Context() != nil {
+			parent = cmd.Context()
+		}
+		ctx, cancel := context.WithTimeout(parent, timeout)
+		defer cancel()
+
+		// The goroutine owns cmd until fn returns and then restores its context;
+		// the channel is buffered so it can finish after the deadline
+		done := make(chan result, 1)
+		if cmd != nil {
+			cmd.SetContext(ctx)
+		}
+		go func() {
+			values, directive := fn(cmd, args, toComplete)
+			if cmd != nil {
+				cmd.SetContext(parent)
+			}
+			done <- result{values, directive}
+		}()
+
+		select {
+		case r := <-done:
+			return r.values, r.directive
+		case <-ctx.Done():
+			return nil, NoFileCompletion()
+		}
+	}
+}
+
+// ExecCompletion creates a completion function that runs name with args
+// through exec and offers the values parse extracts from its standard output,
+// e.g. ExecCompletion(exec, nil, "docker", "compose", "ps", "--services").
+// A nil parse offers each non-blank output line. File completion is disabled,
+// and a command that fails or exits non-zero yields no suggestions.
+//
+// The command runs with the completed command's context; wrap the result in
+// TimeoutCompletion or CachedCompletion for slow commands.
+func ExecCompletion(exec CommandExecutor, parse func(output string) []string, name string, args ...string) CompletionFunc {
+	if parse == nil {
+		parse = CompletionLines
+	}
+	return func(cmd *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		ctx := context.Background()
+		if cmd != nil && cmd.Context() != nil {
+			ctx = cmd.Context()
+		}
+
+		output, err := exec.Output(ctx, name, args...)
+		if err != nil {
+			return nil, NoFileCompletion()
+		}
+		return parse(output), NoFileCompletion()
+	}
+}
+
+// CompletionLines splits command output into completion values: one per
+// line, trimmed, with blank lines dropped
+func CompletionLines(output string) []string {
+	var values []string
+	for _, line := range strings.Split(output, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			values = append(values, line)
+		}
+	}
+	return values
+}
+
+// emptyCompletionTTL caps how long CachedCompletion reuses an empty result,
+// which often means the data was not available yet (e.g. containers starting)
+const emptyCompletionTTL = time.Second
+
+// CachedCompletion wraps an expensive completion function so its results are
+// reused for ttl, per args and prefix being completed. Empty results are
+// reused for at most a second. It is safe for concurrent use.
+//
+// Shells run a new process for every completion request, so results are also
+// stored under the user's cache directory and shared between processes. An
+// entry is keyed by where CachedCompletion was called, the command path, the
+// args and the prefix. If the cache directory is not available, results are
+// only reused within the process.
+func CachedCompletion(ttl time.Duration, fn CompletionFunc) CompletionFunc {
+	c := newCompletionCache(ttl, fn)
+	c.dir = completionCacheDir()
+	if _, file, line, ok := runtime.Caller(1); ok {
+		c.id = fmt.Sprintf("%s:%d", file, line)
+	}
+	return c.complete
+}
+
+// completionCacheDir returns the directory for cached completion results, or
+// an empty string if the user has no cache directory
+func completionCacheDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, branding.CommandName, "completions")
+}
+
+// completionCache memoizes the results of a completion function
+type completionCache struct {
+	fn  CompletionFunc
+	ttl time.Duration
+	now func() time.Time
+
+	// dir persists entries between processes; empty keeps them in memory only
+	dir string
+	// id tells apart the caches of different completion functions in dir
+	id string
+
+	mu      sync.Mutex
+	entries map[string]completionCacheEntry
+}
+
+// completionCacheEntry is a cached completion result
+type completionCacheEntry struct {
+	Values    []string                 `json:"values"`
+	Directive cobra.ShellCompDirective `json:"directive"`
+	Expires   time.Time                `json:"expires"`
+}
+
+// newCompletionCache creates an in-memory cache for fn's results
+func newCompletionCache(ttl time.Duration, fn CompletionFunc) *completionCache {
+	return &completionCache{
+		fn:      fn,
+		ttl:     ttl,
+		now:     time.Now,
+		entries: make(map[string]completionCacheEntry),
+	}
+}
+
+// complete returns the cached result for the arguments if it is fresh,
+// otherwise calls the wrapped function and caches its result
+func (c *completionCache) complete(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	key := c.key(cmd, args, toComplete)
+
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	c.mu.Unlock()
+	if !ok {
+		entry, ok = c.load(key)
+	}
+	if ok && c.now().Before(entry.Expires) {
+		return append([]string(nil), entry.Values...), entry.Directive
+	}
+
+	// Call the function without holding the lock so a slow completion does
+	// not block completions for other arguments
+	values, directive := c.fn(cmd, args, toComplete)
+
+	ttl := c.ttl
+	if len(values) == 0 && ttl > emptyCompletionTTL {
+		ttl = emptyCompletionTTL
+	}
+
+	now := c.now()
+	entry = completionCacheEntry{
+		Values:    append([]string(nil), values...),
+		Directive: directive,
+		Expires:   now.Add(ttl),
+	}
+
+	c.mu.Lock()
+	for k, e := range c.entries {
+		if !now.Before(e.Expires) {
+			delete(c.entries, k)
+		}
+	}
+	c.entries[key] = entry
+	c.mu.Unlock()
+
+	c.store(key, entry, now)
+	return values, directive
+}
+
+// key identifies a completion request by the cache, the command, its
+// arguments and the prefix being completed
+func (c *completionCache) key(cmd *cobra.Command, args []string, toComplete string) string {
+	parts := []string{c.id}
+	if cmd != nil {
+		parts = append(parts, cmd.CommandPath())
+	}
+	parts = append(parts, args...)
+	return strings.Join(append(parts, toComplete), "\x00")
+}
+
+// path returns the file that persists the entry for key
+func (c *completionCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// load reads a persisted entry. Unreadable entries are treated as missing.
+func (c *completionCache) load(key string) (completionCacheEntry, bool) {
+	var entry completionCacheEntry
+	if c.dir == "" {
+		return entry, false
+	}
+
+	data, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return entry, false
+	}
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return entry, false
+	}
+	return entry, true
+}
+
+// store persists an entry and removes expired ones. The cache is best
+// effort, so write errors are ignored.
+func (c *completionCache) store(key string, entry completionCacheEntry, now time.Time) {
+	if c.dir == "" {
+		return
+	}
+	if err := os.MkdirAll(c.dir, 0700); err != nil {
+		return
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+
+	// Write to a temporary file first so concurrent readers never see a
+	// partial entry
+	tmp, err := os.CreateTemp(c.dir, ".tmp-*")
+	if err != nil {
+		return
+	}
+	_, writeErr := tmp.Write(data)
+	closeErr := tmp.Close()
+	if writeErr != nil || closeErr != nil {
+		_ = os.Remove(tmp.Name())
+		return
+	}
+
+	// The modification time records the expiry, so pruning needs no reads
+	_ = os.Chtimes(tmp.Name(), entry.Expires, entry.Expires)
+	path := c.path(key)
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		_ = os.Remove(tmp.Name())
+		return
+	}
+
+	files, err := os.ReadDir(c.dir)
+	if err != nil {
+		return
+	}
+	for _, f := range files {
+		info, err := f.Info()
+		if err != nil || !strings.HasSuffix(f.Name(), ".json") {
+			continue
+		}
+		if !now.Before(info.ModTime()) {
+			_ = os.Remove(filepath.Join(c.dir, f.Name()))
+		}
+	}
+}
+
+// CompletionValuesProvider is an optional interface for context extensions
+// that can offer values they found during detection as shell completions,
+// e.g. the service names of a compose project.
+type CompletionValuesProvider interface {
+	// CompletionValues returns the values known for key in data, the
+	// extension's detection result. Unknown keys return nil.
+	CompletionValues(data interface{}, key string) []string
+}
+
+// detectedContext is the detection result ExtensionCompletion reads from
+var detectedContext struct {
+	mu         sync.RWMutex
+	extensions map[string]ContextExtension
+	data       map[string]interface{}
+}
+
+// SetDetectedContext records the context extensions and their detection
+// results for ExtensionCompletion. The host calls it once project context
+// detection has finished.
+func SetDetectedContext(extensions []ContextExtension, data map[string]interface{}) {
+	byName := make(map[string]ContextExtension, len(extensions))
+	for _, ext := range extensions {
+		if ext == nil {
+			continue
+		}
+		// The first extension registered under a name owns it, as in detection
+		if _, exists := byName[ext.Name()]; !exists {
+			byName[ext.Name()] = ext
+		}
+	}
+
+	detectedContext.mu.Lock()
+	defer detectedContext.mu.Unlock()
+	detectedContext.extensions = byName
+	detectedContext.data = data
+}
+
+// DetectedCompletionValues returns the values the named extension offers for
+// key, based on the detected context. It returns nil if the extension was not
+// detected or does not implement CompletionValuesProvider.
+func DetectedCompletionValues(extName, key string) []string {
+	detectedContext.mu.RLock()
+	ext, ok := detectedContext.extensions[extName]
+	data, detected := detectedContext.data[extName]
+	detectedContext.mu.RUnlock()
+
+	if !ok || !detected {
+		return nil
+	}
+
+	provider, ok := ext.(CompletionValuesProvider)
+	if !ok {
+		return nil
+	}
+	return provider.CompletionValues(data, key)
+}
+
+// ExtensionCompletion creates a completion function that offers the values
+// the named context extension detected for key, e.g.
+// ExtensionCompletion("docker", "services")
+func ExtensionCompletion(extName, key string) CompletionFunc {
+	return DynamicCompletion(func() []string {
+		return DetectedCompletionValues(extName, key)
+	})
+}
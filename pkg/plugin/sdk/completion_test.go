@@ -0,0 +1,461 @@
+package sdk
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// dockerExtension is a docker-like extension that completes service names
+type dockerExtension struct{}
+
+func (dockerExtension) Name() string { return "docker" }
+
+func (dockerExtension) Detect(ctx context.Context, projectRoot string) (interface{}, error) {
+	return map[string]interface{}{"services": []string{"web", "db"}}, nil
+}
+
+func (dockerExtension) Merge(existing interface{}, new interface{}) (interface{}, error) {
+	return new, nil
+}
+
+func (dockerExtension) CompletionValues(data interface{}, key string) []string {
+	fields, ok := data.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	values, _ := fields[key].([]string)
+	sorted := append([]string(nil), values...)
+	sort.Strings(sorted)
+	return sorted
+}
+
+// plainExtension does not offer completion values
+type plainExtension struct{}
+
+func (plainExtension) Name() string { return "plain" }
+
+func (plainExtension) Detect(ctx context.Context, projectRoot string) (interface{}, error) {
+	return map[string]interface{}{}, nil
+}
+
+func (plainExtension) Merge(existing interface{}, new interface{}) (interface{}, error) {
+	return new, nil
+}
+
+func TestExtensionCompletion(t *testing.T) {
+	defer SetDetectedContext(nil, nil)
+
+	docker := dockerExtension{}
+	data, _ := docker.Detect(context.Background(), "/project")
+	SetDetectedContext(
+		[]ContextExtension{docker, plainExtension{}},
+		map[string]interface{}{"docker": data, "plain": data},
+	)
+
+	values, directive := ExtensionCompletion("docker", "services")(&cobra.Command{}, nil, "")
+	if want := []string{"db", "web"}; !reflect.DeepEqual(values, want) {
+		t.Errorf("values = %v, want %v", values, want)
+	}
+	if directive != cobra.ShellCompDirectiveNoFileComp {
+		t.Errorf("directive = %v, want NoFileComp", directive)
+	}
+
+	tests := []struct {
+		name    string
+		extName string
+		key     string
+	}{
+		{"unknown key", "docker", "volumes"},
+		{"extension without completion values", "plain", "services"},
+		{"unknown extension", "cloud", "regions"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if values := DetectedCompletionValues(tt.extName, tt.key); values != nil {
+				t.Errorf("DetectedCompletionValues(%q, %q) = %v, want nil", tt.extName, tt.key, values)
+			}
+		})
+	}
+
+	t.Run("not detected in this project", func(t *testing.T) {
+		SetDetectedContext([]ContextExtension{docker}, map[string]interface{}{})
+		if values := DetectedCompletionValues("docker", "services"); values != nil {
+			t.Errorf("values = %v, want nil", values)
+		}
+	})
+}
+
+func TestToCobraCommand_FlagCompletion(t *testing.T) {
+	def := &PluginCommandDefinition{
+		Name: "logs",
+		Use:  "logs [container]",
+		Flags: []FlagDefinition{
+			{Name: "service", Shorthand: "s", Completion: StaticCompletion([]string{"web", "db"})},
+			{Name: "tail", Type: "int"},
+		},
+		RunE: func(cmd *cobra.Command, args []string) error { return nil },
+	}
+
+	root := &cobra.Command{Use: "glide"}
+	root.AddCommand(def.ToCobraCommand())
+
+	registry := NewCompletionRegistry()
+	if err := registry.Register("logs", StaticCompletion([]string{"app-1", "app-2"})); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	registry.ApplyToCommand(root)
+
+	complete := func(args ...string) string {
+		var out bytes.Buffer
+		root.SetOut(&out)
+		root.SetErr(&bytes.Buffer{})
+		root.SetArgs(append([]string{cobra.ShellCompRequestCmd}, args...))
+		if err := root.Execute(); err != nil {
+			t.Fatalf("completing %v: %v", args, err)
+		}
+		return out.String()
+	}
+
+	tests := []struct {
+		name    string
+		args    []string
+		want    []string
+		notWant []string
+	}{
+		{"flag value", []string{"logs", "--service", ""}, []string{"web", "db"}, []string{"app-1"}},
+		{"flag shorthand value", []string{"logs", "-s", ""}, []string{"web", "db"}, []string{"app-1"}},
+		{"positional argument", []string{"logs", ""}, []string{"app-1", "app-2"}, []string{"web"}},
+		{"positional after flag", []string{"logs", "--service", "web", ""}, []string{"app-1"}, []string{"db"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines := strings.Split(complete(tt.args...), "\n")
+			for _, want := range tt.want {
+				if !containsLine(lines, want) {
+					t.Errorf("completions %q missing %q", lines, want)
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if containsLine(lines, notWant) {
+					t.Errorf("completions %q unexpectedly contain %q", lines, notWant)
+				}
+			}
+		})
+	}
+}
+
+func containsLine(lines []string, want string) bool {
+	for _, line := range lines {
+		if line == want {
+			return true
+		}
+	}
+	return false
+}
+
+func TestStaticCompletionWithDescriptions(t *testing.T) {
+	fn := StaticCompletionWithDescriptions(
+		[]string{"up", "down", "ps"},
+		map[string]string{"up": "Start services", "down": "Stop and remove\n  services"},
+	)
+
+	values, directive := fn(&cobra.Command{}, nil, "")
+	want := []string{"up\tStart services", "down\tStop and remove services", "ps"}
+	if !reflect.DeepEqual(values, want) {
+		t.Errorf("values = %q, want %q", values, want)
+	}
+	if directive != cobra.ShellCompDirectiveNoFileComp {
+		t.Errorf("directive = %v, want NoFileComp", directive)
+	}
+}
+
+func TestDescribedCompletion(t *testing.T) {
+	fn := DescribedCompletion(map[string]string{
+		"up":   "Start services",
+		"down": "Stop services",
+		"logs": "",
+	})
+
+	values, directive := fn(&cobra.Command{}, nil, "")
+	want := []string{"down\tStop services", "logs", "up\tStart services"}
+	if !reflect.DeepEqual(values, want) {
+		t.Errorf("values = %q, want %q", values, want)
+	}
+	if directive != cobra.ShellCompDirectiveNoFileComp {
+		t.Errorf("directive = %v, want NoFileComp", directive)
+	}
+}
+
+func TestCachedCompletion(t *testing.T) {
+	var calls int
+	var result []string
+	cache := newCompletionCache(time.Minute, func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		calls++
+		return result, cobra.ShellCompDirectiveNoFileComp
+	})
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+
+	complete := func(args []string, toComplete string) []string {
+		values, directive := cache.complete(&cobra.Command{}, args, toComplete)
+		if directive != cobra.ShellCompDirectiveNoFileComp {
+			t.Errorf("directive = %v, want NoFileComp", directive)
+		}
+		return values
+	}
+
+	result = []string{"web", "db"}
+	complete(nil, "")
+	if got := complete(nil, ""); !reflect.DeepEqual(got, []string{"web", "db"}) || calls != 1 {
+		t.Errorf("cached call: values = %v, calls = %d, want [web db] and 1 call", got, calls)
+	}
+
+	complete(nil, "w")
+	complete([]string{"logs"}, "")
+	if calls != 3 {
+		t.Errorf("calls = %d, want 3: args and prefix are part of the key", calls)
+	}
+
+	now = now.Add(time.Minute)
+	result = []string{"web"}
+	if got := complete(nil, ""); !reflect.DeepEqual(got, []string{"web"}) || calls != 4 {
+		t.Errorf("after TTL: values = %v, calls = %d, want [web] and 4 calls", got, calls)
+	}
+
+	// Empty results are only reused briefly
+	result = nil
+	complete(nil, "x")
+	complete(nil, "x")
+	if calls != 5 {
+		t.Errorf("calls = %d, want 5 while the empty result is fresh", calls)
+	}
+	now = now.Add(emptyCompletionTTL)
+	complete(nil, "x")
+	if calls != 6 {
+		t.Errorf("calls = %d, want 6 once the empty result expired", calls)
+	}
+}
+
+func TestCachedCompletion_SharedBetweenProcesses(t *testing.T) {
+	dir := t.TempDir()
+	var calls int
+	slow := func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		calls++
+		time.Sleep(200 * time.Millisecond)
+		return []string{"web", "db"}, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	// Each shell completion request runs in a new process with an empty
+	// in-memory cache
+	newProcess := func() *completionCache {
+		cache := newCompletionCache(time.Minute, slow)
+		cache.dir = dir
+		cache.id = "services"
+		return cache
+	}
+	cmd := &cobra.Command{Use: "logs"}
+
+	if values, _ := newProcess().complete(cmd, nil, ""); !reflect.DeepEqual(values, []string{"web", "db"}) {
+		t.Errorf("first request: values = %v, want [web db]", values)
+	}
+
+	start := time.Now()
+	values, directive := newProcess().complete(cmd, nil, "")
+	if elapsed := time.Since(start); elapsed >= 200*time.Millisecond {
+		t.Errorf("second request took %v, want it served from the cache", elapsed)
+	}
+	if !reflect.DeepEqual(values, []string{"web", "db"}) || directive != cobra.ShellCompDirectiveNoFileComp {
+		t.Errorf("second request: values = %v, directive = %v, want [web db] and NoFileComp", values, directive)
+	}
+	if calls != 1 {
+		t.Errorf("calls = %d, want 1", calls)
+	}
+
+	// Another completion function does not see these entries
+	other := newProcess()
+	other.id = "volumes"
+	other.complete(cmd, nil, "")
+	if calls != 2 {
+		t.Errorf("calls = %d, want 2 for a different completion function", calls)
+	}
+
+	// Expired entries are refreshed and pruned
+	later := newProcess()
+	now := time.Now().Add(time.Hour)
+	later.now = func() time.Time { return now }
+	later.complete(cmd, nil, "w")
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 {
+		t.Errorf("cache has %d files, want only the fresh entry", len(files))
+	}
+}
+
+func TestCachedCompletion_Concurrent(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	var calls atomic.Int32
+	fn := CachedCompletion(time.Minute, func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		calls.Add(1)
+		return []string{toComplete + "1", toComplete + "2"}, cobra.ShellCompDirectiveNoFileComp
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(prefix string) {
+			defer wg.Done()
+			values, _ := fn(&cobra.Command{}, nil, prefix)
+			if want := []string{prefix + "1", prefix + "2"}; !reflect.DeepEqual(values, want) {
+				t.Errorf("values = %v, want %v", values, want)
+			}
+		}([]string{"a", "b", "c"}[i%3])
+	}
+	wg.Wait()
+
+	if calls.Load() < 3 {
+		t.Errorf("calls = %d, want at least one per prefix", calls.Load())
+	}
+}
+
+func TestTimeoutCompletion(t *testing.T) {
+	t.Run("returns results in time", func(t *testing.T) {
+		fn := TimeoutCompletion(time.Second, StaticCompletion([]string{"web", "db"}))
+		values, directive := fn(&cobra.Command{}, nil, "")
+		if want := []string{"web", "db"}; !reflect.DeepEqual(values, want) {
+			t.Errorf("values = %v, want %v", values, want)
+		}
+		if directive != cobra.ShellCompDirectiveNoFileComp {
+			t.Errorf("directive = %v, want NoFileComp", directive)
+		}
+	})
+
+	t.Run("gives up on a blocked function", func(t *testing.T) {
+		release := make(chan struct{})
+		defer close(release)
+
+		fn := TimeoutCompletion(50*time.Millisecond, func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			<-release
+			return []string{"late"}, cobra.ShellCompDirectiveDefault
+		})
+
+		start := time.Now()
+		values, directive := fn(&cobra.Command{}, nil, "")
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Errorf("completion took %v, want it bounded by the timeout", elapsed)
+		}
+		if values != nil {
+			t.Errorf("values = %v, want none", values)
+		}
+		if directive != cobra.ShellCompDirectiveNoFileComp {
+			t.Errorf("directive = %v, want NoFileComp", directive)
+		}
+	})
+
+	t.Run("cancels the function's context", func(t *testing.T) {
+		stopped := make(chan struct{})
+		fn := TimeoutCompletion(50*time.Millisecond, func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			<-cmd.Context().Done()
+			close(stopped)
+			return nil, cobra.ShellCompDirectiveDefault
+		})
+
+		cmd := &cobra.Command{}
+		cmd.SetContext(context.Background())
+		fn(cmd, nil, "")
+
+		select {
+		case <-stopped:
+		case <-time.After(time.Second):
+			t.Fatal("completion context was not cancelled at the deadline")
+		}
+	})
+
+	t.Run("stops an exec completion", func(t *testing.T) {
+		exited := make(chan error, 1)
+		exec := CommandExecutorFunc(func(ctx context.Context, name string, args ...string) (string, error) {
+			<-ctx.Done()
+			exited <- ctx.Err()
+			return "", ctx.Err()
+		})
+
+		fn := TimeoutCompletion(50*time.Millisecond, ExecCompletion(exec, nil, "docker", "ps"))
+		fn(&cobra.Command{}, nil, "")
+
+		select {
+		case err := <-exited:
+			if !errors.Is(err, context.DeadlineExceeded) {
+				t.Errorf("exec context error = %v, want deadline exceeded", err)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("exec completion was not stopped at the deadline")
+		}
+	})
+}
+
+func TestExecCompletion(t *testing.T) {
+	var gotName string
+	var gotArgs []string
+	output, exitErr := "web\n\n  db  \nworker\n", error(nil)
+	exec := CommandExecutorFunc(func(ctx context.Context, name string, args ...string) (string, error) {
+		gotName, gotArgs = name, args
+		return output, exitErr
+	})
+
+	t.Run("offers output lines", func(t *testing.T) {
+		fn := ExecCompletion(exec, nil, "docker", "compose", "ps", "--services")
+		values, directive := fn(&cobra.Command{}, nil, "")
+		if want := []string{"web", "db", "worker"}; !reflect.DeepEqual(values, want) {
+			t.Errorf("values = %v, want %v", values, want)
+		}
+		if directive != cobra.ShellCompDirectiveNoFileComp {
+			t.Errorf("directive = %v, want NoFileComp", directive)
+		}
+		if gotName != "docker" || !reflect.DeepEqual(gotArgs, []string{"compose", "ps", "--services"}) {
+			t.Errorf("ran %s %v, want docker [compose ps --services]", gotName, gotArgs)
+		}
+	})
+
+	t.Run("custom parser", func(t *testing.T) {
+		parse := func(out string) []string {
+			var names []string
+			for _, line := range CompletionLines(out) {
+				if strings.HasPrefix(line, "w") {
+					names = append(names, line)
+				}
+			}
+			return names
+		}
+		values, _ := ExecCompletion(exec, parse, "docker")(&cobra.Command{}, nil, "")
+		if want := []string{"web", "worker"}; !reflect.DeepEqual(values, want) {
+			t.Errorf("values = %v, want %v", values, want)
+		}
+	})
+
+	t.Run("non-zero exit", func(t *testing.T) {
+		exitErr = errors.New("exit status 1")
+		defer func() { exitErr = nil }()
+
+		values, directive := ExecCompletion(exec, nil, "docker")(&cobra.Command{}, nil, "")
+		if values != nil {
+			t.Errorf("values = %v, want none", values)
+		}
+		if directive != cobra.ShellCompDirectiveNoFileComp {
+			t.Errorf("directive = %v, want NoFileComp", directive)
+		}
+	})
+}
@@ -1,5 +1,11 @@
 package sdk
 
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
 // ConfigSchema defines the configuration schema for a plugin
 type ConfigSchema struct {
 	// Name is the unique identifier for this config section
@@ -38,6 +44,17 @@ type FieldSchema struct {
 
 	// Nested fields for complex types like objects
 	Nested []FieldSchema
+
+	// Items describes each element of an array field (optional). Its Name is
+	// ignored. Arrays without Items accept elements of any type.
+	Items *FieldSchema
+	// Enum lists the allowed values (optional). Numbers match regardless of
+	// whether they decode as int or float64.
+	Enum []interface{}
+
+	// Min and Max bound numeric values, inclusive (optional)
+	Min *float64
+	Max *float64
 }
 
 // ConfigProvider is the interface plugins implement to provide configuration schema
@@ -82,33 +99,123 @@ func ValidateConfig(schema *ConfigSchema, data map[string]interface{}) []Validat
 			continue
 		}
 
-		// Type validation
-		if !validateType(field.Type, value) {
-			errors = append(errors, ValidationError{
-				Field:   field.Name,
-				Message: "invalid type: expected " + field.Type,
-			})
-		}
+		errors = append(errors, validateValue(field.Name, field, value)...)
+	}
 
+	return errors
+}
+
+// validateValue checks value against field, reporting errors under path.
+// Objects are checked against their Nested fields and arrays element by
+// element against Items.
+func validateValue(path string, field FieldSchema, value interface{}) []ValidationError {
+	if !validateType(field.Type, value) {
+		return []ValidationError{{
+			Field:   path,
+			Message: "invalid type: expected " + field.Type,
+		}}
+	}
+
+	var errors []ValidationError
+	if value != nil {
+		errors = append(errors, validateConstraints(path, field, value)...)
+	}
+
+	switch field.Type {
+	case "object":
 		// Validate nested fields for objects
-		if field.Type == "object" && len(field.Nested) > 0 {
-			if objValue, ok := value.(map[string]interface{}); ok {
-				nestedSchema := &ConfigSchema{
-					Name:   field.Name,
-					Fields: field.Nested,
-				}
-				nestedErrors := ValidateConfig(nestedSchema, objValue)
-				for _, err := range nestedErrors {
-					err.Field = field.Name + "." + err.Field
-					errors = append(errors, err)
-				}
+		if objValue, ok := value.(map[string]interface{}); ok && len(field.Nested) > 0 {
+			nestedSchema := &ConfigSchema{
+				Name:   path,
+				Fields: field.Nested,
+			}
+			nestedErrors := ValidateConfig(nestedSchema, objValue)
+			for _, err := range nestedErrors {
+				err.Field = path + "." + err.Field
+				errors = append(errors, err)
 			}
 		}
+	case "array":
+		// Validate each element against the item schema
+		if items, ok := value.([]interface{}); ok && field.Items != nil {
+			for i, item := range items {
+				errors = append(errors, validateValue(fmt.Sprintf("%s[%d]", path, i), *field.Items, item)...)
+			}
+		}
+	}
+	return errors
+}
+
+// validateConstraints checks value against the field's Enum, Min and Max
+func validateConstraints(path string, field FieldSchema, value interface{}) []ValidationError {
+	var errors []ValidationError
+
+	if len(field.Enum) > 0 && !enumContains(field.Enum, value) {
+		allowed := make([]string, len(field.Enum))
+		for i, v := range field.Enum {
+			allowed[i] = fmt.Sprint(v)
+		}
+		errors = append(errors, ValidationError{
+			Field:   path,
+			Message: fmt.Sprintf("invalid value %v: must be one of %s", value, strings.Join(allowed, ", ")),
+		})
 	}
 
+	if field.Min == nil && field.Max == nil {
+		return errors
+	}
+	n, ok := numericValue(value)
+	if !ok {
+		return errors
+	}
+	if field.Min != nil && n < *field.Min {
+		errors = append(errors, ValidationError{
+			Field:   path,
+			Message: fmt.Sprintf("value %v is below the minimum of %v", value, *field.Min),
+		})
+	}
+	if field.Max != nil && n > *field.Max {
+		errors = append(errors, ValidationError{
+			Field:   path,
+			Message: fmt.Sprintf("value %v is above the maximum of %v", value, *field.Max),
+		})
+	}
 	return errors
 }
 
+// enumContains reports whether value is one of allowed. Numbers are compared
+// by value so 3 matches 3.0.
+func enumContains(allowed []interface{}, value interface{}) bool {
+	n, isNumber := numericValue(value)
+	for _, candidate := range allowed {
+		if isNumber {
+			if c, ok := numericValue(candidate); ok && c == n {
+				return true
+			}
+			continue
+		}
+		if reflect.DeepEqual(candidate, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// numericValue returns value as a float64 if it is any integer or float type
+func numericValue(value interface{}) (float64, bool) {
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	default:
+		return 0, false
+	}
+}
+
 // validateType checks if a value matches the expected type
 func validateType(expectedType string, value interface{}) bool {
 	if value == nil {
@@ -0,0 +1,171 @@
+package sdk
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestValidateConfig_ArrayItems(t *testing.T) {
+	schema := &ConfigSchema{
+		Name: "docker",
+		Fields: []FieldSchema{
+			{Name: "compose_files", Type: "array", Items: &FieldSchema{Type: "string"}},
+			{Name: "extra", Type: "array"},
+			{
+				Name: "services",
+				Type: "array",
+				Items: &FieldSchema{
+					Type: "object",
+					Nested: []FieldSchema{
+						{Name: "name", Type: "string", Required: true},
+						{Name: "ports", Type: "array", Items: &FieldSchema{Type: "int"}},
+					},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name string
+		data map[string]interface{}
+		want []ValidationError
+	}{
+		{
+			name: "valid elements",
+			data: map[string]interface{}{
+				"compose_files": []interface{}{"a.yml", "b.yml"},
+				"services": []interface{}{
+					map[string]interface{}{"name": "web", "ports": []interface{}{80, float64(443)}},
+				},
+			},
+		},
+		{
+			name: "arrays without items accept anything",
+			data: map[string]interface{}{
+				"extra": []interface{}{1, true, "x"},
+			},
+		},
+		{
+			name: "wrong element types",
+			data: map[string]interface{}{
+				"compose_files": []interface{}{"a.yml", 1, true},
+			},
+			want: []ValidationError{
+				{Field: "compose_files[1]", Message: "invalid type: expected string"},
+				{Field: "compose_files[2]", Message: "invalid type: expected string"},
+			},
+		},
+		{
+			name: "nested objects in arrays",
+			data: map[string]interface{}{
+				"services": []interface{}{
+					map[string]interface{}{"name": "web"},
+					map[string]interface{}{"ports": []interface{}{"80"}},
+					"db",
+				},
+			},
+			want: []ValidationError{
+				{Field: "services[1].name", Message: "required field is missing"},
+				{Field: "services[1].ports[0]", Message: "invalid type: expected int"},
+				{Field: "services[2]", Message: "invalid type: expected object"},
+			},
+		},
+		{
+			name: "not an array",
+			data: map[string]interface{}{
+				"compose_files": "a.yml",
+			},
+			want: []ValidationError{
+				{Field: "compose_files", Message: "invalid type: expected array"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ValidateConfig(schema, tt.data)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ValidateConfig() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateConfig_EnumAndRange(t *testing.T) {
+	minReplicas, maxReplicas := 1.0, 10.0
+	minRatio := 0.5
+	schema := &ConfigSchema{
+		Name: "docker",
+		Fields: []FieldSchema{
+			{Name: "restart_policy", Type: "string", Enum: []interface{}{"no", "always", "on-failure"}},
+			{Name: "replicas", Type: "int", Min: &minReplicas, Max: &maxReplicas},
+			{Name: "ratio", Type: "float", Min: &minRatio},
+			{Name: "api_version", Type: "int", Enum: []interface{}{2, 3}},
+			{Name: "profiles", Type: "array", Items: &FieldSchema{Type: "string", Enum: []interface{}{"dev", "ci"}}},
+		},
+	}
+
+	tests := []struct {
+		name string
+		data map[string]interface{}
+		want []ValidationError
+	}{
+		{
+			name: "valid values",
+			data: map[string]interface{}{
+				"restart_policy": "on-failure",
+				"replicas":       10,
+				"ratio":          0.5,
+				"api_version":    float64(3),
+				"profiles":       []interface{}{"ci"},
+			},
+		},
+		{
+			name: "value outside enum",
+			data: map[string]interface{}{
+				"restart_policy": "sometimes",
+				"api_version":    4,
+				"profiles":       []interface{}{"dev", "prod"},
+			},
+			want: []ValidationError{
+				{Field: "restart_policy", Message: "invalid value sometimes: must be one of no, always, on-failure"},
+				{Field: "api_version", Message: "invalid value 4: must be one of 2, 3"},
+				{Field: "profiles[1]", Message: "invalid value prod: must be one of dev, ci"},
+			},
+		},
+		{
+			name: "values outside range",
+			data: map[string]interface{}{
+				"replicas": float64(0),
+				"ratio":    0.25,
+			},
+			want: []ValidationError{
+				{Field: "replicas", Message: "value 0 is below the minimum of 1"},
+				{Field: "ratio", Message: "value 0.25 is below the minimum of 0.5"},
+			},
+		},
+		{
+			name: "above maximum",
+			data: map[string]interface{}{"replicas": 11},
+			want: []ValidationError{
+				{Field: "replicas", Message: "value 11 is above the maximum of 10"},
+			},
+		},
+		{
+			name: "type errors skip constraints",
+			data: map[string]interface{}{"replicas": "many"},
+			want: []ValidationError{
+				{Field: "replicas", Message: "invalid type: expected int"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ValidateConfig(schema, tt.data)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ValidateConfig() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
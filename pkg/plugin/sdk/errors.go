@@ -6,12 +6,24 @@ var (
 	// ErrInvalidExtensionName is returned when an extension has an empty name
 	ErrInvalidExtensionName = errors.New("extension name cannot be empty")
 
+	// ErrDuplicateExtension is returned when an extension name is already registered
+	ErrDuplicateExtension = errors.New("extension already registered")
+
 	// ErrInvalidExecutorName is returned when an executor has an empty name
 	ErrInvalidExecutorName = errors.New("executor name cannot be empty")
 
 	// ErrInvalidCommandName is returned when a command has an empty name
 	ErrInvalidCommandName = errors.New("command name cannot be empty")
 
+	// ErrInvalidCommandDefinition is returned when a command definition fails validation
+	ErrInvalidCommandDefinition = errors.New("invalid command definition")
+
 	// ErrInvalidCompletionProvider is returned when a completion provider is invalid
 	ErrInvalidCompletionProvider = errors.New("invalid completion provider")
+
+	// ErrExtensionDetection is returned by strict detection when a context extension misbehaves
+	ErrExtensionDetection = errors.New("context extension detection failed")
+
+	// ErrIncompatibleAPIVersion is returned when a plugin targets an API version the host does not support
+	ErrIncompatibleAPIVersion = errors.New("incompatible plugin API version")
 )
@@ -0,0 +1,33 @@
+package sdk
+
+import (
+	"context"
+	"os/exec"
+)
+
+// CommandExecutor runs external commands on behalf of SDK helpers such as
+// ExecCompletion. Plugins can pass their own implementation, e.g. one that
+// targets a configured Docker host, or use OSCommandExecutor.
+type CommandExecutor interface {
+	// Output runs name with args and returns its standard output. It returns
+	// an error if the command cannot be started or exits with a non-zero
+	// status.
+	Output(ctx context.Context, name string, args ...string) (string, error)
+}
+
+// CommandExecutorFunc adapts an ordinary function to a CommandExecutor
+type CommandExecutorFunc func(ctx context.Context, name string, args ...string) (string, error)
+
+// Output calls f(ctx, name, args...)
+func (f CommandExecutorFunc) Output(ctx context.Context, name string, args ...string) (string, error) {
+	return f(ctx, name, args...)
+}
+
+// OSCommandExecutor runs commands directly with os/exec
+type OSCommandExecutor struct{}
+
+// Output runs the command and returns its standard output
+func (OSCommandExecutor) Output(ctx context.Context, name string, args ...string) (string, error) {
+	out, err := exec.CommandContext(ctx, name, args...).Output()
+	return string(out), err
+}
@@ -0,0 +1,25 @@
+package sdk
+
+import (
+	"context"
+	"os/exec"
+	"testing"
+)
+
+func TestOSCommandExecutor(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+
+	out, err := OSCommandExecutor{}.Output(context.Background(), "sh", "-c", "echo web; echo db")
+	if err != nil {
+		t.Fatalf("Output() error = %v", err)
+	}
+	if out != "web\ndb\n" {
+		t.Errorf("Output() = %q, want %q", out, "web\ndb\n")
+	}
+
+	if _, err := (OSCommandExecutor{}).Output(context.Background(), "sh", "-c", "exit 3"); err == nil {
+		t.Error("Output() error = nil for a non-zero exit")
+	}
+}
@@ -1,6 +1,14 @@
 package sdk
 
-import "context"
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
 
 // ContextExtension represents additional context data provided by a plugin
 // Plugins can contribute custom data to the project context that will be
@@ -22,6 +30,36 @@ type ContextExtension interface {
 	Merge(existing interface{}, new interface{}) (interface{}, error)
 }
 
+// DataSchemaProvider is an optional interface for context extensions that
+// declare the shape of their Detect output, so drift between a detector and
+// its consumers can be caught with `glide context --validate`
+type DataSchemaProvider interface {
+	// DataSchema describes the object returned by Detect
+	DataSchema() *ConfigSchema
+}
+
+// ValidateExtensionData checks detection output against schema. The data is
+// checked as it looks once serialized to JSON, which is how the cache and
+// other consumers see it, so a []string satisfies "array" and an int
+// satisfies "int". Nil data means the extension did not apply and is valid.
+func ValidateExtensionData(schema *ConfigSchema, data interface{}) []ValidationError {
+	if schema == nil || data == nil {
+		return nil
+	}
+
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return []ValidationError{{Field: schema.Name, Message: "cannot serialize detection result: " + err.Error()}}
+	}
+
+	var object map[string]interface{}
+	if err := json.Unmarshal(encoded, &object); err != nil {
+		return []ValidationError{{Field: schema.Name, Message: "invalid type: expected object"}}
+	}
+
+	return ValidateConfig(schema, object)
+}
+
 // ContextProvider is the interface plugins implement to contribute context extensions
 type ContextProvider interface {
 	// ProvideContext returns the context extension provided by this plugin
@@ -29,9 +67,43 @@ type ContextProvider interface {
 	ProvideContext() ContextExtension
 }
 
+// MultiContextProvider is implemented by plugins that contribute several
+// context extensions. It can be implemented alongside ContextProvider.
+type MultiContextProvider interface {
+	// ProvideContexts returns the context extensions provided by this plugin
+	ProvideContexts() []ContextExtension
+}
+
+// ProvidedExtensions returns every context extension a plugin provides through
+// ContextProvider and/or MultiContextProvider, skipping nil entries.
+func ProvidedExtensions(p interface{}) []ContextExtension {
+	var extensions []ContextExtension
+
+	if provider, ok := p.(ContextProvider); ok {
+		if ext := provider.ProvideContext(); ext != nil {
+			extensions = append(extensions, ext)
+		}
+	}
+
+	if provider, ok := p.(MultiContextProvider); ok {
+		for _, ext := range provider.ProvideContexts() {
+			if ext != nil {
+				extensions = append(extensions, ext)
+			}
+		}
+	}
+
+	return extensions
+}
+
+// DefaultDetectTimeout is a reasonable overall limit for DetectAll at CLI
+// startup; see ExtensionRegistry.SetDetectTimeout
+const DefaultDetectTimeout = 2 * time.Second
+
 // ExtensionRegistry manages registered context extensions
 type ExtensionRegistry struct {
-	extensions map[string]ContextExtension
+	extensions    map[string]ContextExtension
+	detectTimeout time.Duration
 }
 
 // NewExtensionRegistry creates a new extension registry
@@ -52,10 +124,25 @@ func (r *ExtensionRegistry) Register(ext ContextExtension) error {
 		return ErrInvalidExtensionName
 	}
 
+	if _, exists := r.extensions[name]; exists {
+		return fmt.Errorf("%w: %s", ErrDuplicateExtension, name)
+	}
+
 	r.extensions[name] = ext
 	return nil
 }
 
+// RegisterProvider registers all context extensions provided by a plugin.
+// Registration stops at the first invalid or colliding extension.
+func (r *ExtensionRegistry) RegisterProvider(p interface{}) error {
+	for _, ext := range ProvidedExtensions(p) {
+		if err := r.Register(ext); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // Get retrieves an extension by name
 func (r *ExtensionRegistry) Get(name string) (ContextExtension, bool) {
 	ext, ok := r.extensions[name]
@@ -72,26 +159,193 @@ func (r *ExtensionRegistry) All() map[string]ContextExtension {
 	return result
 }
 
-// DetectAll runs detection for all registered extensions
+// SetDetectTimeout limits how long DetectAll waits for detection overall.
+// Zero, the default, means no limit beyond the context passed to DetectAll.
+func (r *ExtensionRegistry) SetDetectTimeout(timeout time.Duration) {
+	r.detectTimeout = timeout
+}
+
+// DetectAll runs detection for all registered extensions concurrently, one
+// goroutine per extension, passing each a context that is cancelled when
+// detection is abandoned. An extension that fails or panics is left out of
+// the results without affecting the others; panics are logged. Use
+// DetectAllWithErrors to find out which extensions failed and why.
+//
+// When the detect timeout expires, the extensions still running are logged
+// and abandoned and the results gathered so far are returned without an
+// error. If ctx itself is cancelled first, the partial results are returned
+// along with ctx.Err().
 func (r *ExtensionRegistry) DetectAll(ctx context.Context, projectRoot string) (map[string]interface{}, error) {
-	results := make(map[string]interface{})
+	results, _, err := r.detectAll(ctx, projectRoot, false)
+	return results, err
+}
+
+// DetectAllWithErrors is DetectAll that also reports, by extension name, why
+// each extension without results failed: the error its Detect returned, a
+// recovered panic, or context.DeadlineExceeded if it was abandoned at the
+// detect timeout.
+func (r *ExtensionRegistry) DetectAllWithErrors(ctx context.Context, projectRoot string) (map[string]interface{}, map[string]error, error) {
+	return r.detectAll(ctx, projectRoot, false)
+}
+
+// DetectAllStrict is DetectAll for CI and tests: instead of skipping a
+// misbehaving extension it stops detection and returns an error wrapping
+// ErrExtensionDetection that names the extension. An extension misbehaves
+// when its Detect fails or panics, when its data does not match the schema
+// of a DataSchemaProvider, or when it is still running at the detect
+// timeout. The results gathered so far are returned with the error.
+func (r *ExtensionRegistry) DetectAllStrict(ctx context.Context, projectRoot string) (map[string]interface{}, error) {
+	results, _, err := r.detectAll(ctx, projectRoot, true)
+	return results, err
+}
+
+// beforeDetectWait is called by detectAll once all detectors were started,
+// with a channel closed when they have all returned. Tests replace it to
+// control the order of events.
+var beforeDetectWait = func(done <-chan struct{}) {}
+
+// detectAll implements the DetectAll variants. It returns the detected data,
+// the error of each failed extension and, for cancellation or in strict
+// mode, an overall error.
+func (r *ExtensionRegistry) detectAll(ctx context.Context, projectRoot string, strict bool) (map[string]interface{}, map[string]error, error) {
+	// cancel stops the remaining detectors, e.g. after a strict mode failure
+	detectCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	if r.detectTimeout > 0 {
+		var cancelTimeout context.CancelFunc
+		detectCtx, cancelTimeout = context.WithTimeout(detectCtx, r.detectTimeout)
+		defer cancelTimeout()
+	}
+
+	var (
+		mu       sync.Mutex
+		results  = make(map[string]interface{})
+		failures = make(map[string]error)
+		pending  = make(map[string]bool, len(r.extensions))
+		finished bool
+		failure  error
+		wg       sync.WaitGroup
+	)
+
+	for name := range r.extensions {
+		pending[name] = true
+	}
 
 	for name, ext := range r.extensions {
-		data, err := ext.Detect(ctx, projectRoot)
-		if err != nil {
-			// Continue with other extensions if one fails
-			continue
-		}
-		if data != nil {
-			results[name] = data
-		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			data, err := detectExtension(detectCtx, ext, projectRoot)
+
+			mu.Lock()
+			defer mu.Unlock()
+			// Results arriving after detection was abandoned are dropped,
+			// even if the detector ignored the cancellation
+			if finished || detectCtx.Err() != nil {
+				return
+			}
+			delete(pending, name)
+			if err == nil && strict {
+				err = validateDetectedData(ext, data)
+			}
+			if err != nil {
+				// Continue with other extensions if one fails
+				failures[name] = err
+				if strict {
+					failure = fmt.Errorf("%w: %s: %w", ErrExtensionDetection, name, err)
+					cancel()
+				}
+				return
+			}
+			if data != nil {
+				results[name] = data
+			}
+		}()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	beforeDetectWait(done)
+
+	// Both cases can be ready at once, e.g. when a strict-mode failure
+	// cancelled detection and the remaining detectors then returned, so the
+	// outcome is decided below rather than by the case that was picked
+	select {
+	case <-done:
+	case <-detectCtx.Done():
 	}
 
-	return results, nil
+	mu.Lock()
+	defer mu.Unlock()
+	finished = true
+
+	if failure != nil {
+		return results, failures, failure
+	}
+	if err := ctx.Err(); err != nil {
+		return results, failures, err
+	}
+	if len(pending) == 0 {
+		return results, failures, nil
+	}
+
+	abandoned := make([]string, 0, len(pending))
+	for name := range pending {
+		abandoned = append(abandoned, name)
+		failures[name] = fmt.Errorf("still running after %s: %w", r.detectTimeout, context.DeadlineExceeded)
+	}
+	sort.Strings(abandoned)
+	if strict {
+		return results, failures, fmt.Errorf("%w: %s: still running after %s",
+			ErrExtensionDetection, strings.Join(abandoned, ", "), r.detectTimeout)
+	}
+	Logger(ctx).Warn("Context extension detection timed out",
+		"timeout", r.detectTimeout, "abandoned", abandoned)
+	return results, failures, nil
 }
 
-// MergeExtensionData merges extension data from multiple sources
-func MergeExtensionData(extensions []ContextExtension, dataMap map[string]interface{}) (map[string]interface{}, error) {
+// validateDetectedData checks data against the extension's declared schema,
+// if it has one
+func validateDetectedData(ext ContextExtension, data interface{}) error {
+	provider, ok := ext.(DataSchemaProvider)
+	if !ok {
+		return nil
+	}
+
+	validationErrors := ValidateExtensionData(provider.DataSchema(), data)
+	if len(validationErrors) == 0 {
+		return nil
+	}
+	messages := make([]string, len(validationErrors))
+	for i, validationErr := range validationErrors {
+		messages[i] = validationErr.Error()
+	}
+	return fmt.Errorf("data does not match schema: %s", strings.Join(messages, "; "))
+}
+
+// detectExtension runs ext's detection, turning a panic into an error
+func detectExtension(ctx context.Context, ext ContextExtension, projectRoot string) (data interface{}, err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			Logger(ctx).Error("Context extension panicked during detection",
+				"extension", ext.Name(), "panic", recovered)
+			data, err = nil, fmt.Errorf("panicked: %v", recovered)
+		}
+	}()
+
+	return ext.Detect(ctx, projectRoot)
+}
+
+// MergeExtensionData merges extension data from multiple sources. When
+// several extensions share a name, map data is merged key by key with
+// DeepMerge, configured by opts or, for extensions embedding DeepMerger, by
+// its Options. Other data is combined with the extension's own Merge.
+func MergeExtensionData(extensions []ContextExtension, dataMap map[string]interface{}, opts ...MergeOption) (map[string]interface{}, error) {
 	result := make(map[string]interface{})
 
 	for _, ext := range extensions {
@@ -99,6 +353,14 @@ func MergeExtensionData(extensions []ContextExtension, dataMap map[string]interf
 		if existing, ok := result[name]; ok {
 			// Merge with existing data
 			if newData, ok := dataMap[name]; ok {
+				if isDataMap(existing) && isDataMap(newData) {
+					extOpts := opts
+					if merger, ok := ext.(mergeOptionsProvider); ok {
+						extOpts = merger.mergeOptions()
+					}
+					result[name] = DeepMerge(existing, newData, extOpts...)
+					continue
+				}
 				merged, err := ext.Merge(existing, newData)
 				if err != nil {
 					return nil, err
@@ -115,3 +377,9 @@ func MergeExtensionData(extensions []ContextExtension, dataMap map[string]interf
 
 	return result, nil
 }
+
+// isDataMap reports whether data is a map DeepMerge merges key by key
+func isDataMap(data interface{}) bool {
+	_, ok := data.(map[string]interface{})
+	return ok
+}
@@ -0,0 +1,475 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type testExtension struct {
+	name string
+	data interface{}
+}
+
+func (e *testExtension) Name() string { return e.name }
+
+func (e *testExtension) Detect(ctx context.Context, projectRoot string) (interface{}, error) {
+	return e.data, nil
+}
+
+func (e *testExtension) Merge(existing interface{}, new interface{}) (interface{}, error) {
+	return new, nil
+}
+
+// cloudPlugin provides several extensions through MultiContextProvider
+type cloudPlugin struct {
+	extensions []ContextExtension
+}
+
+func (p *cloudPlugin) ProvideContexts() []ContextExtension { return p.extensions }
+
+// legacyPlugin provides a single extension through ContextProvider
+type legacyPlugin struct {
+	extension ContextExtension
+}
+
+func (p *legacyPlugin) ProvideContext() ContextExtension { return p.extension }
+
+func TestProvidedExtensions(t *testing.T) {
+	t.Run("multi provider", func(t *testing.T) {
+		p := &cloudPlugin{extensions: []ContextExtension{
+			&testExtension{name: "aws"},
+			nil,
+			&testExtension{name: "kubernetes"},
+		}}
+
+		exts := ProvidedExtensions(p)
+		if len(exts) != 2 {
+			t.Fatalf("got %d extensions, want 2", len(exts))
+		}
+		if exts[0].Name() != "aws" || exts[1].Name() != "kubernetes" {
+			t.Errorf("unexpected extensions: %s, %s", exts[0].Name(), exts[1].Name())
+		}
+	})
+
+	t.Run("single provider", func(t *testing.T) {
+		exts := ProvidedExtensions(&legacyPlugin{extension: &testExtension{name: "docker"}})
+		if len(exts) != 1 || exts[0].Name() != "docker" {
+			t.Errorf("unexpected extensions: %v", exts)
+		}
+	})
+
+	t.Run("non provider", func(t *testing.T) {
+		if exts := ProvidedExtensions(struct{}{}); len(exts) != 0 {
+			t.Errorf("expected no extensions, got %d", len(exts))
+		}
+	})
+}
+
+func TestExtensionRegistry_RegisterProvider(t *testing.T) {
+	registry := NewExtensionRegistry()
+
+	p := &cloudPlugin{extensions: []ContextExtension{
+		&testExtension{name: "aws", data: "us-east-1"},
+		&testExtension{name: "kubernetes", data: "prod-cluster"},
+	}}
+	if err := registry.RegisterProvider(p); err != nil {
+		t.Fatalf("RegisterProvider() error = %v", err)
+	}
+
+	results, err := registry.DetectAll(context.Background(), "/project")
+	if err != nil {
+		t.Fatalf("DetectAll() error = %v", err)
+	}
+	if results["aws"] != "us-east-1" || results["kubernetes"] != "prod-cluster" {
+		t.Errorf("unexpected results: %v", results)
+	}
+
+	// A second plugin reusing an extension name is rejected
+	err = registry.RegisterProvider(&legacyPlugin{extension: &testExtension{name: "aws"}})
+	if !errors.Is(err, ErrDuplicateExtension) {
+		t.Errorf("RegisterProvider() error = %v, want ErrDuplicateExtension", err)
+	}
+}
+
+func TestValidateExtensionData(t *testing.T) {
+	schema := &ConfigSchema{
+		Name: "docker",
+		Fields: []FieldSchema{
+			{Name: "docker_running", Type: "bool", Required: true},
+			{Name: "compose_files", Type: "array"},
+			{Name: "compose_override", Type: "string"},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		data       interface{}
+		wantFields []string
+	}{
+		{
+			name: "matching map",
+			data: map[string]interface{}{"docker_running": true, "compose_files": []string{"a.yml"}},
+		},
+		{
+			name: "matching struct",
+			data: struct {
+				DockerRunning bool `json:"docker_running"`
+			}{true},
+		},
+		{
+			name: "not applicable",
+			data: nil,
+		},
+		{
+			name:       "string instead of list",
+			data:       map[string]interface{}{"docker_running": true, "compose_files": "a.yml"},
+			wantFields: []string{"compose_files"},
+		},
+		{
+			name:       "missing required",
+			data:       map[string]interface{}{"compose_override": "b.yml"},
+			wantFields: []string{"docker_running"},
+		},
+		{
+			name:       "not an object",
+			data:       []string{"a.yml"},
+			wantFields: []string{"docker"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := ValidateExtensionData(schema, tt.data)
+			if len(errs) != len(tt.wantFields) {
+				t.Fatalf("ValidateExtensionData() = %v, want errors for %v", errs, tt.wantFields)
+			}
+			for i, field := range tt.wantFields {
+				if errs[i].Field != field {
+					t.Errorf("error %d field = %q, want %q", i, errs[i].Field, field)
+				}
+			}
+		})
+	}
+}
+
+// funcExtension runs detect for detection
+type funcExtension struct {
+	name   string
+	detect func(ctx context.Context) (interface{}, error)
+}
+
+func (e *funcExtension) Name() string { return e.name }
+
+func (e *funcExtension) Detect(ctx context.Context, projectRoot string) (interface{}, error) {
+	return e.detect(ctx)
+}
+
+func (e *funcExtension) Merge(existing interface{}, new interface{}) (interface{}, error) {
+	return new, nil
+}
+
+func TestExtensionRegistry_DetectAllConcurrent(t *testing.T) {
+	registry := NewExtensionRegistry()
+
+	// Each detector waits until all of them are running, so this only
+	// finishes if they run concurrently
+	const detectors = 4
+	var started sync.WaitGroup
+	started.Add(detectors)
+	for i := 0; i < detectors; i++ {
+		name := fmt.Sprintf("ext-%d", i)
+		err := registry.Register(&funcExtension{name: name, detect: func(ctx context.Context) (interface{}, error) {
+			started.Done()
+			started.Wait()
+			return name, nil
+		}})
+		if err != nil {
+			t.Fatalf("Register() error = %v", err)
+		}
+	}
+
+	_ = registry.Register(&funcExtension{name: "broken", detect: func(ctx context.Context) (interface{}, error) {
+		return nil, errors.New("docker not installed")
+	}})
+	_ = registry.Register(&funcExtension{name: "panicky", detect: func(ctx context.Context) (interface{}, error) {
+		panic("nil map")
+	}})
+
+	results, err := registry.DetectAll(context.Background(), "/project")
+	if err != nil {
+		t.Fatalf("DetectAll() error = %v", err)
+	}
+	if len(results) != detectors {
+		t.Errorf("got %d results, want %d: %v", len(results), detectors, results)
+	}
+	for i := 0; i < detectors; i++ {
+		name := fmt.Sprintf("ext-%d", i)
+		if results[name] != name {
+			t.Errorf("results[%q] = %v, want %q", name, results[name], name)
+		}
+	}
+}
+
+func TestExtensionRegistry_DetectAllCancelled(t *testing.T) {
+	registry := NewExtensionRegistry()
+
+	release := make(chan struct{})
+	defer close(release)
+
+	_ = registry.Register(&testExtension{name: "fast", data: "ready"})
+	_ = registry.Register(&funcExtension{name: "slow", detect: func(ctx context.Context) (interface{}, error) {
+		<-release
+		return "late", nil
+	}})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	results, err := registry.DetectAll(ctx, "/project")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("DetectAll() error = %v, want context.DeadlineExceeded", err)
+	}
+	if _, ok := results["slow"]; ok {
+		t.Error("slow extension should not be in the results")
+	}
+	if results["fast"] != "ready" {
+		t.Errorf("results[fast] = %v, want ready", results["fast"])
+	}
+}
+
+func TestExtensionRegistry_DetectTimeout(t *testing.T) {
+	registry := NewExtensionRegistry()
+	registry.SetDetectTimeout(50 * time.Millisecond)
+
+	cancelled := make(chan struct{})
+	_ = registry.Register(&testExtension{name: "fast", data: "ready"})
+	_ = registry.Register(&funcExtension{name: "docker", detect: func(ctx context.Context) (interface{}, error) {
+		// Blocks until detection is abandoned, like a hung daemon check
+		<-ctx.Done()
+		close(cancelled)
+		return nil, ctx.Err()
+	}})
+	_ = registry.Register(&funcExtension{name: "stubborn", detect: func(ctx context.Context) (interface{}, error) {
+		// Returns data even though it was cancelled
+		<-ctx.Done()
+		return "stale", nil
+	}})
+
+	start := time.Now()
+	results, err := registry.DetectAll(context.Background(), "/project")
+	if err != nil {
+		t.Fatalf("DetectAll() error = %v, want partial results without error", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("DetectAll() took %v, want it to stop at the timeout", elapsed)
+	}
+
+	if len(results) != 1 || results["fast"] != "ready" {
+		t.Errorf("results = %v, want only fast", results)
+	}
+
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Error("blocked detector was not cancelled")
+	}
+}
+
+// schemaTestExtension declares the shape of its detection output
+type schemaTestExtension struct {
+	testExtension
+	schema *ConfigSchema
+}
+
+func (e *schemaTestExtension) DataSchema() *ConfigSchema { return e.schema }
+
+func TestExtensionRegistry_DetectAllStrict(t *testing.T) {
+	errDaemon := errors.New("cannot connect to the Docker daemon")
+
+	tests := []struct {
+		name      string
+		extension ContextExtension
+		timeout   time.Duration
+		// lenient is true if DetectAll still reports the extension
+		lenient bool
+		want    []string
+		wantErr error
+	}{
+		{
+			name: "detector error",
+			extension: &funcExtension{name: "docker", detect: func(ctx context.Context) (interface{}, error) {
+				return nil, errDaemon
+			}},
+			want:    []string{"docker", "cannot connect to the Docker daemon"},
+			wantErr: errDaemon,
+		},
+		{
+			name: "detector panic",
+			extension: &funcExtension{name: "docker", detect: func(ctx context.Context) (interface{}, error) {
+				panic("nil map")
+			}},
+			want: []string{"docker", "panicked: nil map"},
+		},
+		{
+			name: "data does not match schema",
+			extension: &schemaTestExtension{
+				testExtension: testExtension{name: "docker", data: map[string]interface{}{"compose_files": "a.yml"}},
+				schema: &ConfigSchema{Name: "docker", Fields: []FieldSchema{
+					{Name: "compose_files", Type: "array"},
+				}},
+			},
+			lenient: true,
+			want:    []string{"docker", "compose_files: invalid type: expected array"},
+		},
+		{
+			name: "detector still running at timeout",
+			extension: &funcExtension{name: "docker", detect: func(ctx context.Context) (interface{}, error) {
+				<-ctx.Done()
+				return nil, ctx.Err()
+			}},
+			timeout: 20 * time.Millisecond,
+			want:    []string{"docker", "still running after 20ms"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := NewExtensionRegistry()
+			registry.SetDetectTimeout(tt.timeout)
+			_ = registry.Register(&testExtension{name: "node", data: "20"})
+			_ = registry.Register(tt.extension)
+
+			// Lenient detection carries on without an error
+			results, err := registry.DetectAll(context.Background(), "/project")
+			if err != nil {
+				t.Fatalf("DetectAll() error = %v", err)
+			}
+			if _, ok := results["docker"]; ok != tt.lenient {
+				t.Errorf("DetectAll() results = %v, docker included = %v, want %v", results, ok, tt.lenient)
+			}
+
+			_, err = registry.DetectAllStrict(context.Background(), "/project")
+			if !errors.Is(err, ErrExtensionDetection) {
+				t.Fatalf("DetectAllStrict() error = %v, want ErrExtensionDetection", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("DetectAllStrict() error = %v, want it to wrap %v", err, tt.wantErr)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("DetectAllStrict() error = %q, want it to contain %q", err.Error(), want)
+				}
+			}
+		})
+	}
+
+	t.Run("well-behaved extensions", func(t *testing.T) {
+		registry := NewExtensionRegistry()
+		_ = registry.Register(&testExtension{name: "node", data: "20"})
+		_ = registry.Register(&testExtension{name: "go"})
+
+		results, err := registry.DetectAllStrict(context.Background(), "/project")
+		if err != nil {
+			t.Fatalf("DetectAllStrict() error = %v", err)
+		}
+		if len(results) != 1 || results["node"] != "20" {
+			t.Errorf("DetectAllStrict() = %v, want only node", results)
+		}
+	})
+}
+
+func TestExtensionRegistry_DetectAllStrictCancelsWithTimeout(t *testing.T) {
+	registry := NewExtensionRegistry()
+	registry.SetDetectTimeout(time.Minute)
+
+	stopped := make(chan error, 1)
+	_ = registry.Register(&funcExtension{name: "slow", detect: func(ctx context.Context) (interface{}, error) {
+		<-ctx.Done()
+		stopped <- ctx.Err()
+		return nil, ctx.Err()
+	}})
+	_ = registry.Register(&funcExtension{name: "docker", detect: func(ctx context.Context) (interface{}, error) {
+		return nil, errors.New("cannot connect to the Docker daemon")
+	}})
+
+	if _, err := registry.DetectAllStrict(context.Background(), "/project"); err == nil {
+		t.Fatal("DetectAllStrict() error = nil, want the docker failure")
+	}
+
+	// A strict failure stops the other detectors long before the timeout
+	select {
+	case err := <-stopped:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("slow detector context error = %v, want context.Canceled", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("slow detector was not cancelled")
+	}
+}
+
+func TestExtensionRegistry_DetectAllStrictFailureAfterAllReturned(t *testing.T) {
+	// Let every detector return before detectAll waits, so both the
+	// cancellation and the completion are ready when it selects
+	beforeDetectWait = func(done <-chan struct{}) { <-done }
+	defer func() { beforeDetectWait = func(done <-chan struct{}) {} }()
+
+	errDaemon := errors.New("cannot connect to the Docker daemon")
+	registry := NewExtensionRegistry()
+	_ = registry.Register(&testExtension{name: "node", data: "20"})
+	_ = registry.Register(&funcExtension{name: "docker", detect: func(ctx context.Context) (interface{}, error) {
+		return nil, errDaemon
+	}})
+
+	// select picks randomly between ready cases, so try both orders
+	for i := 0; i < 50; i++ {
+		_, err := registry.DetectAllStrict(context.Background(), "/project")
+		if !errors.Is(err, errDaemon) {
+			t.Fatalf("run %d: DetectAllStrict() error = %v, want it to wrap %v", i, err, errDaemon)
+		}
+	}
+}
+
+func TestExtensionRegistry_DetectAllWithErrors(t *testing.T) {
+	errNoSocket := errors.New("dial unix /var/run/docker.sock: no such file or directory")
+
+	registry := NewExtensionRegistry()
+	registry.SetDetectTimeout(50 * time.Millisecond)
+	_ = registry.Register(&testExtension{name: "node", data: "20"})
+	_ = registry.Register(&testExtension{name: "go"})
+	_ = registry.Register(&funcExtension{name: "docker", detect: func(ctx context.Context) (interface{}, error) {
+		return nil, errNoSocket
+	}})
+	_ = registry.Register(&funcExtension{name: "php", detect: func(ctx context.Context) (interface{}, error) {
+		panic("index out of range")
+	}})
+	_ = registry.Register(&funcExtension{name: "k8s", detect: func(ctx context.Context) (interface{}, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}})
+
+	results, failures, err := registry.DetectAllWithErrors(context.Background(), "/project")
+	if err != nil {
+		t.Fatalf("DetectAllWithErrors() error = %v", err)
+	}
+
+	if len(results) != 1 || results["node"] != "20" {
+		t.Errorf("results = %v, want only node", results)
+	}
+	if len(failures) != 3 {
+		t.Errorf("failures = %v, want docker, php and k8s", failures)
+	}
+	if !errors.Is(failures["docker"], errNoSocket) {
+		t.Errorf("failures[docker] = %v, want %v", failures["docker"], errNoSocket)
+	}
+	if err := failures["php"]; err == nil || !strings.Contains(err.Error(), "index out of range") {
+		t.Errorf("failures[php] = %v, want the panic", err)
+	}
+	if !errors.Is(failures["k8s"], context.DeadlineExceeded) {
+		t.Errorf("failures[k8s] = %v, want context.DeadlineExceeded", failures["k8s"])
+	}
+}
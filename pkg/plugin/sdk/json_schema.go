@@ -0,0 +1,135 @@
+package sdk
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// JSONSchemaDraft is the JSON Schema dialect produced by ToJSONSchema
+const JSONSchemaDraft = "http://json-schema.org/draft-07/schema#"
+
+// ToJSONSchema translates the schema into a draft-07 JSON Schema document
+// describing the plugin's configuration section. Field types, descriptions,
+// defaults, Enum, Min and Max carry over; object fields become nested
+// properties and array fields describe their Items.
+func (s *ConfigSchema) ToJSONSchema() ([]byte, error) {
+	doc := s.jsonSchema()
+	doc["$schema"] = JSONSchemaDraft
+	if s.Name != "" {
+		doc["title"] = s.Name
+	}
+	return marshalJSONSchema(doc)
+}
+
+// CombineJSONSchemas builds one draft-07 JSON Schema document for a whole
+// configuration file, with each plugin's section under its schema Name.
+// Sections whose schema is Required are listed as required.
+func CombineJSONSchemas(schemas []*ConfigSchema) ([]byte, error) {
+	properties := make(map[string]interface{}, len(schemas))
+	var required []string
+	for _, schema := range schemas {
+		if schema == nil {
+			continue
+		}
+		if schema.Name == "" {
+			return nil, fmt.Errorf("cannot combine a config schema without a name")
+		}
+		if _, ok := properties[schema.Name]; ok {
+			return nil, fmt.Errorf("config schema %q is defined more than once", schema.Name)
+		}
+		properties[schema.Name] = schema.jsonSchema()
+		if schema.Required {
+			required = append(required, schema.Name)
+		}
+	}
+
+	doc := map[string]interface{}{
+		"$schema":    JSONSchemaDraft,
+		"type":       "object",
+		"properties": properties,
+	}
+	if len(required) > 0 {
+		doc["required"] = required
+	}
+	return marshalJSONSchema(doc)
+}
+
+// jsonSchema returns the JSON Schema object for the section's fields
+func (s *ConfigSchema) jsonSchema() map[string]interface{} {
+	doc := objectJSONSchema(s.Fields)
+	if s.Description != "" {
+		doc["description"] = s.Description
+	}
+	return doc
+}
+
+// objectJSONSchema returns an object schema with a property per field
+func objectJSONSchema(fields []FieldSchema) map[string]interface{} {
+	properties := make(map[string]interface{}, len(fields))
+	var required []string
+	for _, field := range fields {
+		properties[field.Name] = fieldJSONSchema(field)
+		if field.Required {
+			required = append(required, field.Name)
+		}
+	}
+
+	doc := map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+	}
+	if len(required) > 0 {
+		doc["required"] = required
+	}
+	return doc
+}
+
+// fieldJSONSchema returns the JSON Schema for a single field
+func fieldJSONSchema(field FieldSchema) map[string]interface{} {
+	doc := make(map[string]interface{})
+	if field.Type == "object" && len(field.Nested) > 0 {
+		doc = objectJSONSchema(field.Nested)
+	} else if jsonType, ok := jsonSchemaTypes[field.Type]; ok {
+		doc["type"] = jsonType
+	}
+
+	if field.Type == "array" && field.Items != nil {
+		doc["items"] = fieldJSONSchema(*field.Items)
+	}
+	if field.Description != "" {
+		doc["description"] = field.Description
+	}
+	if field.Default != nil {
+		doc["default"] = field.Default
+	}
+	if len(field.Enum) > 0 {
+		doc["enum"] = field.Enum
+	}
+	if field.Min != nil {
+		doc["minimum"] = *field.Min
+	}
+	if field.Max != nil {
+		doc["maximum"] = *field.Max
+	}
+	return doc
+}
+
+// jsonSchemaTypes maps FieldSchema types to JSON Schema types. Fields of other
+// types are left untyped, matching ValidateConfig which accepts any value.
+var jsonSchemaTypes = map[string]string{
+	"string": "string",
+	"bool":   "boolean",
+	"int":    "integer",
+	"float":  "number",
+	"array":  "array",
+	"object": "object",
+}
+
+// marshalJSONSchema encodes a schema document
+func marshalJSONSchema(doc map[string]interface{}) ([]byte, error) {
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode JSON schema: %w", err)
+	}
+	return data, nil
+}
@@ -0,0 +1,122 @@
+package sdk
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestConfigSchema_ToJSONSchema(t *testing.T) {
+	minReplicas := 1.0
+	schema := &ConfigSchema{
+		Name:        "docker",
+		Description: "Docker settings",
+		Fields: []FieldSchema{
+			{Name: "compose_file", Type: "string", Description: "Compose file", Default: "docker-compose.yml"},
+			{Name: "restart_policy", Type: "string", Enum: []interface{}{"no", "always"}},
+			{Name: "replicas", Type: "int", Required: true, Min: &minReplicas},
+			{Name: "profiles", Type: "array", Items: &FieldSchema{Type: "string"}},
+			{
+				Name: "registry",
+				Type: "object",
+				Nested: []FieldSchema{
+					{Name: "url", Type: "string", Required: true},
+					{Name: "insecure", Type: "bool"},
+				},
+			},
+			{Name: "labels", Type: "map"},
+		},
+	}
+
+	data, err := schema.ToJSONSchema()
+	if err != nil {
+		t.Fatalf("ToJSONSchema() error = %v", err)
+	}
+
+	var got map[string]interface{}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("output is not valid JSON: %v\n%s", err, data)
+	}
+
+	want := map[string]interface{}{
+		"$schema":     JSONSchemaDraft,
+		"title":       "docker",
+		"description": "Docker settings",
+		"type":        "object",
+		"required":    []interface{}{"replicas"},
+		"properties": map[string]interface{}{
+			"compose_file": map[string]interface{}{
+				"type":        "string",
+				"description": "Compose file",
+				"default":     "docker-compose.yml",
+			},
+			"restart_policy": map[string]interface{}{
+				"type": "string",
+				"enum": []interface{}{"no", "always"},
+			},
+			"replicas": map[string]interface{}{
+				"type":    "integer",
+				"minimum": float64(1),
+			},
+			"profiles": map[string]interface{}{
+				"type":  "array",
+				"items": map[string]interface{}{"type": "string"},
+			},
+			"registry": map[string]interface{}{
+				"type":     "object",
+				"required": []interface{}{"url"},
+				"properties": map[string]interface{}{
+					"url":      map[string]interface{}{"type": "string"},
+					"insecure": map[string]interface{}{"type": "boolean"},
+				},
+			},
+			"labels": map[string]interface{}{},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ToJSONSchema() =\n%s\nwant %v", data, want)
+	}
+}
+
+func TestCombineJSONSchemas(t *testing.T) {
+	schemas := []*ConfigSchema{
+		{Name: "docker", Required: true, Fields: []FieldSchema{{Name: "host", Type: "string"}}},
+		{Name: "node", Fields: []FieldSchema{{Name: "version", Type: "string"}}},
+		nil,
+	}
+
+	data, err := CombineJSONSchemas(schemas)
+	if err != nil {
+		t.Fatalf("CombineJSONSchemas() error = %v", err)
+	}
+
+	var got struct {
+		Schema     string                            `json:"$schema"`
+		Required   []string                          `json:"required"`
+		Properties map[string]map[string]interface{} `json:"properties"`
+	}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("output is not valid JSON: %v\n%s", err, data)
+	}
+	if got.Schema != JSONSchemaDraft {
+		t.Errorf("$schema = %q, want %q", got.Schema, JSONSchemaDraft)
+	}
+	if !reflect.DeepEqual(got.Required, []string{"docker"}) {
+		t.Errorf("required = %v, want [docker]", got.Required)
+	}
+	for _, name := range []string{"docker", "node"} {
+		section, ok := got.Properties[name]
+		if !ok {
+			t.Fatalf("section %q missing:\n%s", name, data)
+		}
+		if _, nested := section["$schema"]; nested {
+			t.Errorf("section %q should not repeat $schema", name)
+		}
+	}
+
+	_, err = CombineJSONSchemas([]*ConfigSchema{{Name: "docker"}, {Name: "docker"}})
+	if err == nil || !strings.Contains(err.Error(), `"docker" is defined more than once`) {
+		t.Errorf("CombineJSONSchemas() duplicate error = %v", err)
+	}
+}
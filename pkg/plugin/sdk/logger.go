@@ -0,0 +1,43 @@
+package sdk
+
+import (
+	"context"
+
+	"github.com/glide-cli/glide/v3/pkg/logging"
+)
+
+// PluginLogKey is the log attribute that tags records with the plugin that wrote them
+const PluginLogKey = "plugin"
+
+// loggerKey is the context key for the current plugin's logger
+type loggerKey struct{}
+
+// NamedLogger derives the logger for the named plugin from base. Every
+// record it writes carries plugin=<name>, so verbose output can be
+// attributed to the plugin that produced it.
+func NamedLogger(base *logging.Logger, name string) *logging.Logger {
+	if base == nil {
+		base = logging.Default()
+	}
+	return base.With(PluginLogKey, name)
+}
+
+// WithLogger returns a copy of ctx carrying logger
+func WithLogger(ctx context.Context, logger *logging.Logger) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, loggerKey{}, logger)
+}
+
+// Logger returns the scoped logger of the plugin whose command is running.
+// Plugin commands receive it through cmd.Context(). Outside a plugin command
+// it falls back to the default logger.
+func Logger(ctx context.Context) *logging.Logger {
+	if ctx != nil {
+		if logger, ok := ctx.Value(loggerKey{}).(*logging.Logger); ok && logger != nil {
+			return logger
+		}
+	}
+	return logging.Default()
+}
@@ -0,0 +1,19 @@
+package sdk
+
+import (
+	"context"
+	"testing"
+
+	"github.com/glide-cli/glide/v3/pkg/logging"
+)
+
+func TestLogger(t *testing.T) {
+	if got := Logger(context.Background()); got != logging.Default() {
+		t.Error("Logger without a scoped logger should return the default logger")
+	}
+
+	scoped := NamedLogger(logging.Default(), "docker")
+	if got := Logger(WithLogger(context.Background(), scoped)); got != scoped {
+		t.Error("Logger should return the logger attached with WithLogger")
+	}
+}
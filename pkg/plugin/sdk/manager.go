@@ -758,6 +758,11 @@ func (d *Discoverer) scanDirectory(dir string) ([]*PluginInfo, error) {
 			continue
 		}
 
+		// Go plugins are loaded in-process, not run as plugin binaries
+		if filepath.Ext(entry.Name()) == ".so" {
+			continue
+		}
+
 		path := filepath.Join(dir, entry.Name())
 		plugins = append(plugins, &PluginInfo{
 			Name: entry.Name(),
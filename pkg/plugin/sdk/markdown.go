@@ -0,0 +1,199 @@
+package sdk
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// uncategorizedHeading is the section for commands without a Category
+const uncategorizedHeading = "Other Commands"
+
+// GenerateMarkdown renders Markdown reference documentation for defs. See
+// WriteMarkdown for the layout.
+func GenerateMarkdown(defs []*PluginCommandDefinition) string {
+	var b strings.Builder
+	// strings.Builder never returns a write error
+	_ = WriteMarkdown(&b, defs)
+	return b.String()
+}
+
+// WriteMarkdown writes Markdown reference documentation for defs to w.
+//
+// Top-level commands are grouped into one section per Category, sorted by
+// name, with uncategorized commands last. Each command is followed by its
+// subcommands and documents its usage, description, aliases, examples and
+// flags. Commands and flags are sorted by name and hidden ones are left out,
+// so the output only changes when the definitions do.
+func WriteMarkdown(w io.Writer, defs []*PluginCommandDefinition) error {
+	groups := make(map[string][]*PluginCommandDefinition)
+	for _, def := range defs {
+		if def == nil || def.Hidden {
+			continue
+		}
+		groups[def.Category] = append(groups[def.Category], def)
+	}
+
+	categories := make([]string, 0, len(groups))
+	for category := range groups {
+		if category != "" {
+			categories = append(categories, category)
+		}
+	}
+	sort.Strings(categories)
+	if _, ok := groups[""]; ok {
+		categories = append(categories, "")
+	}
+
+	mw := &markdownWriter{w: w}
+	for i, category := range categories {
+		if i > 0 {
+			mw.printf("\n")
+		}
+		heading := category
+		if heading == "" {
+			heading = uncategorizedHeading
+		}
+		mw.printf("## %s\n", heading)
+
+		for _, def := range sortedCommands(groups[category]) {
+			mw.command(def, "")
+		}
+	}
+	return mw.err
+}
+
+// markdownWriter writes Markdown to w, remembering the first write error
+type markdownWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (mw *markdownWriter) printf(format string, args ...interface{}) {
+	if mw.err != nil {
+		return
+	}
+	_, mw.err = fmt.Fprintf(mw.w, format, args...)
+}
+
+// command writes the section for def and then its subcommands
+func (mw *markdownWriter) command(def *PluginCommandDefinition, parentPath string) {
+	path := strings.TrimSpace(parentPath + " " + def.Name)
+
+	mw.printf("\n### %s\n", path)
+
+	if def.Short != "" {
+		mw.printf("\n%s\n", def.Short)
+	}
+	if def.Long != "" && def.Long != def.Short {
+		mw.printf("\n%s\n", strings.TrimSpace(def.Long))
+	}
+
+	mw.printf("\n**Usage:**\n\n```\n%s\n```\n", strings.TrimSpace(parentPath+" "+def.Use))
+
+	if len(def.Aliases) > 0 {
+		aliases := make([]string, len(def.Aliases))
+		for i, alias := range def.Aliases {
+			aliases[i] = "`" + alias + "`"
+		}
+		mw.printf("\n**Aliases:** %s\n", strings.Join(aliases, ", "))
+	}
+
+	if def.Example != "" {
+		mw.printf("\n**Examples:**\n\n```\n%s\n```\n", strings.Trim(def.Example, "\n"))
+	}
+
+	mw.flags(def.Flags)
+
+	for _, sub := range sortedCommands(def.Subcommands) {
+		if sub.Hidden {
+			continue
+		}
+		mw.command(sub, path)
+	}
+}
+
+// flags writes the flag table, if there are any visible flags
+func (mw *markdownWriter) flags(flags []FlagDefinition) {
+	visible := make([]FlagDefinition, 0, len(flags))
+	for _, flag := range flags {
+		if !flag.Hidden {
+			visible = append(visible, flag)
+		}
+	}
+	if len(visible) == 0 {
+		return
+	}
+	sort.SliceStable(visible, func(i, j int) bool {
+		return visible[i].Name < visible[j].Name
+	})
+
+	mw.printf("\n**Flags:**\n\n")
+	mw.printf("| Flag | Type | Default | Required | Description |\n")
+	mw.printf("|------|------|---------|----------|-------------|\n")
+	for _, flag := range visible {
+		name := "`--" + flag.Name + "`"
+		if flag.Shorthand != "" {
+			name = "`-" + flag.Shorthand + "`, " + name
+		}
+
+		// Build the flag the way ToCobraCommand does so defaults match --help
+		defaultValue := ""
+		scratch := &cobra.Command{}
+		addFlagToCommand(scratch, flag)
+		if f := scratch.Flags().Lookup(flag.Name); f != nil && f.DefValue != "" {
+			defaultValue = "`" + f.DefValue + "`"
+		}
+
+		required := "no"
+		if flag.Required {
+			required = "yes"
+		}
+
+		description := flag.Usage
+		if flag.EnvVar != "" {
+			description = strings.TrimSpace(fmt.Sprintf("%s (env: `%s`)", description, flag.EnvVar))
+		}
+		if flag.Deprecated != "" {
+			description = strings.TrimSpace(fmt.Sprintf("%s **Deprecated:** %s", description, flag.Deprecated))
+		}
+
+		mw.printf("| %s | %s | %s | %s | %s |\n",
+			name, markdownFlagType(flag.Type), defaultValue, required, escapeTableCell(description))
+	}
+}
+
+// sortedCommands returns the non-nil definitions in defs sorted by name
+func sortedCommands(defs []*PluginCommandDefinition) []*PluginCommandDefinition {
+	sorted := make([]*PluginCommandDefinition, 0, len(defs))
+	for _, def := range defs {
+		if def != nil {
+			sorted = append(sorted, def)
+		}
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Name < sorted[j].Name
+	})
+	return sorted
+}
+
+// markdownFlagType returns the documented name of a flag type
+func markdownFlagType(flagType string) string {
+	switch flagType {
+	case "":
+		return "string"
+	case "float":
+		return "float64"
+	default:
+		return flagType
+	}
+}
+
+// escapeTableCell keeps text from breaking out of a Markdown table cell
+func escapeTableCell(text string) string {
+	text = strings.ReplaceAll(text, "|", "\\|")
+	return strings.Join(strings.Fields(text), " ")
+}
@@ -0,0 +1,142 @@
+package sdk
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func markdownCommands() []*PluginCommandDefinition {
+	return []*PluginCommandDefinition{
+		{
+			Name:     "status",
+			Use:      "status",
+			Short:    "Show project status",
+			Category: "",
+		},
+		{
+			Name:     "compose",
+			Use:      "compose [command]",
+			Short:    "Run compose commands",
+			Long:     "Run docker compose commands against the project stack.",
+			Example:  "glide compose up -d\nglide compose ps",
+			Aliases:  []string{"c", "dc"},
+			Category: "docker",
+			Flags: []FlagDefinition{
+				{Name: "project-name", Usage: "Override the | project name", EnvVar: "COMPOSE_PROJECT_NAME"},
+				{Name: "file", Shorthand: "f", Type: "[]string", Usage: "Compose files", Required: true},
+				{Name: "internal", Type: "bool", Hidden: true},
+			},
+			Subcommands: []*PluginCommandDefinition{
+				{
+					Name:  "up",
+					Use:   "up [service...]",
+					Short: "Start services",
+					Flags: []FlagDefinition{
+						{Name: "timeout", Type: "duration", Default: "30s", Usage: "Startup timeout"},
+						{Name: "detach", Shorthand: "d", Type: "bool", Deprecated: "services always detach"},
+					},
+				},
+				{Name: "debug", Use: "debug", Hidden: true},
+			},
+		},
+		{
+			Name:     "build",
+			Use:      "build",
+			Short:    "Build images",
+			Category: "docker",
+		},
+		{
+			Name:     "deploy",
+			Use:      "deploy <env>",
+			Short:    "Deploy the project",
+			Category: "release",
+			Flags: []FlagDefinition{
+				{Name: "replicas", Type: "int", Default: 2},
+			},
+		},
+		{Name: "secret", Use: "secret", Hidden: true},
+	}
+}
+
+func TestGenerateMarkdown(t *testing.T) {
+	want := "## docker\n" +
+		"\n### build\n" +
+		"\nBuild images\n" +
+		"\n**Usage:**\n\n```\nbuild\n```\n" +
+		"\n### compose\n" +
+		"\nRun compose commands\n" +
+		"\nRun docker compose commands against the project stack.\n" +
+		"\n**Usage:**\n\n```\ncompose [command]\n```\n" +
+		"\n**Aliases:** `c`, `dc`\n" +
+		"\n**Examples:**\n\n```\nglide compose up -d\nglide compose ps\n```\n" +
+		"\n**Flags:**\n\n" +
+		"| Flag | Type | Default | Required | Description |\n" +
+		"|------|------|---------|----------|-------------|\n" +
+		"| `-f`, `--file` | []string | `[]` | yes | Compose files |\n" +
+		"| `--project-name` | string |  | no | Override the \\| project name (env: `COMPOSE_PROJECT_NAME`) |\n" +
+		"\n### compose up\n" +
+		"\nStart services\n" +
+		"\n**Usage:**\n\n```\ncompose up [service...]\n```\n" +
+		"\n**Flags:**\n\n" +
+		"| Flag | Type | Default | Required | Description |\n" +
+		"|------|------|---------|----------|-------------|\n" +
+		"| `-d`, `--detach` | bool | `false` | no | **Deprecated:** services always detach |\n" +
+		"| `--timeout` | duration | `30s` | no | Startup timeout |\n" +
+		"\n## release\n" +
+		"\n### deploy\n" +
+		"\nDeploy the project\n" +
+		"\n**Usage:**\n\n```\ndeploy <env>\n```\n" +
+		"\n**Flags:**\n\n" +
+		"| Flag | Type | Default | Required | Description |\n" +
+		"|------|------|---------|----------|-------------|\n" +
+		"| `--replicas` | int | `2` | no |  |\n" +
+		"\n## Other Commands\n" +
+		"\n### status\n" +
+		"\nShow project status\n" +
+		"\n**Usage:**\n\n```\nstatus\n```\n"
+
+	got := GenerateMarkdown(markdownCommands())
+	if got != want {
+		t.Errorf("GenerateMarkdown() mismatch\n--- got ---\n%s\n--- want ---\n%s", got, want)
+	}
+}
+
+func TestGenerateMarkdown_Deterministic(t *testing.T) {
+	defs := markdownCommands()
+	want := GenerateMarkdown(defs)
+
+	// Reverse commands and flags; the output must not change
+	reversed := make([]*PluginCommandDefinition, 0, len(defs))
+	for i := len(defs) - 1; i >= 0; i-- {
+		def := *defs[i]
+		flags := make([]FlagDefinition, 0, len(def.Flags))
+		for j := len(def.Flags) - 1; j >= 0; j-- {
+			flags = append(flags, def.Flags[j])
+		}
+		def.Flags = flags
+		reversed = append(reversed, &def)
+	}
+	reversed = append(reversed, nil)
+
+	if got := GenerateMarkdown(reversed); got != want {
+		t.Errorf("output depends on definition order\n--- got ---\n%s\n--- want ---\n%s", got, want)
+	}
+
+	if got := GenerateMarkdown(nil); got != "" {
+		t.Errorf("GenerateMarkdown(nil) = %q, want empty", got)
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+func TestWriteMarkdown_WriteError(t *testing.T) {
+	err := WriteMarkdown(failingWriter{}, markdownCommands())
+	if err == nil || !strings.Contains(err.Error(), "disk full") {
+		t.Errorf("WriteMarkdown() error = %v, want disk full", err)
+	}
+}
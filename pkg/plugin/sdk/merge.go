@@ -0,0 +1,97 @@
+package sdk
+
+// MergeOption configures DeepMerge
+type MergeOption func(*mergeOptions)
+
+type mergeOptions struct {
+	concatSlices bool
+}
+
+// WithConcatSlices makes DeepMerge append new slices to existing ones
+// instead of replacing them
+func WithConcatSlices() MergeOption {
+	return func(o *mergeOptions) {
+		o.concatSlices = true
+	}
+}
+
+// DeepMerge combines two extension values. Maps are merged key by key,
+// recursing into nested maps, so data from a source that only sets some keys
+// does not wipe the others. For any other value the new one wins, except
+// that a nil new value keeps the existing one. Slices are replaced unless
+// WithConcatSlices is given.
+//
+// Neither argument is modified; merged maps are new maps. Context extensions
+// can use DeepMerge to implement ContextExtension.Merge, or embed DeepMerger.
+func DeepMerge(existing, new interface{}, opts ...MergeOption) interface{} {
+	var options mergeOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	return deepMerge(existing, new, options)
+}
+
+// DeepMerger implements ContextExtension.Merge with DeepMerge. Embed it in
+// an extension to choose the options its map data is merged with, e.g.
+// to concatenate slices:
+//
+//	type dockerExtension struct {
+//	    sdk.DeepMerger
+//	}
+//
+//	ext := &dockerExtension{DeepMerger: sdk.DeepMerger{Options: []sdk.MergeOption{sdk.WithConcatSlices()}}}
+type DeepMerger struct {
+	// Options configure DeepMerge, e.g. WithConcatSlices()
+	Options []MergeOption
+}
+
+// Merge combines existing and new with DeepMerge
+func (m DeepMerger) Merge(existing interface{}, new interface{}) (interface{}, error) {
+	return DeepMerge(existing, new, m.Options...), nil
+}
+
+func (m DeepMerger) mergeOptions() []MergeOption {
+	return m.Options
+}
+
+// mergeOptionsProvider is implemented by extensions embedding DeepMerger
+type mergeOptionsProvider interface {
+	mergeOptions() []MergeOption
+}
+
+func deepMerge(existing, new interface{}, options mergeOptions) interface{} {
+	if new == nil {
+		return existing
+	}
+
+	switch newValue := new.(type) {
+	case map[string]interface{}:
+		existingMap, ok := existing.(map[string]interface{})
+		if !ok {
+			return newValue
+		}
+		merged := make(map[string]interface{}, len(existingMap)+len(newValue))
+		for key, value := range existingMap {
+			merged[key] = value
+		}
+		for key, value := range newValue {
+			merged[key] = deepMerge(existingMap[key], value, options)
+		}
+		return merged
+
+	case []interface{}:
+		if existingSlice, ok := existing.([]interface{}); ok && options.concatSlices {
+			return append(append([]interface{}{}, existingSlice...), newValue...)
+		}
+		return newValue
+
+	case []string:
+		if existingSlice, ok := existing.([]string); ok && options.concatSlices {
+			return append(append([]string{}, existingSlice...), newValue...)
+		}
+		return newValue
+
+	default:
+		return new
+	}
+}
@@ -0,0 +1,208 @@
+package sdk
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestDeepMerge(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing interface{}
+		new      interface{}
+		opts     []MergeOption
+		want     interface{}
+	}{
+		{
+			name:     "nil new keeps existing",
+			existing: map[string]interface{}{"docker_running": true},
+			new:      nil,
+			want:     map[string]interface{}{"docker_running": true},
+		},
+		{
+			name:     "nil existing takes new",
+			existing: nil,
+			new:      map[string]interface{}{"docker_running": true},
+			want:     map[string]interface{}{"docker_running": true},
+		},
+		{
+			name:     "partial overlap keeps other keys",
+			existing: map[string]interface{}{"compose_files": []string{"a.yml"}, "docker_running": false},
+			new:      map[string]interface{}{"docker_running": true},
+			want:     map[string]interface{}{"compose_files": []string{"a.yml"}, "docker_running": true},
+		},
+		{
+			name:     "nil values in new keep existing keys",
+			existing: map[string]interface{}{"compose_override": "override.yml"},
+			new:      map[string]interface{}{"compose_override": nil, "project": "web"},
+			want:     map[string]interface{}{"compose_override": "override.yml", "project": "web"},
+		},
+		{
+			name: "nested maps merge recursively",
+			existing: map[string]interface{}{
+				"services": map[string]interface{}{
+					"web": map[string]interface{}{"status": "running", "ports": []interface{}{80}},
+					"db":  map[string]interface{}{"status": "running"},
+				},
+			},
+			new: map[string]interface{}{
+				"services": map[string]interface{}{
+					"web":   map[string]interface{}{"status": "stopped"},
+					"cache": map[string]interface{}{"status": "running"},
+				},
+			},
+			want: map[string]interface{}{
+				"services": map[string]interface{}{
+					"web":   map[string]interface{}{"status": "stopped", "ports": []interface{}{80}},
+					"db":    map[string]interface{}{"status": "running"},
+					"cache": map[string]interface{}{"status": "running"},
+				},
+			},
+		},
+		{
+			name:     "slices are replaced by default",
+			existing: map[string]interface{}{"compose_files": []string{"a.yml"}, "ports": []interface{}{80}},
+			new:      map[string]interface{}{"compose_files": []string{"b.yml"}, "ports": []interface{}{443}},
+			want:     map[string]interface{}{"compose_files": []string{"b.yml"}, "ports": []interface{}{443}},
+		},
+		{
+			name:     "slices concatenate with option",
+			existing: map[string]interface{}{"compose_files": []string{"a.yml"}, "ports": []interface{}{80}},
+			new:      map[string]interface{}{"compose_files": []string{"b.yml"}, "ports": []interface{}{443}},
+			opts:     []MergeOption{WithConcatSlices()},
+			want:     map[string]interface{}{"compose_files": []string{"a.yml", "b.yml"}, "ports": []interface{}{80, 443}},
+		},
+		{
+			name:     "map replaces scalar",
+			existing: map[string]interface{}{"network": "bridge"},
+			new:      map[string]interface{}{"network": map[string]interface{}{"name": "bridge"}},
+			want:     map[string]interface{}{"network": map[string]interface{}{"name": "bridge"}},
+		},
+		{
+			name:     "scalars are replaced",
+			existing: "v1",
+			new:      "v2",
+			want:     "v2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DeepMerge(tt.existing, tt.new, tt.opts...)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DeepMerge() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDeepMerge_DoesNotModifyInputs(t *testing.T) {
+	existing := map[string]interface{}{
+		"nested": map[string]interface{}{"a": 1},
+		"list":   []string{"x"},
+	}
+	new := map[string]interface{}{
+		"nested": map[string]interface{}{"b": 2},
+		"list":   []string{"y"},
+	}
+
+	DeepMerge(existing, new, WithConcatSlices())
+
+	if !reflect.DeepEqual(existing["nested"], map[string]interface{}{"a": 1}) {
+		t.Errorf("existing nested map modified: %v", existing["nested"])
+	}
+	if !reflect.DeepEqual(new["nested"], map[string]interface{}{"b": 2}) {
+		t.Errorf("new nested map modified: %v", new["nested"])
+	}
+	if !reflect.DeepEqual(existing["list"], []string{"x"}) {
+		t.Errorf("existing slice modified: %v", existing["list"])
+	}
+}
+
+// mergingExtension merges its data with DeepMerger
+type mergingExtension struct {
+	DeepMerger
+	name string
+}
+
+func (e *mergingExtension) Name() string { return e.name }
+
+func (e *mergingExtension) Detect(ctx context.Context, projectRoot string) (interface{}, error) {
+	return nil, nil
+}
+
+func TestMergeExtensionData_DeepMerger(t *testing.T) {
+	concat := DeepMerger{Options: []MergeOption{WithConcatSlices()}}
+	extensions := []ContextExtension{
+		&mergingExtension{name: "docker", DeepMerger: concat},
+		&mergingExtension{name: "docker", DeepMerger: concat},
+		&testExtension{name: "node"},
+	}
+	dataMap := map[string]interface{}{
+		"docker": map[string]interface{}{"compose_files": []string{"a.yml"}},
+		"node":   "20",
+	}
+
+	result, err := MergeExtensionData(extensions, dataMap)
+	if err != nil {
+		t.Fatalf("MergeExtensionData() error = %v", err)
+	}
+
+	want := map[string]interface{}{
+		"docker": map[string]interface{}{"compose_files": []string{"a.yml", "a.yml"}},
+		"node":   "20",
+	}
+	if !reflect.DeepEqual(result, want) {
+		t.Errorf("MergeExtensionData() = %v, want %v", result, want)
+	}
+}
+
+func TestMergeExtensionData_DeepMergesMaps(t *testing.T) {
+	// testExtension's own Merge replaces the data, so concatenated slices
+	// show that map data went through DeepMerge
+	extensions := []ContextExtension{
+		&testExtension{name: "docker"},
+		&testExtension{name: "docker"},
+	}
+	dataMap := map[string]interface{}{
+		"docker": map[string]interface{}{"compose_files": []string{"a.yml"}},
+	}
+
+	result, err := MergeExtensionData(extensions, dataMap, WithConcatSlices())
+	if err != nil {
+		t.Fatalf("MergeExtensionData() error = %v", err)
+	}
+
+	want := map[string]interface{}{"compose_files": []string{"a.yml", "a.yml"}}
+	if !reflect.DeepEqual(result["docker"], want) {
+		t.Errorf("MergeExtensionData() = %v, want %v", result["docker"], want)
+	}
+}
+
+// countingExtension counts the calls to its Merge
+type countingExtension struct {
+	testExtension
+	merges *int
+}
+
+func (e *countingExtension) Merge(existing interface{}, new interface{}) (interface{}, error) {
+	*e.merges++
+	return new, nil
+}
+
+func TestMergeExtensionData_UsesExtensionMergeForOtherData(t *testing.T) {
+	var merges int
+	extensions := []ContextExtension{
+		&countingExtension{testExtension{name: "node"}, &merges},
+		&countingExtension{testExtension{name: "node"}, &merges},
+	}
+
+	result, err := MergeExtensionData(extensions, map[string]interface{}{"node": "20"})
+	if err != nil {
+		t.Fatalf("MergeExtensionData() error = %v", err)
+	}
+	if result["node"] != "20" || merges != 1 {
+		t.Errorf("MergeExtensionData() = %v after %d merges, want 20 after 1", result["node"], merges)
+	}
+}
@@ -0,0 +1,76 @@
+package sdk
+
+import (
+	"io"
+
+	"github.com/glide-cli/glide/v3/pkg/output"
+	"github.com/spf13/cobra"
+)
+
+// TextRenderer is implemented by command results that have a human-readable
+// form. Results that don't implement it are displayed with the table/plain
+// formatter in text mode.
+type TextRenderer interface {
+	// RenderText writes the result for humans to w
+	RenderText(w io.Writer) error
+}
+
+// OutputFormat returns the output format selected with the global --format
+// flag (also accepted as --output) for cmd, or table if it isn't set
+func OutputFormat(cmd *cobra.Command) output.Format {
+	flag := cmd.Flag("format")
+	if flag == nil {
+		return output.FormatTable
+	}
+
+	format, err := output.ParseFormat(flag.Value.String())
+	if err != nil {
+		return output.FormatTable
+	}
+	return format
+}
+
+// WriteResult writes a structured command result to cmd's output in the
+// format selected by the user. Plugin commands should build a result value
+// and pass it here instead of formatting JSON themselves, so that every
+// command renders machine output the same way.
+//
+// Example:
+//
+//	type psResult struct {
+//	    Containers []Container `json:"containers" yaml:"containers"`
+//	}
+//
+//	func (r psResult) RenderText(w io.Writer) error { ... }
+//
+//	RunE: func(cmd *cobra.Command, args []string) error {
+//	    return sdk.WriteResult(cmd, psResult{Containers: containers})
+//	}
+func WriteResult(cmd *cobra.Command, result interface{}) error {
+	w := cmd.OutOrStdout()
+	format := OutputFormat(cmd)
+
+	switch format {
+	case output.FormatJSON, output.FormatYAML:
+		formatter, err := output.CreateFormatter(format, w, true, false)
+		if err != nil {
+			return err
+		}
+		return formatter.Display(result)
+	}
+
+	if renderer, ok := result.(TextRenderer); ok {
+		return renderer.RenderText(w)
+	}
+
+	noColor := false
+	if flag := cmd.Flag("no-color"); flag != nil {
+		noColor = flag.Value.String() == "true"
+	}
+
+	formatter, err := output.CreateFormatter(format, w, noColor, false)
+	if err != nil {
+		return err
+	}
+	return formatter.Display(result)
+}
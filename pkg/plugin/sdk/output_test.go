@@ -0,0 +1,87 @@
+package sdk
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/glide-cli/glide/v3/pkg/output"
+	"github.com/spf13/cobra"
+)
+
+type portsResult struct {
+	Ports []string `json:"ports" yaml:"ports"`
+}
+
+func (r portsResult) RenderText(w io.Writer) error {
+	for _, p := range r.Ports {
+		if _, err := fmt.Fprintln(w, p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runWithFormat executes a subcommand that writes result, with the global
+// format flags defined on its parent
+func runWithFormat(t *testing.T, result interface{}, args ...string) string {
+	t.Helper()
+
+	var format string
+	root := &cobra.Command{Use: "glide"}
+	root.PersistentFlags().StringVar(&format, "format", "table", "")
+	root.PersistentFlags().StringVar(&format, "output", "table", "")
+	root.PersistentFlags().Bool("no-color", false, "")
+
+	root.AddCommand(&cobra.Command{
+		Use: "ports",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return WriteResult(cmd, result)
+		},
+	})
+
+	var out bytes.Buffer
+	root.SetOut(&out)
+	root.SetArgs(append([]string{"ports"}, args...))
+	if err := root.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	return out.String()
+}
+
+func TestWriteResult_Text(t *testing.T) {
+	got := runWithFormat(t, portsResult{Ports: []string{"80/tcp", "443/tcp"}})
+	if got != "80/tcp\n443/tcp\n" {
+		t.Errorf("text output = %q", got)
+	}
+}
+
+func TestWriteResult_JSON(t *testing.T) {
+	for _, flag := range []string{"--format=json", "--output=json"} {
+		t.Run(flag, func(t *testing.T) {
+			got := runWithFormat(t, portsResult{Ports: []string{"80/tcp"}}, flag)
+
+			var decoded portsResult
+			if err := json.Unmarshal([]byte(got), &decoded); err != nil {
+				t.Fatalf("output is not JSON: %v\n%s", err, got)
+			}
+			if len(decoded.Ports) != 1 || decoded.Ports[0] != "80/tcp" {
+				t.Errorf("decoded = %+v", decoded)
+			}
+		})
+	}
+}
+
+func TestOutputFormat(t *testing.T) {
+	cmd := &cobra.Command{Use: "standalone"}
+	if got := OutputFormat(cmd); got != output.FormatTable {
+		t.Errorf("OutputFormat() without flag = %s, want table", got)
+	}
+
+	cmd.Flags().String("format", "text", "")
+	if got := OutputFormat(cmd); got != output.FormatPlain {
+		t.Errorf("OutputFormat() = %s, want plain", got)
+	}
+}
@@ -0,0 +1,147 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"time"
+)
+
+// ErrRetryExhausted is returned (wrapped) when a retry policy gives up
+var ErrRetryExhausted = errors.New("retry attempts exhausted")
+
+// RetryPolicy configures Retry
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of calls (0 means unlimited; MaxDuration should then be set)
+	MaxAttempts int
+
+	// MaxDuration bounds the total time spent retrying (0 means unlimited)
+	MaxDuration time.Duration
+
+	// InitialInterval is the delay before the second attempt
+	InitialInterval time.Duration
+
+	// MaxInterval caps the delay between attempts (0 means uncapped)
+	MaxInterval time.Duration
+
+	// Multiplier scales the delay after each failed attempt (values below 1 are treated as 1)
+	Multiplier float64
+
+	// Jitter randomizes each delay by up to this fraction, e.g. 0.2 for ±20%
+	Jitter float64
+}
+
+// DefaultRetryPolicy returns sensible defaults for retrying external tools
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts:     5,
+		MaxDuration:     time.Minute,
+		InitialInterval: 500 * time.Millisecond,
+		MaxInterval:     10 * time.Second,
+		Multiplier:      2,
+		Jitter:          0.2,
+	}
+}
+
+// permanentError marks an error that should not be retried
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent wraps err so that Retry returns it immediately instead of retrying
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// Retry calls fn until it succeeds, the policy is exhausted, or ctx is done.
+//
+// Delays grow exponentially from InitialInterval by Multiplier, capped at
+// MaxInterval, with random jitter applied. Cancellation of ctx interrupts any
+// pending backoff. Errors wrapped with Permanent stop retrying immediately.
+// A nil policy uses DefaultRetryPolicy.
+//
+// Example:
+//
+//	err := sdk.Retry(ctx, nil, func() error {
+//	    return exec.CommandContext(ctx, "docker", "pull", image).Run()
+//	})
+//	if errors.Is(err, sdk.ErrRetryExhausted) {
+//	    // every attempt failed
+//	}
+func Retry(ctx context.Context, policy *RetryPolicy, fn func() error) error {
+	if policy == nil {
+		policy = DefaultRetryPolicy()
+	}
+
+	start := time.Now()
+	delay := policy.InitialInterval
+
+	for attempt := 1; ; attempt++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		err := fn()
+		if err == nil {
+			return nil
+		}
+
+		var perm *permanentError
+		if errors.As(err, &perm) {
+			return perm.err
+		}
+
+		if policy.MaxAttempts > 0 && attempt >= policy.MaxAttempts {
+			return fmt.Errorf("%w after %d attempts: %w", ErrRetryExhausted, attempt, err)
+		}
+
+		wait := applyJitter(delay, policy.Jitter)
+		if policy.MaxDuration > 0 && time.Since(start)+wait > policy.MaxDuration {
+			return fmt.Errorf("%w after %d attempts (%s limit): %w", ErrRetryExhausted, attempt, policy.MaxDuration, err)
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("retry cancelled after %d attempts: %w (last error: %v)", attempt, ctx.Err(), err)
+		case <-timer.C:
+		}
+
+		delay = nextRetryInterval(delay, policy)
+	}
+}
+
+// nextRetryInterval computes the next backoff delay
+func nextRetryInterval(current time.Duration, policy *RetryPolicy) time.Duration {
+	multiplier := policy.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+
+	next := time.Duration(float64(current) * multiplier)
+	if policy.MaxInterval > 0 && next > policy.MaxInterval {
+		next = policy.MaxInterval
+	}
+	return next
+}
+
+// applyJitter randomizes delay by up to ±fraction
+func applyJitter(delay time.Duration, fraction float64) time.Duration {
+	if fraction <= 0 || delay <= 0 {
+		return delay
+	}
+	if fraction > 1 {
+		fraction = 1
+	}
+
+	offset := (rand.Float64()*2 - 1) * fraction * float64(delay)
+	return delay + time.Duration(offset)
+}
@@ -0,0 +1,147 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func fastRetryPolicy(maxAttempts int) *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts:     maxAttempts,
+		InitialInterval: time.Millisecond,
+		MaxInterval:     5 * time.Millisecond,
+		Multiplier:      2,
+	}
+}
+
+func TestRetry_SucceedsOnNthAttempt(t *testing.T) {
+	calls := 0
+	err := Retry(context.Background(), fastRetryPolicy(5), func() error {
+		calls++
+		if calls < 3 {
+			return errors.New("not yet")
+		}
+		return nil
+	})
+
+	if err != nil {
+		t.Fatalf("Retry() error = %v, want nil", err)
+	}
+	if calls != 3 {
+		t.Errorf("calls = %d, want 3", calls)
+	}
+}
+
+func TestRetry_ExhaustsAttempts(t *testing.T) {
+	lastErr := errors.New("still failing")
+	calls := 0
+	err := Retry(context.Background(), fastRetryPolicy(4), func() error {
+		calls++
+		return lastErr
+	})
+
+	if calls != 4 {
+		t.Errorf("calls = %d, want 4", calls)
+	}
+	if !errors.Is(err, ErrRetryExhausted) {
+		t.Errorf("error should wrap ErrRetryExhausted, got %v", err)
+	}
+	if !errors.Is(err, lastErr) {
+		t.Errorf("error should wrap the last attempt error, got %v", err)
+	}
+}
+
+func TestRetry_MaxDuration(t *testing.T) {
+	policy := &RetryPolicy{
+		MaxDuration:     20 * time.Millisecond,
+		InitialInterval: 15 * time.Millisecond,
+		Multiplier:      1,
+	}
+
+	calls := 0
+	err := Retry(context.Background(), policy, func() error {
+		calls++
+		return errors.New("failing")
+	})
+
+	if !errors.Is(err, ErrRetryExhausted) {
+		t.Errorf("error should wrap ErrRetryExhausted, got %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("calls = %d, want 2", calls)
+	}
+}
+
+func TestRetry_PermanentError(t *testing.T) {
+	permErr := errors.New("bad credentials")
+	calls := 0
+	err := Retry(context.Background(), fastRetryPolicy(5), func() error {
+		calls++
+		return Permanent(permErr)
+	})
+
+	if calls != 1 {
+		t.Errorf("calls = %d, want 1", calls)
+	}
+	if err != permErr {
+		t.Errorf("Retry() error = %v, want %v", err, permErr)
+	}
+}
+
+func TestRetry_CancelledDuringBackoff(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	policy := &RetryPolicy{
+		MaxAttempts:     10,
+		InitialInterval: time.Hour,
+	}
+
+	calls := 0
+	done := make(chan error, 1)
+	go func() {
+		done <- Retry(ctx, policy, func() error {
+			calls++
+			return errors.New("failing")
+		})
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("error should wrap context.Canceled, got %v", err)
+		}
+		if calls != 1 {
+			t.Errorf("calls = %d, want 1", calls)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Retry did not return after cancellation")
+	}
+}
+
+func TestNextRetryInterval(t *testing.T) {
+	policy := &RetryPolicy{Multiplier: 2, MaxInterval: 300 * time.Millisecond}
+
+	if got := nextRetryInterval(100*time.Millisecond, policy); got != 200*time.Millisecond {
+		t.Errorf("nextRetryInterval() = %v, want 200ms", got)
+	}
+	if got := nextRetryInterval(200*time.Millisecond, policy); got != 300*time.Millisecond {
+		t.Errorf("nextRetryInterval() = %v, want capped 300ms", got)
+	}
+}
+
+func TestApplyJitter(t *testing.T) {
+	base := 100 * time.Millisecond
+	for i := 0; i < 100; i++ {
+		got := applyJitter(base, 0.2)
+		if got < 80*time.Millisecond || got > 120*time.Millisecond {
+			t.Fatalf("applyJitter() = %v, want within ±20%% of %v", got, base)
+		}
+	}
+	if got := applyJitter(base, 0); got != base {
+		t.Errorf("applyJitter() with no jitter = %v, want %v", got, base)
+	}
+}
@@ -0,0 +1,90 @@
+package sdk
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// ShellEnv holds shell aliases and environment variables a plugin contributes
+// to the user's interactive shell via `glide shellenv`
+type ShellEnv struct {
+	// Aliases maps alias names to the command they expand to
+	// Example: {"dc": "glide docker"}
+	Aliases map[string]string
+
+	// Env maps environment variable names to values
+	Env map[string]string
+}
+
+// ShellEnvProvider is an optional interface for plugins that contribute shell
+// aliases or environment variables. Output is only ever printed for the user
+// to eval; nothing is applied automatically.
+type ShellEnvProvider interface {
+	// ProvideShellEnv returns the plugin's shell setup, or nil for none
+	ProvideShellEnv() *ShellEnv
+}
+
+var (
+	shellAliasNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.-]*$`)
+	shellEnvNamePattern   = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+)
+
+// Validate checks that all alias and variable names are safe to emit unquoted
+func (e *ShellEnv) Validate() error {
+	if e == nil {
+		return nil
+	}
+	for name := range e.Aliases {
+		if !shellAliasNamePattern.MatchString(name) {
+			return fmt.Errorf("invalid shell alias name %q", name)
+		}
+	}
+	for name := range e.Env {
+		if !shellEnvNamePattern.MatchString(name) {
+			return fmt.Errorf("invalid environment variable name %q", name)
+		}
+	}
+	return nil
+}
+
+// IsEmpty reports whether the ShellEnv contributes nothing
+func (e *ShellEnv) IsEmpty() bool {
+	return e == nil || (len(e.Aliases) == 0 && len(e.Env) == 0)
+}
+
+// Render returns POSIX shell statements for the aliases and variables, sorted
+// by name. Values are single-quoted so the output is safe to eval.
+func (e *ShellEnv) Render() (string, error) {
+	if err := e.Validate(); err != nil {
+		return "", err
+	}
+	if e.IsEmpty() {
+		return "", nil
+	}
+
+	var sb strings.Builder
+	for _, name := range sortedShellKeys(e.Env) {
+		fmt.Fprintf(&sb, "export %s=%s\n", name, ShellQuote(e.Env[name]))
+	}
+	for _, name := range sortedShellKeys(e.Aliases) {
+		fmt.Fprintf(&sb, "alias %s=%s\n", name, ShellQuote(e.Aliases[name]))
+	}
+	return sb.String(), nil
+}
+
+// ShellQuote single-quotes s for POSIX shells, escaping embedded single quotes
+func ShellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// sortedShellKeys returns the keys of m in sorted order
+func sortedShellKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
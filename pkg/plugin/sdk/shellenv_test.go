@@ -0,0 +1,87 @@
+package sdk
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestShellQuote(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"glide docker", `'glide docker'`},
+		{"", `''`},
+		{"it's", `'it'\''s'`},
+		{"$(rm -rf /); `x`", "'$(rm -rf /); `x`'"},
+	}
+
+	for _, tt := range tests {
+		if got := ShellQuote(tt.in); got != tt.want {
+			t.Errorf("ShellQuote(%q) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestShellEnv_Render(t *testing.T) {
+	env := &ShellEnv{
+		Aliases: map[string]string{"dc": "glide docker", "dcu": "glide docker compose up"},
+		Env:     map[string]string{"COMPOSE_PROJECT_NAME": "my app"},
+	}
+
+	got, err := env.Render()
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	want := "export COMPOSE_PROJECT_NAME='my app'\n" +
+		"alias dc='glide docker'\n" +
+		"alias dcu='glide docker compose up'\n"
+	if got != want {
+		t.Errorf("Render() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestShellEnv_RenderInvalidNames(t *testing.T) {
+	tests := []*ShellEnv{
+		{Aliases: map[string]string{"dc; rm -rf ~": "x"}},
+		{Env: map[string]string{"BAD-NAME": "x"}},
+		{Env: map[string]string{"1ABC": "x"}},
+	}
+
+	for _, env := range tests {
+		if _, err := env.Render(); err == nil {
+			t.Errorf("Render(%v) expected error", env)
+		}
+	}
+}
+
+func TestShellEnv_RenderNil(t *testing.T) {
+	var env *ShellEnv
+	got, err := env.Render()
+	if err != nil || got != "" {
+		t.Errorf("Render() on nil = %q, %v; want empty, nil", got, err)
+	}
+}
+
+func TestShellEnv_RenderRoundTrip(t *testing.T) {
+	sh, err := exec.LookPath("sh")
+	if err != nil {
+		t.Skip("sh not available")
+	}
+
+	value := `it's "quoted" $HOME $(echo nope) ` + "`echo nope`\\"
+	snippet, err := (&ShellEnv{Env: map[string]string{"GLIDE_TEST_VALUE": value}}).Render()
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	out, err := exec.Command(sh, "-c", snippet+`printf '%s' "$GLIDE_TEST_VALUE"`).Output()
+	if err != nil {
+		t.Fatalf("sh failed: %v", err)
+	}
+	if got := strings.TrimSuffix(string(out), "\n"); got != value {
+		t.Errorf("evaluated value = %q, want %q", got, value)
+	}
+}
@@ -0,0 +1,121 @@
+package plugin
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	goplugin "plugin"
+	"sort"
+
+	"github.com/glide-cli/glide/v3/pkg/logging"
+	"github.com/glide-cli/glide/v3/pkg/plugin/sdk"
+)
+
+// SharedPluginExt is the file extension of Go plugins loaded by LoadSharedPlugins
+const SharedPluginExt = ".so"
+
+// SharedPluginSymbol is the function a Go plugin must export. Its signature
+// must be func() Plugin.
+const SharedPluginSymbol = "NewPlugin"
+
+// LoadSharedPlugins opens every Go plugin (.so) in dir, calls its exported
+// NewPlugin function and registers the returned plugin, so LoadAll picks it
+// up with the built-in plugins. Other files are ignored and a missing dir is
+// not an error.
+//
+// Each file must first pass the same checks as runtime plugin binaries
+// (sdk.Validator in strict mode): it must be an executable in dir, not
+// writable by group or others, and in a recognized binary format. Files that
+// fail are reported and never opened, since opening runs their code inside
+// glide.
+//
+// Go plugins only load into a binary built with the same Go version and the
+// same versions of every shared package. A file that fails to load, for
+// example because it was built against another glide release, is reported
+// in the result and does not stop the remaining files from loading.
+func (r *Registry) LoadSharedPlugins(dir string) *PluginLoadResult {
+	result := &PluginLoadResult{
+		Loaded:   make([]string, 0),
+		Failed:   make([]PluginError, 0),
+		Warnings: make([]string, 0),
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("cannot read plugin directory %s: %v", dir, err))
+		}
+		return result
+	}
+
+	var files []string
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != SharedPluginExt {
+			continue
+		}
+		files = append(files, entry.Name())
+	}
+	sort.Strings(files)
+
+	validator := sdk.NewValidator(true)
+	validator.AddTrustedPath(dir)
+
+	for _, file := range files {
+		path := filepath.Join(dir, file)
+		logging.Debug("Loading shared plugin", "path", path)
+
+		var p Plugin
+		err := validator.Validate(path)
+		if err != nil {
+			err = fmt.Errorf("plugin validation failed: %w", err)
+		} else {
+			p, err = openSharedPlugin(path)
+		}
+		if err == nil {
+			err = r.RegisterPlugin(p)
+		}
+		if err != nil {
+			logging.Warn("Shared plugin failed to load", "path", path, "error", err)
+			result.Failed = append(result.Failed, PluginError{
+				Name:    file,
+				Error:   err,
+				IsFatal: false,
+			})
+			continue
+		}
+
+		result.Loaded = append(result.Loaded, p.Name())
+	}
+
+	return result
+}
+
+// openSharedPlugin opens the Go plugin at path and creates its Plugin
+func openSharedPlugin(path string) (Plugin, error) {
+	lib, err := goplugin.Open(path)
+	if err != nil {
+		// Version skew between the plugin and this binary surfaces here
+		return nil, fmt.Errorf("failed to open: %w", err)
+	}
+
+	sym, err := lib.Lookup(SharedPluginSymbol)
+	if err != nil {
+		return nil, fmt.Errorf("missing %s function: %w", SharedPluginSymbol, err)
+	}
+
+	newPlugin, ok := sym.(func() Plugin)
+	if !ok {
+		return nil, fmt.Errorf("%s has type %T, want func() Plugin", SharedPluginSymbol, sym)
+	}
+
+	p := newPlugin()
+	if p == nil {
+		return nil, fmt.Errorf("%s returned a nil plugin", SharedPluginSymbol)
+	}
+	return p, nil
+}
+
+// LoadSharedPlugins loads Go plugins from dir into the global registry
+func LoadSharedPlugins(dir string) *PluginLoadResult {
+	return globalRegistry.LoadSharedPlugins(dir)
+}
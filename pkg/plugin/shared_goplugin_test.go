@@ -0,0 +1,43 @@
+//go:build goplugin
+
+// This test builds a real Go plugin and needs cgo. Run it with:
+//
+//	go test -tags goplugin -run TestLoadSharedPlugins_GoPlugin ./pkg/plugin
+
+package plugin_test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadSharedPlugins_GoPlugin(t *testing.T) {
+	dir := t.TempDir()
+	pluginDir := filepath.Join(dir, "plugins")
+	require.NoError(t, os.Mkdir(pluginDir, 0755))
+
+	// The test binary compiles this package with its tests, which a Go
+	// plugin refuses to load into, so a separate host program loads it
+	build := func(args ...string) {
+		cmd := exec.Command("go", append([]string{"build"}, args...)...)
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+	build("-buildmode=plugin", "-o", filepath.Join(pluginDir, "example.so"), "./testdata/sharedplugin")
+	build("-o", filepath.Join(dir, "host"), "./testdata/sharedhost")
+	require.NoError(t, os.WriteFile(filepath.Join(pluginDir, "broken.so"), []byte("not a plugin"), 0644))
+
+	out, err := exec.Command(filepath.Join(dir, "host"), pluginDir).Output()
+	require.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	require.Len(t, lines, 2)
+	assert.Equal(t, "loaded shared-example", lines[0])
+	assert.True(t, strings.HasPrefix(lines[1], "failed broken.so: "), lines[1])
+}
@@ -0,0 +1,67 @@
+package plugin_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/glide-cli/glide/v3/pkg/plugin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadSharedPlugins(t *testing.T) {
+	t.Run("missing directory", func(t *testing.T) {
+		reg := plugin.NewRegistry()
+		result := reg.LoadSharedPlugins(filepath.Join(t.TempDir(), "missing"))
+		assert.Empty(t, result.Loaded)
+		assert.Empty(t, result.Failed)
+		assert.Empty(t, result.Warnings)
+	})
+
+	t.Run("bad files fail individually", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "a.so"), []byte("not a plugin"), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "b.so"), []byte("not a plugin"), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "readme.txt"), []byte("ignored"), 0644))
+		require.NoError(t, os.Mkdir(filepath.Join(dir, "dir.so"), 0755))
+
+		reg := plugin.NewRegistry()
+		result := reg.LoadSharedPlugins(dir)
+		assert.Empty(t, result.Loaded)
+		require.Len(t, result.Failed, 2)
+		assert.Equal(t, "a.so", result.Failed[0].Name)
+		assert.Equal(t, "b.so", result.Failed[1].Name)
+		assert.False(t, result.HasFatalErrors())
+		assert.Contains(t, result.Failed[0].Error.Error(), "invalid plugin binary format")
+		assert.Empty(t, reg.List())
+	})
+
+	t.Run("files are validated before they are opened", func(t *testing.T) {
+		dir := t.TempDir()
+		// An ELF header gets past the format check, so only the
+		// permissions decide
+		elf := []byte("\x7fELF not really a plugin")
+		for name, mode := range map[string]os.FileMode{
+			"group-writable.so": 0775,
+			"world-writable.so": 0757,
+			"not-executable.so": 0644,
+			"ok.so":             0755,
+		} {
+			path := filepath.Join(dir, name)
+			require.NoError(t, os.WriteFile(path, elf, mode))
+			require.NoError(t, os.Chmod(path, mode))
+		}
+
+		result := plugin.NewRegistry().LoadSharedPlugins(dir)
+		require.Len(t, result.Failed, 4)
+		errs := make(map[string]string)
+		for _, failed := range result.Failed {
+			errs[failed.Name] = failed.Error.Error()
+		}
+		assert.Contains(t, errs["group-writable.so"], "plugin validation failed")
+		assert.Contains(t, errs["world-writable.so"], "plugin validation failed")
+		assert.Contains(t, errs["not-executable.so"], "plugin validation failed")
+		assert.Contains(t, errs["ok.so"], "failed to open", "valid files are opened")
+	})
+}
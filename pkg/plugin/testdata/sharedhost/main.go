@@ -0,0 +1,22 @@
+// Command sharedhost loads the Go plugins in a directory and prints the
+// outcome, one line per plugin, for the shared plugin loader test.
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/glide-cli/glide/v3/pkg/plugin"
+)
+
+func main() {
+	reg := plugin.NewRegistry()
+	result := reg.LoadSharedPlugins(os.Args[1])
+
+	for _, name := range result.Loaded {
+		fmt.Printf("loaded %s\n", name)
+	}
+	for _, failure := range result.Failed {
+		fmt.Printf("failed %s: %v\n", failure.Name, failure.Error)
+	}
+}
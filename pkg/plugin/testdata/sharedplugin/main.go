@@ -0,0 +1,15 @@
+// Command sharedplugin is a Go plugin used by the shared plugin loader test.
+// Build it with -buildmode=plugin.
+package main
+
+import (
+	"github.com/glide-cli/glide/v3/pkg/plugin"
+	"github.com/glide-cli/glide/v3/pkg/plugin/plugintest"
+)
+
+// NewPlugin is looked up by plugin.LoadSharedPlugins
+func NewPlugin() plugin.Plugin {
+	return plugintest.NewMockPlugin("shared-example")
+}
+
+func main() {}